@@ -0,0 +1,174 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: cometbft/services/light_block/v1/light_block_service.proto
+
+package v1
+
+import (
+	context "context"
+	fmt "fmt"
+	grpc1 "github.com/cosmos/gogoproto/grpc"
+	proto "github.com/cosmos/gogoproto/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+func init() {
+	proto.RegisterFile("cometbft/services/light_block/v1/light_block_service.proto", fileDescriptor_281da193bdadda52)
+}
+
+var fileDescriptor_281da193bdadda52 = []byte{
+	// 225 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xb2, 0x4a, 0xce, 0xcf, 0x4d,
+	0x2d, 0x49, 0x4a, 0x2b, 0xd1, 0x2f, 0x4e, 0x2d, 0x2a, 0xcb, 0x4c, 0x4e, 0x2d, 0xd6, 0xcf, 0xc9,
+	0x4c, 0xcf, 0x28, 0x89, 0x4f, 0xca, 0xc9, 0x4f, 0xce, 0xd6, 0x2f, 0x33, 0x44, 0xe6, 0xc6, 0x43,
+	0xd5, 0xe8, 0x15, 0x14, 0xe5, 0x97, 0xe4, 0x0b, 0x29, 0xc0, 0xf4, 0xea, 0xc1, 0xf4, 0xea, 0x21,
+	0x29, 0xd6, 0x2b, 0x33, 0x94, 0x32, 0x22, 0xc5, 0x74, 0x88, 0xa9, 0x46, 0x2b, 0x98, 0xb8, 0x04,
+	0x7d, 0x40, 0xa2, 0x4e, 0x20, 0xc1, 0x60, 0x88, 0x3e, 0xa1, 0x06, 0x46, 0x2e, 0x5e, 0xf7, 0xd4,
+	0x12, 0x84, 0x84, 0x90, 0x99, 0x1e, 0x21, 0xeb, 0xf5, 0x50, 0x34, 0x04, 0xa5, 0x16, 0x96, 0xa6,
+	0x16, 0x97, 0x48, 0x99, 0x93, 0xac, 0xaf, 0xb8, 0x20, 0x3f, 0xaf, 0x38, 0x55, 0xa8, 0x9f, 0x91,
+	0x4b, 0xc8, 0x3d, 0xb5, 0xc4, 0x19, 0xc4, 0xc9, 0x2b, 0x2e, 0x2d, 0x0e, 0x48, 0x2c, 0x4a, 0xcc,
+	0x2d, 0x16, 0xb2, 0x26, 0xca, 0x3c, 0x34, 0x5d, 0x30, 0xc7, 0xd8, 0x90, 0xa7, 0x19, 0xe2, 0x22,
+	0xa7, 0xe8, 0x13, 0x8f, 0xe4, 0x18, 0x2f, 0x3c, 0x92, 0x63, 0x7c, 0xf0, 0x48, 0x8e, 0x71, 0xc2,
+	0x63, 0x39, 0x86, 0x0b, 0x8f, 0xe5, 0x18, 0x6e, 0x3c, 0x96, 0x63, 0x88, 0x72, 0x4c, 0xcf, 0x2c,
+	0xc9, 0x28, 0x4d, 0x02, 0x99, 0xae, 0x0f, 0x8f, 0x03, 0x38, 0x23, 0xb1, 0x20, 0x53, 0x9f, 0x50,
+	0xcc, 0x24, 0xb1, 0x81, 0xa3, 0xc3, 0x18, 0x30, 0x00, 0x76, 0xf8, 0x1e, 0x84, 0x22, 0x02, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// LightBlockServiceClient is the client API for LightBlockService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LightBlockServiceClient interface {
+	// GetLightBlock returns the signed header and validator set at the given
+	// height, or at the latest height if the height is 0.
+	GetLightBlock(ctx context.Context, in *GetLightBlockRequest, opts ...grpc.CallOption) (*GetLightBlockResponse, error)
+	// GetConsensusParams returns the consensus parameters at the given height,
+	// or at the latest height if the height is 0.
+	GetConsensusParams(ctx context.Context, in *GetConsensusParamsRequest, opts ...grpc.CallOption) (*GetConsensusParamsResponse, error)
+}
+
+type lightBlockServiceClient struct {
+	cc grpc1.ClientConn
+}
+
+func NewLightBlockServiceClient(cc grpc1.ClientConn) LightBlockServiceClient {
+	return &lightBlockServiceClient{cc}
+}
+
+func (c *lightBlockServiceClient) GetLightBlock(ctx context.Context, in *GetLightBlockRequest, opts ...grpc.CallOption) (*GetLightBlockResponse, error) {
+	out := new(GetLightBlockResponse)
+	err := c.cc.Invoke(ctx, "/cometbft.services.light_block.v1.LightBlockService/GetLightBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightBlockServiceClient) GetConsensusParams(ctx context.Context, in *GetConsensusParamsRequest, opts ...grpc.CallOption) (*GetConsensusParamsResponse, error) {
+	out := new(GetConsensusParamsResponse)
+	err := c.cc.Invoke(ctx, "/cometbft.services.light_block.v1.LightBlockService/GetConsensusParams", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LightBlockServiceServer is the server API for LightBlockService service.
+type LightBlockServiceServer interface {
+	// GetLightBlock returns the signed header and validator set at the given
+	// height, or at the latest height if the height is 0.
+	GetLightBlock(context.Context, *GetLightBlockRequest) (*GetLightBlockResponse, error)
+	// GetConsensusParams returns the consensus parameters at the given height,
+	// or at the latest height if the height is 0.
+	GetConsensusParams(context.Context, *GetConsensusParamsRequest) (*GetConsensusParamsResponse, error)
+}
+
+// UnimplementedLightBlockServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedLightBlockServiceServer struct {
+}
+
+func (*UnimplementedLightBlockServiceServer) GetLightBlock(ctx context.Context, req *GetLightBlockRequest) (*GetLightBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLightBlock not implemented")
+}
+func (*UnimplementedLightBlockServiceServer) GetConsensusParams(ctx context.Context, req *GetConsensusParamsRequest) (*GetConsensusParamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConsensusParams not implemented")
+}
+
+func RegisterLightBlockServiceServer(s grpc1.Server, srv LightBlockServiceServer) {
+	s.RegisterService(&_LightBlockService_serviceDesc, srv)
+}
+
+func _LightBlockService_GetLightBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLightBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightBlockServiceServer).GetLightBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cometbft.services.light_block.v1.LightBlockService/GetLightBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightBlockServiceServer).GetLightBlock(ctx, req.(*GetLightBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LightBlockService_GetConsensusParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConsensusParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightBlockServiceServer).GetConsensusParams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cometbft.services.light_block.v1.LightBlockService/GetConsensusParams",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightBlockServiceServer).GetConsensusParams(ctx, req.(*GetConsensusParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var LightBlockService_serviceDesc = _LightBlockService_serviceDesc
+var _LightBlockService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cometbft.services.light_block.v1.LightBlockService",
+	HandlerType: (*LightBlockServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLightBlock",
+			Handler:    _LightBlockService_GetLightBlock_Handler,
+		},
+		{
+			MethodName: "GetConsensusParams",
+			Handler:    _LightBlockService_GetConsensusParams_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "cometbft/services/light_block/v1/light_block_service.proto",
+}
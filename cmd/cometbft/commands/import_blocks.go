@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cometbft/cometbft/v2/config"
+	"github.com/cometbft/cometbft/v2/internal/blocksync"
+	cs "github.com/cometbft/cometbft/v2/internal/consensus"
+	"github.com/cometbft/cometbft/v2/mempool"
+	"github.com/cometbft/cometbft/v2/node"
+	"github.com/cometbft/cometbft/v2/proxy"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/store"
+)
+
+// ImportBlocksCmd imports blocks from a block archive.
+var ImportBlocksCmd = &cobra.Command{
+	Use:     "import-blocks [file]",
+	Aliases: []string{"import_blocks"},
+	Short:   "import blocks from an archive file and execute them against the app",
+	Long: `
+import-blocks reads an archive produced by export-blocks, verifies every block
+against the current state the same way block sync does, stores it and executes
+it against the application configured in proxy_app.
+
+Blocks at or below the latest height of the node are skipped, so an
+interrupted import is resumed by running the command again with the same
+archive. Events are not indexed during the import; run reindex-event
+afterwards if needed.
+
+This is an offline command: the node must not be running.
+`,
+	Example: `
+	cometbft import-blocks blocks.bin
+	`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		height, err := ImportBlocks(cmd.Context(), config, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to import blocks: %w", err)
+		}
+		fmt.Printf("Imported blocks up to height %d\n", height)
+		return nil
+	},
+}
+
+// ImportBlocks applies the blocks of the block archive at path on top of the
+// node's current state. It returns the latest height after the import.
+func ImportBlocks(ctx context.Context, config *cfg.Config, path string) (int64, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	blockStoreDB, err := cfg.DefaultDBProvider(&cfg.DBContext{ID: "blockstore", Config: config})
+	if err != nil {
+		return 0, err
+	}
+	blockStore := store.NewBlockStore(blockStoreDB, store.WithDBKeyLayout(config.Storage.ExperimentalKeyLayout),
+		store.WithCompression(config.Storage.Compression))
+	defer blockStore.Close()
+
+	stateDB, err := cfg.DefaultDBProvider(&cfg.DBContext{ID: "state", Config: config})
+	if err != nil {
+		return 0, err
+	}
+	state, genDoc, err := node.LoadStateFromDBOrGenesisDocProviderWithConfig(
+		stateDB, node.DefaultGenesisDocProviderFunc(config), "", config)
+	if err != nil {
+		return 0, err
+	}
+	stateStore := sm.NewStore(stateDB, sm.StoreOptions{
+		DiscardABCIResponses: config.Storage.DiscardABCIResponses,
+		Logger:               logger,
+		DBKeyLayout:          config.Storage.ExperimentalKeyLayout,
+		Compression:          config.Storage.Compression,
+	})
+	defer stateStore.Close()
+
+	proxyApp := proxy.NewAppConns(proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()), proxy.NopMetrics())
+	proxyApp.SetLogger(logger.With("module", "proxy"))
+	if err := proxyApp.Start(); err != nil {
+		return 0, fmt.Errorf("error starting proxy app connections: %w", err)
+	}
+	defer func() {
+		_ = proxyApp.Stop()
+	}()
+
+	// Bring the application up to date with the stores first, in case a
+	// previous import was interrupted between saving a block and committing
+	// it in the application.
+	appInfoResponse, err := proxyApp.Query().Info(ctx, proxy.InfoRequest)
+	if err != nil {
+		return 0, fmt.Errorf("error calling Info: %w", err)
+	}
+	handshaker := cs.NewHandshaker(stateStore, state, blockStore, genDoc)
+	handshaker.SetLogger(logger.With("module", "consensus"))
+	if err := handshaker.Handshake(ctx, appInfoResponse, proxyApp); err != nil {
+		return 0, fmt.Errorf("error during handshake: %w", err)
+	}
+	if state, err = stateStore.Load(); err != nil {
+		return 0, err
+	}
+
+	storeHeight := blockStore.Height()
+	if storeHeight == 0 {
+		// The stores may have been bootstrapped by an offline state sync.
+		if storeHeight, err = stateStore.GetOfflineStateSyncHeight(); err != nil {
+			storeHeight = 0
+		}
+	}
+	if state.LastBlockHeight != storeHeight {
+		return 0, fmt.Errorf("state (%d) and store (%d) height mismatch", state.LastBlockHeight, storeHeight)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	blockExec := sm.NewBlockExecutor(stateStore, logger.With("module", "state"), proxyApp.Consensus(),
+		&mempool.NopMempool{}, sm.EmptyEvidencePool{}, blockStore)
+
+	logger.Info("Importing blocks", "from_height", state.LastBlockHeight+1, "archive", path)
+	state, err = blocksync.ImportBlocks(ctx, blocksync.NewArchiveReader(f), state, blockExec, blockStore,
+		logger.With("module", "blocksync"))
+	return state.LastBlockHeight, err
+}
@@ -0,0 +1,418 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/spf13/cobra"
+
+	dbm "github.com/cometbft/cometbft-db"
+	cfg "github.com/cometbft/cometbft/v2/config"
+	cmtos "github.com/cometbft/cometbft/v2/internal/os"
+	"github.com/cometbft/cometbft/v2/internal/tempfile"
+)
+
+// migrateBatchSize is the number of entries written to the new database in
+// each batch.
+const migrateBatchSize = 10000
+
+// migratedDBs are the IDs of the databases opened through the DBProvider.
+var migratedDBs = []string{"blockstore", "state", "tx_index", "evidence", "light"}
+
+// coldBlockStoreName is the name of the cold database of the blockstore among
+// the migrated databases.
+const coldBlockStoreName = "blockstore (cold)"
+
+// migrateDBJournalFile is the file, in db_dir, recording the databases being
+// swapped with their copies, so that an interrupted swap is completed by
+// running migrate-db again.
+const migrateDBJournalFile = "migrate-db.json"
+
+// renameFile renames the databases, replaced in tests.
+var renameFile = os.Rename
+
+// dbMigration is a database migrated by MigrateDB.
+type dbMigration struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+	Dir  string `json:"dir"`
+}
+
+// migrateDBJournal records the databases migrated from a backend to another,
+// once they are all copied and verified. The copies are written to the
+// "<to>-migration" directory next to each database, and the originals are
+// moved to the "<from>-backup" one.
+type migrateDBJournal struct {
+	From       dbm.BackendType `json:"from"`
+	To         dbm.BackendType `json:"to"`
+	Migrations []dbMigration   `json:"migrations"`
+}
+
+func (j *migrateDBJournal) stagingDir(dir string) string {
+	return filepath.Join(dir, string(j.To)+"-migration")
+}
+
+func (j *migrateDBJournal) backupDir(dir string) string {
+	return filepath.Join(dir, string(j.From)+"-backup")
+}
+
+// loadMigrateDBJournal loads the journal in path, or returns nil if there is
+// none.
+func loadMigrateDBJournal(path string) (*migrateDBJournal, error) {
+	bz, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var j migrateDBJournal
+	if err := json.Unmarshal(bz, &j); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &j, nil
+}
+
+// save writes the journal to path.
+func (j *migrateDBJournal) save(path string) error {
+	bz, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	return tempfile.WriteFileAtomic(path, bz, 0o600)
+}
+
+// swap moves each database to its backup directory and its copy in its
+// place. The databases already swapped, whose copy is no longer in the
+// staging directory, are skipped, so that an interrupted swap can be
+// completed.
+func (j *migrateDBJournal) swap() error {
+	for _, m := range j.Migrations {
+		name := m.ID + ".db"
+		staged := filepath.Join(j.stagingDir(m.Dir), name)
+		if !cmtos.FileExists(staged) {
+			continue
+		}
+		if err := os.MkdirAll(j.backupDir(m.Dir), 0o700); err != nil {
+			return err
+		}
+		current := filepath.Join(m.Dir, name)
+		if cmtos.FileExists(current) {
+			if err := renameFile(current, filepath.Join(j.backupDir(m.Dir), name)); err != nil {
+				return err
+			}
+		}
+		if err := renameFile(staged, current); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollback undoes swap, moving the copies back to the staging directories
+// and the databases back from the backup directories.
+func (j *migrateDBJournal) rollback() error {
+	for i := len(j.Migrations) - 1; i >= 0; i-- {
+		m := j.Migrations[i]
+		name := m.ID + ".db"
+		backup := filepath.Join(j.backupDir(m.Dir), name)
+		if !cmtos.FileExists(backup) {
+			continue
+		}
+		current := filepath.Join(m.Dir, name)
+		if cmtos.FileExists(current) {
+			if err := renameFile(current, filepath.Join(j.stagingDir(m.Dir), name)); err != nil {
+				return err
+			}
+		}
+		if err := renameFile(backup, current); err != nil {
+			return err
+		}
+	}
+	for _, m := range j.Migrations {
+		if err := os.Remove(j.backupDir(m.Dir)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+var dbBackendRegexp = regexp.MustCompile(`(?m)^db_backend\s*=.*$`)
+
+var migrateTo string
+
+func init() {
+	MigrateDBCmd.Flags().StringVar(&migrateTo, "to", "", "the database backend to migrate to")
+	_ = MigrateDBCmd.MarkFlagRequired("to")
+}
+
+// MigrateDBCmd copies the node's databases to another backend.
+var MigrateDBCmd = &cobra.Command{
+	Use:     "migrate-db",
+	Aliases: []string{"migrate_db"},
+	Short:   "migrate the node's databases to another database backend",
+	Long: `
+migrate-db copies every database of the node (blockstore, state, tx_index,
+evidence and light, when they exist) from the backend set in db_backend to the
+given backend. The cold database of the blockstore is migrated as well, unless
+storage.cold_storage.db_backend sets its own backend. The copies are verified
+by comparing the number of entries and a checksum of the entries of both
+databases.
+
+Once all the databases are copied and verified, the old databases are moved to
+the "<backend>-backup" directory under db_dir (and under the directory of the
+cold database), the new ones replace them, and db_backend is updated in
+config.toml. The backup can be deleted once the node runs correctly with the
+new backend. If moving the databases fails, they are moved back. If the command
+is interrupted while moving them, running it again with the same backend
+completes the migration.
+
+This is an offline command: the node must not be running.
+`,
+	Example: `
+	cometbft migrate-db --to pebbledb
+	`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		from := config.DBBackend
+		migrated, err := MigrateDB(config, dbm.BackendType(migrateTo))
+		if err != nil {
+			return fmt.Errorf("failed to migrate databases: %w", err)
+		}
+		fmt.Printf("Migrated databases %v from %s to %s\n", migrated, from, migrateTo)
+		return nil
+	},
+}
+
+// MigrateDB copies the databases of the node from its configured backend to
+// backend, verifies the copies, swaps them with the originals, which are
+// kept in a backup directory, and updates db_backend in the config file. It
+// returns the names of the migrated databases.
+//
+// If swapping the databases fails, the swap is rolled back. If it is
+// interrupted, or cannot be rolled back, running MigrateDB again with the
+// same backend completes it.
+func MigrateDB(config *cfg.Config, backend dbm.BackendType) ([]string, error) {
+	configFile := filepath.Join(config.RootDir, cfg.DefaultConfigDir, cfg.DefaultConfigFileName)
+	configBytes, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if !dbBackendRegexp.Match(configBytes) {
+		return nil, fmt.Errorf("db_backend not found in %s", configFile)
+	}
+
+	journalFile := filepath.Join(config.DBDir(), migrateDBJournalFile)
+	journal, err := loadMigrateDBJournal(journalFile)
+	if err != nil {
+		return nil, err
+	}
+	if journal != nil {
+		if journal.To != backend {
+			return nil, fmt.Errorf("the interrupted migration from %s to %s must be completed first", journal.From, journal.To)
+		}
+		if err := journal.swap(); err != nil {
+			return nil, fmt.Errorf("completing the interrupted migration: %w", err)
+		}
+		return completeMigrateDB(config, configFile, configBytes, journal, journalFile)
+	}
+
+	from := dbm.BackendType(config.DBBackend)
+	switch backend {
+	case from:
+		return nil, fmt.Errorf("databases already use the %s backend", backend)
+	case dbm.MemDBBackend:
+		return nil, errors.New("cannot migrate to an in-memory database")
+	}
+
+	var migrations []dbMigration
+	dirs := []string{config.DBDir()}
+	for _, id := range migratedDBs {
+		migrations = append(migrations, dbMigration{Name: id, ID: id, Dir: config.DBDir()})
+	}
+	// The cold database of the blockstore follows db_backend unless it sets
+	// its own backend.
+	if cold := config.Storage.ColdStorage; cold != nil && cold.Enabled && cold.DBBackend == "" {
+		dirs = append(dirs, cold.DBDir())
+		migrations = append(migrations, dbMigration{Name: coldBlockStoreName, ID: "blockstore", Dir: cold.DBDir()})
+	}
+	journal = &migrateDBJournal{From: from, To: backend}
+	for _, dir := range dirs {
+		if cmtos.FileExists(journal.backupDir(dir)) {
+			return nil, fmt.Errorf("backup directory %s already exists", journal.backupDir(dir))
+		}
+		// Discard the leftovers of an interrupted copy.
+		if err := os.RemoveAll(journal.stagingDir(dir)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, m := range migrations {
+		if !cmtos.FileExists(filepath.Join(m.Dir, m.ID+".db")) {
+			continue
+		}
+		if err := migrateDB(m.ID, from, m.Dir, backend, journal.stagingDir(m.Dir)); err != nil {
+			return nil, fmt.Errorf("migrating %s: %w", m.Name, err)
+		}
+		journal.Migrations = append(journal.Migrations, m)
+	}
+
+	if err := journal.save(journalFile); err != nil {
+		return nil, fmt.Errorf("saving %s: %w", journalFile, err)
+	}
+	if err := journal.swap(); err != nil {
+		if rerr := journal.rollback(); rerr != nil {
+			return nil, fmt.Errorf("swapping the databases: %w; rolling back: %v; "+
+				"run migrate-db --to %s again to complete the migration", err, rerr, backend)
+		}
+		if rerr := os.Remove(journalFile); rerr != nil {
+			return nil, fmt.Errorf("swapping the databases: %w; removing %s: %v", err, journalFile, rerr)
+		}
+		return nil, fmt.Errorf("swapping the databases, rolled back: %w", err)
+	}
+	return completeMigrateDB(config, configFile, configBytes, journal, journalFile)
+}
+
+// completeMigrateDB removes the staging directories once the databases of
+// journal are swapped, updates db_backend in the config file, and removes the
+// journal. It returns the names of the migrated databases.
+func completeMigrateDB(
+	config *cfg.Config,
+	configFile string,
+	configBytes []byte,
+	journal *migrateDBJournal,
+	journalFile string,
+) ([]string, error) {
+	names := make([]string, len(journal.Migrations))
+	for i, m := range journal.Migrations {
+		if err := os.RemoveAll(journal.stagingDir(m.Dir)); err != nil {
+			return nil, err
+		}
+		names[i] = m.Name
+	}
+
+	configBytes = dbBackendRegexp.ReplaceAll(configBytes, fmt.Appendf(nil, "db_backend = %q", journal.To))
+	if err := tempfile.WriteFileAtomic(configFile, configBytes, 0o644); err != nil {
+		return nil, fmt.Errorf("updating db_backend in %s: %w", configFile, err)
+	}
+	config.DBBackend = string(journal.To)
+	if err := os.Remove(journalFile); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// migrateDB copies the database id in dir with the from backend to a
+// database with the to backend in toDir, and verifies the copy.
+func migrateDB(id string, from dbm.BackendType, dir string, to dbm.BackendType, toDir string) error {
+	src, err := dbm.NewDB(id, from, dir)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := dbm.NewDB(id, to, toDir)
+	if err != nil {
+		return err
+	}
+	count, sum, err := copyDB(src, dst)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	// Reopen the copy to verify what was persisted.
+	dst, err = dbm.NewDB(id, to, toDir)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	dstCount, dstSum, err := checksumDB(dst)
+	if err != nil {
+		return err
+	}
+	if dstCount != count {
+		return fmt.Errorf("copied %d entries, found %d", count, dstCount)
+	}
+	if !bytes.Equal(dstSum, sum) {
+		return fmt.Errorf("checksum mismatch: %X != %X", dstSum, sum)
+	}
+	return nil
+}
+
+// copyDB copies all the entries of src to dst, returning their number and
+// checksum.
+func copyDB(src, dst dbm.DB) (int64, []byte, error) {
+	it, err := src.Iterator(nil, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer it.Close()
+
+	h := sha256.New()
+	batch := dst.NewBatch()
+	defer func() {
+		_ = batch.Close()
+	}()
+	var count int64
+	for ; it.Valid(); it.Next() {
+		key, value := it.Key(), it.Value()
+		if err := batch.Set(key, value); err != nil {
+			return 0, nil, err
+		}
+		writeChecksumEntry(h, key, value)
+		count++
+
+		if count%migrateBatchSize == 0 {
+			if err := batch.Write(); err != nil {
+				return 0, nil, err
+			}
+			_ = batch.Close()
+			batch = dst.NewBatch()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return 0, nil, err
+	}
+	if err := batch.WriteSync(); err != nil {
+		return 0, nil, err
+	}
+	return count, h.Sum(nil), nil
+}
+
+// checksumDB returns the number and checksum of the entries of db.
+func checksumDB(db dbm.DB) (int64, []byte, error) {
+	it, err := db.Iterator(nil, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer it.Close()
+
+	h := sha256.New()
+	var count int64
+	for ; it.Valid(); it.Next() {
+		writeChecksumEntry(h, it.Key(), it.Value())
+		count++
+	}
+	return count, h.Sum(nil), it.Error()
+}
+
+// writeChecksumEntry adds a key and value, each prefixed by its length, to
+// the checksum h.
+func writeChecksumEntry(h hash.Hash, key, value []byte) {
+	var buf [binary.MaxVarintLen64]byte
+	h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(key)))])
+	h.Write(key)
+	h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(value)))])
+	h.Write(value)
+}
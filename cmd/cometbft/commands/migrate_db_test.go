@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+	cfg "github.com/cometbft/cometbft/v2/config"
+)
+
+func TestMigrateDB(t *testing.T) {
+	config := cfg.DefaultConfig()
+	config.SetRoot(t.TempDir())
+	config.DBBackend = string(dbm.GoLevelDBBackend)
+	cfg.EnsureRoot(config.RootDir)
+	cfg.WriteConfigFile(filepath.Join(config.RootDir, cfg.DefaultConfigDir, cfg.DefaultConfigFileName), config)
+
+	for _, id := range []string{"blockstore", "state"} {
+		db, err := dbm.NewDB(id, dbm.GoLevelDBBackend, config.DBDir())
+		require.NoError(t, err)
+		for i := 0; i < 2*migrateBatchSize+1; i++ {
+			require.NoError(t, db.Set(fmt.Appendf(nil, "%s:%d", id, i), fmt.Appendf(nil, "value %d", i)))
+		}
+		require.NoError(t, db.Close())
+	}
+
+	_, err := MigrateDB(config, dbm.GoLevelDBBackend)
+	require.Error(t, err)
+
+	migrated, err := MigrateDB(config, dbm.PebbleDBBackend)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"blockstore", "state"}, migrated)
+	assert.Equal(t, string(dbm.PebbleDBBackend), config.DBBackend)
+
+	configBytes, err := os.ReadFile(filepath.Join(config.RootDir, cfg.DefaultConfigDir, cfg.DefaultConfigFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(configBytes), `db_backend = "pebbledb"`)
+	assert.DirExists(t, filepath.Join(config.DBDir(), "goleveldb-backup", "state.db"))
+	assert.NoDirExists(t, filepath.Join(config.DBDir(), "pebbledb-migration"))
+
+	for _, id := range migrated {
+		db, err := dbm.NewDB(id, dbm.PebbleDBBackend, config.DBDir())
+		require.NoError(t, err)
+		count, _, err := checksumDB(db)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2*migrateBatchSize+1, count)
+		value, err := db.Get([]byte(id + ":7"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("value 7"), value)
+		require.NoError(t, db.Close())
+	}
+}
+
+func TestMigrateDBColdStore(t *testing.T) {
+	config := cfg.DefaultConfig()
+	config.SetRoot(t.TempDir())
+	config.DBBackend = string(dbm.GoLevelDBBackend)
+	config.Storage.ColdStorage.Enabled = true
+	cfg.EnsureRoot(config.RootDir)
+	cfg.WriteConfigFile(filepath.Join(config.RootDir, cfg.DefaultConfigDir, cfg.DefaultConfigFileName), config)
+
+	for _, dir := range []string{config.DBDir(), config.Storage.ColdStorage.DBDir()} {
+		db, err := dbm.NewDB("blockstore", dbm.GoLevelDBBackend, dir)
+		require.NoError(t, err)
+		require.NoError(t, db.Set([]byte("key"), []byte(dir)))
+		require.NoError(t, db.Close())
+	}
+
+	migrated, err := MigrateDB(config, dbm.PebbleDBBackend)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"blockstore", coldBlockStoreName}, migrated)
+
+	coldDir := config.Storage.ColdStorage.DBDir()
+	assert.DirExists(t, filepath.Join(coldDir, "goleveldb-backup", "blockstore.db"))
+	assert.NoDirExists(t, filepath.Join(coldDir, "pebbledb-migration"))
+	db, err := cfg.ColdBlockStoreDB(config)
+	require.NoError(t, err)
+	value, err := db.Get([]byte("key"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte(coldDir), value)
+	require.NoError(t, db.Close())
+
+	// A cold database with its own backend is left as is.
+	config.Storage.ColdStorage.DBBackend = string(dbm.PebbleDBBackend)
+	migrated, err = MigrateDB(config, dbm.GoLevelDBBackend)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"blockstore"}, migrated)
+	assert.NoDirExists(t, filepath.Join(coldDir, "pebbledb-backup"))
+}
+
+// failRenames makes the renames from the from-th, counting from 0, to the
+// to-th, excluded, fail until the test ends.
+func failRenames(t *testing.T, from, to int) {
+	t.Helper()
+	renames := 0
+	renameFile = func(oldpath, newpath string) error {
+		renames++
+		if renames > from && renames <= to {
+			return fmt.Errorf("renaming %s: %w", oldpath, os.ErrPermission)
+		}
+		return os.Rename(oldpath, newpath)
+	}
+	t.Cleanup(func() { renameFile = os.Rename })
+}
+
+func newMigrateDBConfig(t *testing.T) *cfg.Config {
+	t.Helper()
+	config := cfg.DefaultConfig()
+	config.SetRoot(t.TempDir())
+	config.DBBackend = string(dbm.GoLevelDBBackend)
+	cfg.EnsureRoot(config.RootDir)
+	cfg.WriteConfigFile(filepath.Join(config.RootDir, cfg.DefaultConfigDir, cfg.DefaultConfigFileName), config)
+
+	for _, id := range []string{"blockstore", "state"} {
+		db, err := dbm.NewDB(id, dbm.GoLevelDBBackend, config.DBDir())
+		require.NoError(t, err)
+		require.NoError(t, db.Set([]byte("key"), []byte(id)))
+		require.NoError(t, db.Close())
+	}
+	return config
+}
+
+func requireDBValues(t *testing.T, config *cfg.Config, backend dbm.BackendType) {
+	t.Helper()
+	for _, id := range []string{"blockstore", "state"} {
+		db, err := dbm.NewDB(id, backend, config.DBDir())
+		require.NoError(t, err)
+		value, err := db.Get([]byte("key"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte(id), value)
+		require.NoError(t, db.Close())
+	}
+}
+
+func TestMigrateDBRollsBackSwap(t *testing.T) {
+	config := newMigrateDBConfig(t)
+
+	// The blockstore is swapped, then moving the state database fails.
+	failRenames(t, 2, 3)
+	_, err := MigrateDB(config, dbm.PebbleDBBackend)
+	require.ErrorIs(t, err, os.ErrPermission)
+	assert.Equal(t, string(dbm.GoLevelDBBackend), config.DBBackend)
+	assert.NoDirExists(t, filepath.Join(config.DBDir(), "goleveldb-backup"))
+	assert.NoFileExists(t, filepath.Join(config.DBDir(), migrateDBJournalFile))
+	requireDBValues(t, config, dbm.GoLevelDBBackend)
+
+	renameFile = os.Rename
+	migrated, err := MigrateDB(config, dbm.PebbleDBBackend)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"blockstore", "state"}, migrated)
+	requireDBValues(t, config, dbm.PebbleDBBackend)
+}
+
+func TestMigrateDBCompletesInterruptedSwap(t *testing.T) {
+	config := newMigrateDBConfig(t)
+
+	// The blockstore is swapped, then neither the swap nor its rollback can
+	// proceed.
+	failRenames(t, 2, math.MaxInt)
+	_, err := MigrateDB(config, dbm.PebbleDBBackend)
+	require.ErrorIs(t, err, os.ErrPermission)
+	assert.FileExists(t, filepath.Join(config.DBDir(), migrateDBJournalFile))
+
+	renameFile = os.Rename
+	_, err = MigrateDB(config, dbm.GoLevelDBBackend)
+	require.Error(t, err)
+
+	migrated, err := MigrateDB(config, dbm.PebbleDBBackend)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"blockstore", "state"}, migrated)
+	assert.Equal(t, string(dbm.PebbleDBBackend), config.DBBackend)
+	assert.NoFileExists(t, filepath.Join(config.DBDir(), migrateDBJournalFile))
+	assert.NoDirExists(t, filepath.Join(config.DBDir(), "pebbledb-migration"))
+	assert.DirExists(t, filepath.Join(config.DBDir(), "goleveldb-backup", "state.db"))
+	requireDBValues(t, config, dbm.PebbleDBBackend)
+}
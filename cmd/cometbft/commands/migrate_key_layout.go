@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	dbm "github.com/cometbft/cometbft-db"
+	cfg "github.com/cometbft/cometbft/v2/config"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/store"
+)
+
+var keyMigrationBatchSize int
+
+func init() {
+	MigrateKeyLayoutCmd.Flags().IntVar(&keyMigrationBatchSize, "batch-size", migrateBatchSize,
+		"the number of keys rewritten in each batch")
+}
+
+// MigrateKeyLayoutCmd rewrites the keys of the blockstore and state databases
+// with the v2 key layout.
+var MigrateKeyLayoutCmd = &cobra.Command{
+	Use:     "migrate-key-layout",
+	Aliases: []string{"migrate_key_layout"},
+	Short:   "migrate the blockstore and state databases to the v2 key layout",
+	Long: `
+migrate-key-layout rewrites the keys of the blockstore and state databases from
+the v1 (legacy) to the v2 key layout, in batches, and records the new layout
+version in the databases, including the cold database of the blockstore if
+storage.cold_storage is enabled, so that the node uses the v2 layout from then on,
+whatever the value of storage.experimental_db_key_layout.
+
+An interrupted migration is resumed by running the command again. The node
+cannot start until the migration is complete.
+
+This is an offline command: the node must not be running.
+`,
+	Example: `
+	cometbft migrate-key-layout --batch-size 5000
+	`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		blocks, states, err := MigrateKeyLayout(config, keyMigrationBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to migrate the key layout: %w", err)
+		}
+		fmt.Printf("Migrated %d blockstore keys and %d state keys to the v2 key layout\n", blocks, states)
+		return nil
+	},
+}
+
+// MigrateKeyLayout migrates the keys of the blockstore and state databases of
+// the node to the v2 key layout, in batches of batchSize keys. The cold
+// database of the blockstore, if any, is migrated before the main one, whose
+// layout version the node checks, and its keys are counted in blocks. It
+// returns the number of migrated keys of each database.
+func MigrateKeyLayout(config *cfg.Config, batchSize int) (blocks, states int64, err error) {
+	if batchSize <= 0 {
+		return 0, 0, errors.New("batch size must be positive")
+	}
+	backend := dbm.BackendType(config.DBBackend)
+
+	coldDB, err := cfg.ColdBlockStoreDB(config)
+	if err != nil {
+		return 0, 0, err
+	}
+	if coldDB != nil {
+		defer coldDB.Close()
+		blocks, err = store.MigrateKeyLayoutToV2(coldDB, batchSize)
+		if err != nil {
+			return blocks, 0, fmt.Errorf("migrating the cold blockstore: %w", err)
+		}
+	}
+
+	blockStoreDB, err := dbm.NewDB("blockstore", backend, config.DBDir())
+	if err != nil {
+		return blocks, 0, err
+	}
+	defer blockStoreDB.Close()
+	n, err := store.MigrateKeyLayoutToV2(blockStoreDB, batchSize)
+	blocks += n
+	if err != nil {
+		return blocks, 0, fmt.Errorf("migrating the blockstore: %w", err)
+	}
+
+	stateDB, err := dbm.NewDB("state", backend, config.DBDir())
+	if err != nil {
+		return blocks, 0, err
+	}
+	defer stateDB.Close()
+	states, err = sm.MigrateKeyLayoutToV2(stateDB, batchSize)
+	if err != nil {
+		return blocks, states, fmt.Errorf("migrating the state: %w", err)
+	}
+	return blocks, states, nil
+}
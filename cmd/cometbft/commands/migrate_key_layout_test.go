@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+	cfg "github.com/cometbft/cometbft/v2/config"
+)
+
+func TestMigrateKeyLayoutColdStore(t *testing.T) {
+	config := cfg.DefaultConfig()
+	config.SetRoot(t.TempDir())
+	config.DBBackend = string(dbm.GoLevelDBBackend)
+	config.Storage.ColdStorage.Enabled = true
+
+	for _, dir := range []string{config.DBDir(), config.Storage.ColdStorage.DBDir()} {
+		db, err := dbm.NewDB("blockstore", dbm.GoLevelDBBackend, dir)
+		require.NoError(t, err)
+		require.NoError(t, db.Set([]byte("P:5:0"), []byte("part")))
+		require.NoError(t, db.Set([]byte("C:5"), []byte("commit")))
+		require.NoError(t, db.Close())
+	}
+
+	blocks, _, err := MigrateKeyLayout(config, 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, blocks)
+
+	for _, dir := range []string{config.DBDir(), config.Storage.ColdStorage.DBDir()} {
+		db, err := dbm.NewDB("blockstore", dbm.GoLevelDBBackend, dir)
+		require.NoError(t, err)
+		version, err := db.Get([]byte("version"))
+		require.NoError(t, err)
+		assert.Equal(t, "v2", string(version), dir)
+		has, err := db.Has([]byte("P:5:0"))
+		require.NoError(t, err)
+		assert.False(t, has, dir)
+		require.NoError(t, db.Close())
+	}
+}
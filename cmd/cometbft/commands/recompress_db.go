@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	dbm "github.com/cometbft/cometbft-db"
+	cfg "github.com/cometbft/cometbft/v2/config"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/store"
+)
+
+var recompressBatchSize int
+
+func init() {
+	RecompressDBCmd.Flags().IntVar(&recompressBatchSize, "batch-size", migrateBatchSize,
+		"the number of records rewritten in each batch")
+}
+
+// RecompressDBCmd rewrites the stored block parts and ABCI responses with the
+// configured compression.
+var RecompressDBCmd = &cobra.Command{
+	Use:     "recompress-db",
+	Aliases: []string{"recompress_db"},
+	Short:   "rewrite the stored block parts and ABCI responses with the configured compression",
+	Long: `
+recompress-db rewrites the block parts and ABCI responses stored by the node
+which were written with another compression than storage.compression, in
+batches, then compacts the databases to reclaim the space of the old records.
+Records written with any compression are readable, so running this command is
+only needed to apply a new compression setting to existing data.
+
+An interrupted run is resumed by running the command again.
+
+This is an offline command: the node must not be running.
+`,
+	Example: `
+	cometbft recompress-db --batch-size 5000
+	`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		parts, responses, err := RecompressDB(config, recompressBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to recompress the databases: %w", err)
+		}
+		fmt.Printf("Rewrote %d block parts and %d ABCI responses with compression %q\n",
+			parts, responses, config.Storage.Compression)
+		return nil
+	},
+}
+
+// RecompressDB rewrites the block parts and ABCI responses of the node which
+// are not compressed with config.Storage.Compression, in batches of batchSize
+// records, and compacts the databases. It returns the number of rewritten
+// block parts and ABCI responses.
+func RecompressDB(config *cfg.Config, batchSize int) (parts, responses int64, err error) {
+	if batchSize <= 0 {
+		return 0, 0, errors.New("batch size must be positive")
+	}
+	backend := dbm.BackendType(config.DBBackend)
+
+	blockStoreDB, err := dbm.NewDB("blockstore", backend, config.DBDir())
+	if err != nil {
+		return 0, 0, err
+	}
+	coldBlockStoreDB, err := cfg.ColdBlockStoreDB(config)
+	if err != nil {
+		_ = blockStoreDB.Close()
+		return 0, 0, err
+	}
+	blockStore := store.NewBlockStore(blockStoreDB,
+		store.WithDBKeyLayout(config.Storage.ExperimentalKeyLayout),
+		store.WithColdStore(coldBlockStoreDB),
+		store.WithCompression(config.Storage.Compression))
+	defer blockStore.Close()
+	parts, err = blockStore.RecompressBlockParts(batchSize)
+	if err != nil {
+		return parts, 0, fmt.Errorf("recompressing the block parts: %w", err)
+	}
+	if parts > 0 {
+		if err := compactDBs(blockStoreDB, coldBlockStoreDB); err != nil {
+			return parts, 0, fmt.Errorf("compacting the blockstore: %w", err)
+		}
+	}
+
+	stateDB, err := dbm.NewDB("state", backend, config.DBDir())
+	if err != nil {
+		return parts, 0, err
+	}
+	stateStore := sm.NewStore(stateDB, sm.StoreOptions{
+		DBKeyLayout: config.Storage.ExperimentalKeyLayout,
+		Compression: config.Storage.Compression,
+	})
+	defer stateStore.Close()
+	responses, err = sm.RecompressABCIResponses(stateStore, batchSize)
+	if err != nil {
+		return parts, responses, fmt.Errorf("recompressing the ABCI responses: %w", err)
+	}
+	if responses > 0 {
+		if err := compactDBs(stateDB); err != nil {
+			return parts, responses, fmt.Errorf("compacting the state: %w", err)
+		}
+	}
+	return parts, responses, nil
+}
+
+func compactDBs(dbs ...dbm.DB) error {
+	for _, db := range dbs {
+		if db == nil {
+			continue
+		}
+		if err := db.Compact(nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package commands
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -13,8 +15,12 @@ import (
 	abcitypes "github.com/cometbft/cometbft/v2/abci/types"
 	cmtcfg "github.com/cometbft/cometbft/v2/config"
 	"github.com/cometbft/cometbft/v2/internal/test"
+	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	blockidxkv "github.com/cometbft/cometbft/v2/state/indexer/block/kv"
 	blockmocks "github.com/cometbft/cometbft/v2/state/indexer/mocks"
 	"github.com/cometbft/cometbft/v2/state/mocks"
+	"github.com/cometbft/cometbft/v2/state/txindex"
+	"github.com/cometbft/cometbft/v2/state/txindex/kv"
 	txmocks "github.com/cometbft/cometbft/v2/state/txindex/mocks"
 	"github.com/cometbft/cometbft/v2/types"
 )
@@ -90,11 +96,14 @@ func TestLoadEventSink(t *testing.T) {
 		{"KV", "", false},
 		{"PSQL", "", true}, // true because empty connect url
 		// skip to test PSQL connect with correct url
+		{"SQLITE", "", false},
+		{"JSONL", "", false},
 		{"UnsupportedSinkType", "wrongUrl", true},
+		{"kv,sqlite", "", true},
 	}
 
 	for idx, tc := range testCases {
-		cfg := cmtcfg.TestConfig()
+		cfg := cmtcfg.TestConfig().SetRoot(t.TempDir())
 		cfg.TxIndex.Indexer = tc.sinks
 		cfg.TxIndex.PsqlConn = tc.connURL
 		_, _, err := loadEventSinks(cfg, test.DefaultTestChainID)
@@ -188,3 +197,160 @@ func TestReIndexEvent(t *testing.T) {
 		}
 	}
 }
+
+func TestReIndexEventParallel(t *testing.T) {
+	const lastHeight int64 = 350
+
+	mockBlockStore := &mocks.BlockStore{}
+	mockStateStore := &mocks.Store{}
+	mockBlockStore.
+		On("LoadBlock", mock.AnythingOfType("int64")).
+		Return(&types.Block{Data: types.Data{Txs: types.Txs{make(types.Tx, 1)}}}, &types.BlockMeta{})
+	abciResp := &abcitypes.FinalizeBlockResponse{
+		TxResults: []*abcitypes.ExecTxResult{{Code: 0}},
+	}
+	mockStateStore.
+		On("LoadFinalizeBlockResponse", int64(150)).Return(nil, errors.New("")).Once().
+		On("LoadFinalizeBlockResponse", mock.AnythingOfType("int64")).Return(abciResp, nil)
+
+	store := dbm.NewMemDB()
+	txIndexer := kv.NewTxIndex(store)
+	blockIndexer := blockidxkv.New(dbm.NewPrefixDB(store, []byte("block_events")))
+
+	args := eventReIndexArgs{
+		startHeight:  1,
+		endHeight:    lastHeight,
+		workers:      1,
+		blockIndexer: blockIndexer,
+		txIndexer:    txIndexer,
+		checkpointer: txIndexer,
+		blockStore:   mockBlockStore,
+		stateStore:   mockStateStore,
+	}
+
+	// The re-index fails at height 150, after the first range.
+	err := eventReIndex(setupReIndexEventCmd(), args)
+	require.Error(t, err)
+	checkpoint, err := txIndexer.GetReindexCheckpoint()
+	require.NoError(t, err)
+	require.Equal(t, txindex.ReindexCheckpoint{StartHeight: 1, EndHeight: lastHeight, Height: 100}, checkpoint)
+
+	args.fromHeight, err = resumeHeight(txIndexer, 1, lastHeight)
+	require.NoError(t, err)
+	require.EqualValues(t, 101, args.fromHeight)
+	args.workers = 4
+	require.NoError(t, eventReIndex(setupReIndexEventCmd(), args))
+
+	// The checkpoint keeps the heights of the run, not the resumed ones.
+	checkpoint, err = txIndexer.GetReindexCheckpoint()
+	require.NoError(t, err)
+	require.Equal(t, txindex.ReindexCheckpoint{StartHeight: 1, EndHeight: lastHeight, Height: lastHeight}, checkpoint)
+	for h := int64(1); h <= lastHeight; h++ {
+		ok, err := blockIndexer.Has(h)
+		require.NoError(t, err)
+		require.True(t, ok, h)
+	}
+
+	// Resuming after the end height reindexes nothing.
+	start, err := resumeHeight(txIndexer, 1, lastHeight)
+	require.NoError(t, err)
+	require.Equal(t, lastHeight+1, start)
+	// A re-index of other heights is not resumed.
+	start, err = resumeHeight(txIndexer, 1, lastHeight+10)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, start)
+	start, err = resumeHeight(txIndexer, 20, lastHeight)
+	require.NoError(t, err)
+	require.EqualValues(t, 20, start)
+	// The start height is kept if nothing was reindexed.
+	start, err = resumeHeight(kv.NewTxIndex(dbm.NewMemDB()), 5, lastHeight)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, start)
+}
+
+// TestReIndexEventParallelEvents checks that the events reindexed by
+// concurrent workers are numbered uniquely, so that the conditions of a query
+// are matched against the attributes of a single event. Run with -race.
+func TestReIndexEventParallelEvents(t *testing.T) {
+	const lastHeight int64 = 400
+
+	mockBlockStore := &mocks.BlockStore{}
+	mockStateStore := &mocks.Store{}
+	// Each height has two events of the same type, with different
+	// attributes.
+	events := func(height int64) []abcitypes.Event {
+		return []abcitypes.Event{
+			{Type: "transfer", Attributes: []abcitypes.EventAttribute{
+				{Key: "sender", Value: "alice", Index: true},
+				{Key: "height", Value: strconv.FormatInt(height, 10), Index: true},
+			}},
+			{Type: "transfer", Attributes: []abcitypes.EventAttribute{
+				{Key: "sender", Value: "bob", Index: true},
+				{Key: "height", Value: strconv.FormatInt(height+1000, 10), Index: true},
+			}},
+		}
+	}
+	for h := int64(1); h <= lastHeight; h++ {
+		tx := types.Tx(strconv.FormatInt(h, 10))
+		mockBlockStore.On("LoadBlock", h).Return(&types.Block{Data: types.Data{Txs: types.Txs{tx}}}, &types.BlockMeta{})
+		mockStateStore.On("LoadFinalizeBlockResponse", h).Return(&abcitypes.FinalizeBlockResponse{
+			Events:    events(h),
+			TxResults: []*abcitypes.ExecTxResult{{Code: 0, Events: events(h)}},
+		}, nil)
+	}
+
+	store := dbm.NewMemDB()
+	txIndexer := kv.NewTxIndex(store)
+	blockIndexer := blockidxkv.New(dbm.NewPrefixDB(store, []byte("block_events")))
+	args := eventReIndexArgs{
+		startHeight:  1,
+		endHeight:    lastHeight,
+		workers:      8,
+		blockIndexer: blockIndexer,
+		txIndexer:    txIndexer,
+		blockStore:   mockBlockStore,
+		stateStore:   mockStateStore,
+	}
+	require.NoError(t, eventReIndex(setupReIndexEventCmd(), args))
+
+	ctx := context.Background()
+	for _, h := range []int64{1, 150, lastHeight} {
+		// The attributes of the same event match.
+		q := query.MustCompile(fmt.Sprintf("transfer.sender = 'alice' AND transfer.height = %d", h))
+		heights, err := blockIndexer.Search(ctx, q)
+		require.NoError(t, err)
+		require.Equal(t, []int64{h}, heights)
+		results, _, err := txIndexer.Search(ctx, q, txindex.Pagination{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, h, results[0].Height)
+
+		// Those of different events do not.
+		q = query.MustCompile(fmt.Sprintf("transfer.sender = 'bob' AND transfer.height = %d", h))
+		heights, err = blockIndexer.Search(ctx, q)
+		require.NoError(t, err)
+		require.Empty(t, heights)
+		results, _, err = txIndexer.Search(ctx, q, txindex.Pagination{})
+		require.NoError(t, err)
+		require.Empty(t, results)
+	}
+}
+
+func TestSinkConfig(t *testing.T) {
+	cfg := cmtcfg.TestConfig().SetRoot(t.TempDir())
+	cfg.TxIndex.Indexer = "psql"
+	require.Same(t, cfg, sinkConfig(cfg))
+
+	sink, psqlConn = "sqlite", "postgresql://localhost"
+	defer func() { sink, psqlConn = "", "" }()
+	sinkCfg := sinkConfig(cfg)
+	require.Equal(t, "sqlite", sinkCfg.TxIndex.Indexer)
+	require.Equal(t, "postgresql://localhost", sinkCfg.TxIndex.PsqlConn)
+	// The configuration itself is left unchanged.
+	require.Equal(t, "psql", cfg.TxIndex.Indexer)
+
+	_, ti, err := loadEventSinks(sinkCfg, test.DefaultTestChainID)
+	require.NoError(t, err)
+	require.Implements(t, (*reindexCheckpointer)(nil), ti)
+	require.NoError(t, ti.Close())
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 
@@ -13,15 +14,23 @@ import (
 	"github.com/cometbft/cometbft/v2/store"
 )
 
-var removeBlock = false
+var (
+	removeBlock      = false
+	rollbackToHeight int64
+	rollbackDryRun   bool
+)
 
 func init() {
 	RollbackStateCmd.Flags().BoolVar(&removeBlock, "hard", false, "remove last block as well as state")
+	RollbackStateCmd.Flags().Int64Var(&rollbackToHeight, "to-height", 0,
+		"roll back state (and blocks with --hard) to the given height instead of by one height")
+	RollbackStateCmd.Flags().BoolVar(&rollbackDryRun, "dry-run", false,
+		"with --to-height, report what would be removed without changing anything")
 }
 
 var RollbackStateCmd = &cobra.Command{
 	Use:   "rollback",
-	Short: "rollback CometBFT state by one height",
+	Short: "rollback CometBFT state by one or more heights",
 	Long: `
 A state rollback is performed to recover from an incorrect application state transition,
 when CometBFT has persisted an incorrect app hash and is thus unable to make
@@ -30,8 +39,21 @@ The application should also roll back to height n - 1. If the --hard flag is not
 no blocks will be removed so upon restarting CometBFT the transactions in block n will be
 re-executed against the application. Using --hard will also remove block n. This can
 be done multiple times.
+
+With --to-height h, the state is rolled back to height h in a single run, using the
+stored blocks, validator sets and consensus params, and the state data of the later
+heights is removed. The blocks above h + 1 are removed as well, so that block h + 1 is
+re-executed on restart; --hard also removes block h + 1. The application should roll
+back to height h. Use --dry-run to report what would be removed.
 `,
 	RunE: func(_ *cobra.Command, _ []string) error {
+		if rollbackToHeight > 0 {
+			return rollbackTo(rollbackToHeight)
+		}
+		if rollbackDryRun {
+			return errors.New("--dry-run requires --to-height")
+		}
+
 		height, hash, err := RollbackState(config, removeBlock)
 		if err != nil {
 			return fmt.Errorf("failed to rollback state: %w", err)
@@ -65,6 +87,50 @@ func RollbackState(config *cfg.Config, removeBlock bool) (int64, []byte, error)
 	return state.Rollback(blockStore, stateStore, removeBlock)
 }
 
+func rollbackTo(height int64) error {
+	plan, err := RollbackStateTo(config, height, removeBlock, rollbackDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to rollback state: %w", err)
+	}
+
+	prefix := "Rolled back"
+	if rollbackDryRun {
+		prefix = "Would roll back"
+	}
+	if plan.StateHeight > plan.Height {
+		fmt.Printf("%s state from height %d to height %d and hash %X, removing the ABCI responses, "+
+			"validator sets and consensus params of heights %d to %d\n",
+			prefix, plan.StateHeight, plan.Height, plan.AppHash, plan.Height+1, plan.StateHeight)
+	} else {
+		fmt.Printf("State is already at height %d and hash %X\n", plan.Height, plan.AppHash)
+	}
+	if plan.DeletedBlocksTo != 0 {
+		action := "Removed"
+		if rollbackDryRun {
+			action = "Would remove"
+		}
+		fmt.Printf("%s blocks %d to %d\n", action, plan.DeletedBlocksFrom, plan.DeletedBlocksTo)
+	}
+	return nil
+}
+
+// RollbackStateTo rolls back the state to the given height, removing the
+// blocks above it (or above height + 1 if removeBlocks is false), see
+// state.RollbackTo. If dryRun is true, nothing is changed. It returns what is
+// or would be rolled back.
+func RollbackStateTo(config *cfg.Config, height int64, removeBlocks, dryRun bool) (*state.RollbackPlan, error) {
+	blockStore, stateStore, err := loadStateAndBlockStore(config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = blockStore.Close()
+		_ = stateStore.Close()
+	}()
+
+	return state.RollbackTo(blockStore, stateStore, height, removeBlocks, dryRun)
+}
+
 func loadStateAndBlockStore(config *cfg.Config) (*store.BlockStore, state.Store, error) {
 	dbType := dbm.BackendType(config.DBBackend)
 
@@ -77,7 +143,12 @@ func loadStateAndBlockStore(config *cfg.Config) (*store.BlockStore, state.Store,
 	if err != nil {
 		return nil, nil, err
 	}
-	blockStore := store.NewBlockStore(blockStoreDB, store.WithDBKeyLayout(config.Storage.ExperimentalKeyLayout))
+	coldBlockStoreDB, err := cfg.ColdBlockStoreDB(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	blockStore := store.NewBlockStore(blockStoreDB, store.WithDBKeyLayout(config.Storage.ExperimentalKeyLayout),
+		store.WithColdStore(coldBlockStoreDB))
 
 	if !os.FileExists(filepath.Join(config.DBDir(), "state.db")) {
 		return nil, nil, fmt.Errorf("no statestore found in %v", config.DBDir())
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cometbft/cometbft/v2/config"
+	"github.com/cometbft/cometbft/v2/node"
+	"github.com/cometbft/cometbft/v2/proxy"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/statesync"
+)
+
+// SnapshotCmd groups the commands operating on state sync snapshots.
+var SnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Commands operating on state sync snapshots",
+}
+
+// RestoreSnapshotCmd restores the app from a snapshot on disk and bootstraps
+// the node's stores at the snapshot height.
+var RestoreSnapshotCmd = &cobra.Command{
+	Use:   "restore [metadata file] [chunks dir]",
+	Short: "Restore a node from a local state sync snapshot",
+	Long: `
+restore state syncs a fresh node from a snapshot on disk instead of one served
+by peers. The snapshot is described by a JSON metadata file:
+
+	{"height": 1000, "format": 1, "chunks": 3, "hash": "<hex>", "metadata": "<base64>"}
+
+and its chunks are the files in the chunks directory named after their index
+(0, 1, 2, ...).
+
+The snapshot is offered and its chunks are applied to the application
+configured in proxy_app, as during state sync. The resulting app hash is then
+verified with a light client, using the state provider and trust options of the
+[statesync] section of the configuration, and the state and block stores are
+bootstrapped at the snapshot height.
+
+This is an offline command: the node must not be running, and its stores must
+be empty.
+`,
+	Example: `
+	cometbft snapshot restore snapshot.json chunks/
+	`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		height, err := RestoreSnapshot(cmd.Context(), config, args[0], args[1])
+		if err != nil {
+			return fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+		fmt.Printf("Restored snapshot at height %d\n", height)
+		return nil
+	},
+}
+
+func init() {
+	SnapshotCmd.AddCommand(RestoreSnapshotCmd)
+}
+
+// RestoreSnapshot restores the application from the snapshot described by
+// the metadata file at metadataPath, with its chunks in chunksDir, and
+// bootstraps the node's stores with the light client verified state at the
+// snapshot height. It returns the snapshot height.
+func RestoreSnapshot(ctx context.Context, config *cfg.Config, metadataPath, chunksDir string) (int64, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	genDoc, err := node.DefaultGenesisDocProviderFunc(config)()
+	if err != nil {
+		return 0, err
+	}
+	genState, err := sm.MakeGenesisState(genDoc.GenesisDoc)
+	if err != nil {
+		return 0, err
+	}
+
+	stateProvider, err := statesync.NewStateProviderFromConfig(
+		ctx, config.StateSync,
+		genState.ChainID, genState.Version, genState.InitialHeight,
+		logger.With("module", "light"),
+		config.Storage.ExperimentalKeyLayout)
+	if err != nil {
+		return 0, node.ErrLightClientStateProvider{Err: err}
+	}
+
+	proxyApp := proxy.NewAppConns(proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()), proxy.NopMetrics())
+	proxyApp.SetLogger(logger.With("module", "proxy"))
+	if err := proxyApp.Start(); err != nil {
+		return 0, fmt.Errorf("error starting proxy app connections: %w", err)
+	}
+	defer func() {
+		_ = proxyApp.Stop()
+	}()
+
+	state, _, err := statesync.RestoreLocalSnapshot(*config.StateSync, logger.With("module", "statesync"),
+		proxyApp.Snapshot(), proxyApp.Query(), stateProvider, metadataPath, chunksDir)
+	if err != nil {
+		return 0, err
+	}
+
+	err = node.BootstrapState(ctx, config, cfg.DefaultDBProvider, node.DefaultGenesisDocProviderFunc(config),
+		uint64(state.LastBlockHeight), state.AppHash)
+	if err != nil {
+		return 0, fmt.Errorf("bootstrapping state: %w", err)
+	}
+	return state.LastBlockHeight, nil
+}
@@ -0,0 +1,364 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/spf13/cobra"
+
+	cmtproto "github.com/cometbft/cometbft/api/cometbft/types/v2"
+	cfg "github.com/cometbft/cometbft/v2/config"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/store"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+var (
+	verifyStartHeight int64
+	verifyEndHeight   int64
+	verifyOutput      string
+)
+
+func init() {
+	VerifyStoreCmd.Flags().Int64Var(&verifyStartHeight, "start-height", 0,
+		"the first height to verify (default: the base height of the blockstore)")
+	VerifyStoreCmd.Flags().Int64Var(&verifyEndHeight, "end-height", 0,
+		"the last height to verify (default: the latest height of the blockstore)")
+	VerifyStoreCmd.Flags().StringVar(&verifyOutput, "output", "",
+		"file to write the report to (default: standard output)")
+}
+
+// VerifyStoreCmd checks the consistency of the blockstore and state store.
+var VerifyStoreCmd = &cobra.Command{
+	Use:     "verify-store",
+	Aliases: []string{"verify_store"},
+	Short:   "verify the integrity of the blockstore and state store",
+	Long: `
+verify-store walks the blocks in the given height range and checks, for each
+height, that:
+
+  - the block parts match the part set hash of the block ID,
+  - the block decoded from the parts matches the block ID and is valid,
+  - the LastBlockID of the block links to the block below,
+  - the commit is for the block ID and is signed by the validator set stored
+    for the height,
+  - the stored ABCI responses match the results hash and app hash of the
+    block above.
+
+It also checks the base and height recorded in the blockstore state against
+the stored blocks and the state.
+
+The report is written as JSON, listing the errors found at each corrupt
+height. The command fails if any corruption is found.
+
+This is an offline command: the node must not be running.
+`,
+	Example: `
+	cometbft verify-store
+	cometbft verify-store --start-height 100 --end-height 200 --output report.json
+	`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		report, err := VerifyStore(config, verifyStartHeight, verifyEndHeight)
+		if err != nil {
+			return fmt.Errorf("failed to verify store: %w", err)
+		}
+
+		bz, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if verifyOutput == "" {
+			fmt.Println(string(bz))
+		} else if err := os.WriteFile(verifyOutput, bz, 0o600); err != nil {
+			return err
+		}
+
+		if !report.OK() {
+			return fmt.Errorf("store is corrupt at %d heights", len(report.CorruptHeights))
+		}
+		return nil
+	},
+}
+
+// StoreReport is the result of verifying the blockstore and state store.
+type StoreReport struct {
+	Base        int64 `json:"base"`
+	Height      int64 `json:"height"`
+	StateHeight int64 `json:"state_height"`
+	StartHeight int64 `json:"start_height"`
+	EndHeight   int64 `json:"end_height"`
+	// StoreErrors are the inconsistencies of the stores which are not
+	// specific to a height, e.g. of the blockstore base and height.
+	StoreErrors    []string        `json:"store_errors"`
+	CorruptHeights []CorruptHeight `json:"corrupt_heights"`
+}
+
+// CorruptHeight lists the errors found at a height.
+type CorruptHeight struct {
+	Height int64    `json:"height"`
+	Errors []string `json:"errors"`
+}
+
+// OK returns whether no corruption was found.
+func (r *StoreReport) OK() bool {
+	return len(r.StoreErrors) == 0 && len(r.CorruptHeights) == 0
+}
+
+// VerifyStore verifies the blocks from startHeight to endHeight (inclusive)
+// of the node's blockstore against themselves and the state store. A zero
+// startHeight or endHeight defaults to the base or latest height of the
+// blockstore respectively.
+func VerifyStore(config *cfg.Config, startHeight, endHeight int64) (*StoreReport, error) {
+	blockStore, stateStore, err := loadStateAndBlockStore(config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = blockStore.Close()
+		_ = stateStore.Close()
+	}()
+
+	return verifyStore(blockStore, stateStore, startHeight, endHeight)
+}
+
+func verifyStore(blockStore *store.BlockStore, stateStore sm.Store, startHeight, endHeight int64) (*StoreReport, error) {
+	state, err := stateStore.Load()
+	if err != nil {
+		return nil, err
+	}
+	if state.IsEmpty() {
+		return nil, errors.New("no state found")
+	}
+
+	base, height := blockStore.Base(), blockStore.Height()
+	if startHeight == 0 {
+		startHeight = base
+	}
+	if endHeight == 0 {
+		endHeight = height
+	}
+	if startHeight < base || endHeight > height || startHeight > endHeight {
+		return nil, fmt.Errorf("%w (requested range: %d-%d, available: %d-%d)",
+			ErrHeightNotAvailable, startHeight, endHeight, base, height)
+	}
+
+	v := &storeVerifier{
+		blockStore: blockStore,
+		stateStore: stateStore,
+		state:      state,
+	}
+	report := &StoreReport{
+		Base:           base,
+		Height:         height,
+		StateHeight:    state.LastBlockHeight,
+		StartHeight:    startHeight,
+		EndHeight:      endHeight,
+		StoreErrors:    v.verifyStoreState(),
+		CorruptHeights: []CorruptHeight{},
+	}
+
+	var prev *types.BlockMeta
+	if startHeight > base {
+		prev, _ = v.loadBlockMeta(startHeight - 1)
+	}
+	for h := startHeight; h <= endHeight; h++ {
+		meta, errs := v.verifyHeight(h, prev)
+		if len(errs) > 0 {
+			report.CorruptHeights = append(report.CorruptHeights, CorruptHeight{Height: h, Errors: errs})
+		}
+		prev = meta
+	}
+	return report, nil
+}
+
+type storeVerifier struct {
+	blockStore *store.BlockStore
+	stateStore sm.Store
+	state      sm.State
+}
+
+// verifyStoreState checks the base and height of the blockstore against the
+// stored blocks and the state.
+func (v *storeVerifier) verifyStoreState() []string {
+	errs := []string{}
+	base, height := v.blockStore.Base(), v.blockStore.Height()
+	if base > height {
+		errs = append(errs, fmt.Sprintf("base %d is above height %d", base, height))
+	}
+	if meta, err := v.loadBlockMeta(base); meta == nil && err == nil {
+		errs = append(errs, fmt.Sprintf("no block at base %d", base))
+	}
+	if meta, err := v.loadBlockMeta(height); meta == nil && err == nil {
+		errs = append(errs, fmt.Sprintf("no block at height %d", height))
+	}
+	if meta, _ := v.loadBlockMeta(height + 1); meta != nil {
+		errs = append(errs, fmt.Sprintf("block found above height %d", height))
+	}
+	// The state may lag behind the blockstore by one block if the node
+	// stopped between saving a block and applying it.
+	if lastHeight := v.state.LastBlockHeight; height != lastHeight && height != lastHeight+1 {
+		errs = append(errs, fmt.Sprintf("height %d is inconsistent with the state height %d", height, lastHeight))
+	}
+	return errs
+}
+
+// verifyHeight checks the block at height h, whose predecessor has the meta
+// prev (nil if unknown). It returns the meta of the block, if any, and the
+// errors found.
+func (v *storeVerifier) verifyHeight(h int64, prev *types.BlockMeta) (*types.BlockMeta, []string) {
+	meta, err := v.loadBlockMeta(h)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("loading block meta: %v", err)}
+	}
+	if meta == nil {
+		return nil, []string{"missing block meta"}
+	}
+
+	var errs []string
+	if meta.Header.Height != h {
+		errs = append(errs, fmt.Sprintf("block meta is for height %d", meta.Header.Height))
+	}
+	if !bytes.Equal(meta.Header.Hash(), meta.BlockID.Hash) {
+		errs = append(errs, fmt.Sprintf("header hash %X does not match block ID hash %X", meta.Header.Hash(), meta.BlockID.Hash))
+	}
+	errs = append(errs, v.verifyBlock(h, meta)...)
+	if prev != nil && !meta.Header.LastBlockID.Equals(prev.BlockID) {
+		errs = append(errs, fmt.Sprintf("last block ID %v does not match block ID %v at height %d",
+			meta.Header.LastBlockID, prev.BlockID, h-1))
+	}
+	errs = append(errs, v.verifyCommit(h, meta)...)
+	errs = append(errs, v.verifyResults(h)...)
+	return meta, errs
+}
+
+// verifyBlock checks the parts of the block at height h against its meta.
+func (v *storeVerifier) verifyBlock(h int64, meta *types.BlockMeta) []string {
+	var errs []string
+	psh := meta.BlockID.PartSetHeader
+	buf := []byte{}
+	for i := 0; i < int(psh.Total); i++ {
+		var part *types.Part
+		if err := recoverPanic(func() { part = v.blockStore.LoadBlockPart(h, i) }); err != nil {
+			errs = append(errs, fmt.Sprintf("loading block part %d: %v", i, err))
+			continue
+		}
+		if part == nil {
+			errs = append(errs, fmt.Sprintf("missing block part %d", i))
+			continue
+		}
+		if err := part.Proof.Verify(psh.Hash, part.Bytes); err != nil {
+			errs = append(errs, fmt.Sprintf("block part %d does not match the part set hash: %v", i, err))
+			continue
+		}
+		buf = append(buf, part.Bytes...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	pbb := new(cmtproto.Block)
+	if err := proto.Unmarshal(buf, pbb); err != nil {
+		return []string{fmt.Sprintf("decoding block: %v", err)}
+	}
+	block, err := types.BlockFromProto(pbb)
+	if err != nil {
+		return []string{fmt.Sprintf("decoding block: %v", err)}
+	}
+	if !bytes.Equal(block.Hash(), meta.BlockID.Hash) {
+		errs = append(errs, fmt.Sprintf("block hash %X does not match block ID hash %X", block.Hash(), meta.BlockID.Hash))
+	}
+	if err := block.ValidateBasic(); err != nil {
+		errs = append(errs, fmt.Sprintf("invalid block: %v", err))
+	}
+	return errs
+}
+
+// verifyCommit checks the commit of the block at height h against the stored
+// validator set.
+func (v *storeVerifier) verifyCommit(h int64, meta *types.BlockMeta) []string {
+	var commit *types.Commit
+	err := recoverPanic(func() {
+		if h == v.blockStore.Height() {
+			commit = v.blockStore.LoadSeenCommit(h)
+		} else {
+			commit = v.blockStore.LoadBlockCommit(h)
+		}
+	})
+	switch {
+	case err != nil:
+		return []string{fmt.Sprintf("loading commit: %v", err)}
+	case commit == nil:
+		return []string{"missing commit"}
+	case !commit.BlockID.Equals(meta.BlockID):
+		return []string{fmt.Sprintf("commit is for block ID %v, not %v", commit.BlockID, meta.BlockID)}
+	}
+
+	vals, err := v.stateStore.LoadValidators(h)
+	if err != nil {
+		return []string{fmt.Sprintf("loading validators: %v", err)}
+	}
+	if !bytes.Equal(vals.Hash(), meta.Header.ValidatorsHash) {
+		return []string{fmt.Sprintf("validator set hash %X does not match the header validators hash %X",
+			vals.Hash(), meta.Header.ValidatorsHash)}
+	}
+	if err := vals.VerifyCommit(v.state.ChainID, meta.BlockID, h, commit); err != nil {
+		return []string{fmt.Sprintf("invalid commit: %v", err)}
+	}
+	return nil
+}
+
+// verifyResults checks the ABCI responses stored for height h against the
+// results hash and app hash of the next block or, for the last height, the
+// state. Responses which were not stored or were pruned are skipped.
+func (v *storeVerifier) verifyResults(h int64) []string {
+	var resultsHash, appHash []byte
+	if h == v.state.LastBlockHeight {
+		resultsHash, appHash = v.state.LastResultsHash, v.state.AppHash
+	} else {
+		next, err := v.loadBlockMeta(h + 1)
+		if err != nil || next == nil {
+			return nil
+		}
+		resultsHash, appHash = next.Header.LastResultsHash, next.Header.AppHash
+	}
+
+	resp, err := v.stateStore.LoadFinalizeBlockResponse(h)
+	if err != nil {
+		if errors.Is(err, sm.ErrFinalizeBlockResponsesNotPersisted) || errors.As(err, &sm.ErrNoABCIResponsesForHeight{}) {
+			return nil
+		}
+		return []string{fmt.Sprintf("loading ABCI responses: %v", err)}
+	}
+
+	var errs []string
+	if hash := sm.TxResultsHash(resp.TxResults); !bytes.Equal(hash, resultsHash) {
+		errs = append(errs, fmt.Sprintf("ABCI results hash %X does not match the last results hash %X", hash, resultsHash))
+	}
+	// Responses migrated from the legacy format have no app hash.
+	if len(resp.AppHash) > 0 && !bytes.Equal(resp.AppHash, appHash) {
+		errs = append(errs, fmt.Sprintf("ABCI app hash %X does not match the app hash %X", resp.AppHash, appHash))
+	}
+	return errs
+}
+
+func (v *storeVerifier) loadBlockMeta(h int64) (meta *types.BlockMeta, err error) {
+	err = recoverPanic(func() { meta = v.blockStore.LoadBlockMeta(h) })
+	return meta, err
+}
+
+// recoverPanic calls load, returning the panic raised by the blockstore when
+// it finds corrupted data as an error.
+func recoverPanic(load func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	load()
+	return nil
+}
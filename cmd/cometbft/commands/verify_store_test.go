@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+	abci "github.com/cometbft/cometbft/v2/abci/types"
+	"github.com/cometbft/cometbft/v2/internal/test"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/store"
+	"github.com/cometbft/cometbft/v2/types"
+	cmttime "github.com/cometbft/cometbft/v2/types/time"
+)
+
+const verifyStoreChainID = "verify-store-chain"
+
+// makeVerifyStoreChain saves a chain of n signed blocks, along with their
+// validators and ABCI responses, in new stores.
+func makeVerifyStoreChain(t *testing.T, n int64) (dbm.DB, sm.Store) {
+	t.Helper()
+
+	vals, privVals := test.ValidatorSet(context.Background(), t, 2, 10)
+	blockDB := dbm.NewMemDB()
+	blockStore := store.NewBlockStore(blockDB)
+	stateStore := sm.NewStore(dbm.NewMemDB(), sm.StoreOptions{})
+
+	lastCommit := &types.Commit{}
+	lastBlockID := types.BlockID{}
+	lastResp := &abci.FinalizeBlockResponse{}
+	for h := int64(1); h <= n; h++ {
+		block := types.MakeBlock(h, test.MakeNTxs(h, 2), lastCommit, nil)
+		block.ChainID = verifyStoreChainID
+		block.Time = cmttime.Now()
+		block.LastBlockID = lastBlockID
+		block.ValidatorsHash = vals.Hash()
+		block.NextValidatorsHash = vals.Hash()
+		block.ProposerAddress = vals.GetProposer().Address
+		block.LastResultsHash = sm.TxResultsHash(lastResp.TxResults)
+		block.AppHash = lastResp.AppHash
+
+		parts, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+		commit, err := test.MakeCommit(blockID, h, 0, vals, privVals, verifyStoreChainID, block.Time.Add(time.Second))
+		require.NoError(t, err)
+		blockStore.SaveBlock(block, parts, commit)
+		require.NoError(t, stateStore.SaveValidatorSet(h, vals))
+
+		resp := &abci.FinalizeBlockResponse{
+			TxResults: []*abci.ExecTxResult{{Code: 0, Data: []byte{byte(h)}}, {Code: 1}},
+			AppHash:   []byte{byte(h)},
+		}
+		require.NoError(t, stateStore.SaveFinalizeBlockResponse(h, resp))
+
+		lastCommit, lastBlockID, lastResp = commit, blockID, resp
+	}
+
+	require.NoError(t, stateStore.Save(sm.State{
+		ChainID:                          verifyStoreChainID,
+		InitialHeight:                    1,
+		LastBlockHeight:                  n,
+		LastBlockID:                      lastBlockID,
+		Validators:                       vals,
+		NextValidators:                   vals,
+		LastValidators:                   vals,
+		LastHeightValidatorsChanged:      1,
+		ConsensusParams:                  *types.DefaultConsensusParams(),
+		LastHeightConsensusParamsChanged: 1,
+		LastResultsHash:                  sm.TxResultsHash(lastResp.TxResults),
+		AppHash:                          lastResp.AppHash,
+	}))
+	return blockDB, stateStore
+}
+
+func TestVerifyStore(t *testing.T) {
+	blockDB, stateStore := makeVerifyStoreChain(t, 5)
+
+	report, err := verifyStore(store.NewBlockStore(blockDB), stateStore, 0, 0)
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "%+v", report)
+	assert.EqualValues(t, 1, report.StartHeight)
+	assert.EqualValues(t, 5, report.EndHeight)
+
+	_, err = verifyStore(store.NewBlockStore(blockDB), stateStore, 0, 6)
+	require.ErrorIs(t, err, ErrHeightNotAvailable)
+
+	// Corrupt a block part, and the ABCI responses of another height.
+	require.NoError(t, blockDB.Set([]byte("P:3:0"), []byte("corrupt")))
+	require.NoError(t, stateStore.SaveFinalizeBlockResponse(2, &abci.FinalizeBlockResponse{AppHash: []byte{2}}))
+
+	report, err = verifyStore(store.NewBlockStore(blockDB), stateStore, 0, 0)
+	require.NoError(t, err)
+	assert.False(t, report.OK())
+	assert.Empty(t, report.StoreErrors)
+	require.Len(t, report.CorruptHeights, 2)
+	assert.EqualValues(t, 2, report.CorruptHeights[0].Height)
+	assert.Contains(t, report.CorruptHeights[0].Errors[0], "ABCI results hash")
+	assert.EqualValues(t, 3, report.CorruptHeights[1].Height)
+	assert.Contains(t, report.CorruptHeights[1].Errors[0], "loading block part 0")
+
+	// Heights outside the range are not verified.
+	report, err = verifyStore(store.NewBlockStore(blockDB), stateStore, 4, 5)
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "%+v", report)
+}
@@ -29,6 +29,13 @@ func main() {
 		cmd.RollbackStateCmd,
 		cmd.CompactGoLevelDBCmd,
 		cmd.InspectCmd,
+		cmd.ExportBlocksCmd,
+		cmd.ImportBlocksCmd,
+		cmd.SnapshotCmd,
+		cmd.VerifyStoreCmd,
+		cmd.MigrateDBCmd,
+		cmd.MigrateKeyLayoutCmd,
+		cmd.RecompressDBCmd,
 		debug.DebugCmd,
 		config.Command(),
 		cli.NewCompletionCmd(rootCmd, true),
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -51,6 +52,9 @@ const (
 
 	MempoolTypeFlood = "flood"
 	MempoolTypeNop   = "nop"
+
+	StateProviderRPC  = "rpc"
+	StateProviderGRPC = "grpc"
 )
 
 // NOTE: Most of the structs & relevant comments + the
@@ -148,6 +152,10 @@ func (cfg *Config) SetRoot(root string) *Config {
 	cfg.P2P.RootDir = root
 	cfg.Mempool.RootDir = root
 	cfg.Consensus.RootDir = root
+	cfg.StateSync.RootDir = root
+	cfg.Storage.ColdStorage.RootDir = root
+	cfg.TxIndex.RootDir = root
+	cfg.GRPC.RootDir = root
 	return cfg
 }
 
@@ -181,6 +189,9 @@ func (cfg *Config) ValidateBasic() error {
 	if err := cfg.Storage.ValidateBasic(); err != nil {
 		return fmt.Errorf("error in [storage] section: %w", err)
 	}
+	if err := cfg.TxIndex.ValidateBasic(); err != nil {
+		return ErrInSection{Section: "tx_index", Err: err}
+	}
 	if err := cfg.Instrumentation.ValidateBasic(); err != nil {
 		return ErrInSection{Section: "instrumentation", Err: err}
 	}
@@ -480,6 +491,21 @@ type RPCConfig struct {
 	// https://www.jsonrpc.org/specification#batch
 	MaxRequestBatchSize int `mapstructure:"max_request_batch_size"`
 
+	// Number of requests per second a client, identified by its IP address,
+	// may send on average, over all routes. 0 means unlimited.
+	RateLimitPerClient float64 `mapstructure:"rate_limit_per_client"`
+
+	// Number of requests a client may send at once above
+	// RateLimitPerClient. If 0, it defaults to RateLimitPerClient rounded up.
+	RateLimitBurst int `mapstructure:"rate_limit_burst"`
+
+	// Maximum number of concurrent calls to each of the ExpensiveRoutes,
+	// over all clients. 0 means unlimited.
+	MaxConcurrentExpensiveCalls int `mapstructure:"max_concurrent_expensive_calls"`
+
+	// Routes to which MaxConcurrentExpensiveCalls applies.
+	ExpensiveRoutes []string `mapstructure:"expensive_routes"`
+
 	// Maximum size of request body, in bytes
 	MaxBodyBytes int64 `mapstructure:"max_body_bytes"`
 
@@ -504,6 +530,12 @@ type RPCConfig struct {
 	// Otherwise, HTTP server is run.
 	TLSKeyFile string `mapstructure:"tls_key_file"`
 
+	// The path to a JSON file listing the bearer tokens the clients must
+	// authenticate with, and the routes each of them is allowed to call.
+	// Might be either absolute path or path related to CometBFT's config directory.
+	// If empty, the clients are not authenticated.
+	AuthTokensFile string `mapstructure:"auth_tokens_file"`
+
 	// pprof listen address (https://golang.org/pkg/net/http/pprof)
 	// FIXME: This should be moved under the instrumentation section
 	PprofListenAddress string `mapstructure:"pprof_laddr"`
@@ -530,6 +562,11 @@ func DefaultRPCConfig() *RPCConfig {
 		MaxBodyBytes:        int64(1000000), // 1MB
 		MaxHeaderBytes:      1 << 20,        // same as the net/http default
 
+		RateLimitPerClient:          0, // unlimited
+		RateLimitBurst:              0,
+		MaxConcurrentExpensiveCalls: 0, // unlimited
+		ExpensiveRoutes:             []string{"tx_search", "block_search", "block_results", "tx_count", "block_count"},
+
 		TLSCertFile: "",
 		TLSKeyFile:  "",
 	}
@@ -570,6 +607,15 @@ func (cfg *RPCConfig) ValidateBasic() error {
 	if cfg.MaxRequestBatchSize < 0 {
 		return cmterrors.ErrNegativeField{Field: "max_request_batch_size"}
 	}
+	if cfg.RateLimitPerClient < 0 {
+		return cmterrors.ErrNegativeField{Field: "rate_limit_per_client"}
+	}
+	if cfg.RateLimitBurst < 0 {
+		return cmterrors.ErrNegativeField{Field: "rate_limit_burst"}
+	}
+	if cfg.MaxConcurrentExpensiveCalls < 0 {
+		return cmterrors.ErrNegativeField{Field: "max_concurrent_expensive_calls"}
+	}
 	if cfg.MaxBodyBytes < 0 {
 		return cmterrors.ErrNegativeField{Field: "max_body_bytes"}
 	}
@@ -608,11 +654,31 @@ func (cfg RPCConfig) IsTLSEnabled() bool {
 	return cfg.TLSCertFile != "" && cfg.TLSKeyFile != ""
 }
 
+// AuthTokensFilePath returns the full path to the file listing the tokens of
+// the clients.
+func (cfg RPCConfig) AuthTokensFilePath() string {
+	return authTokensFilePath(cfg.AuthTokensFile, cfg.RootDir)
+}
+
+// IsAuthEnabled returns true if the clients must authenticate with a token.
+func (cfg RPCConfig) IsAuthEnabled() bool {
+	return cfg.AuthTokensFile != ""
+}
+
+func authTokensFilePath(path, rootDir string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return rootify(filepath.Join(DefaultConfigDir, path), rootDir)
+}
+
 // -----------------------------------------------------------------------------
 // GRPCConfig
 
 // GRPCConfig defines the configuration for the CometBFT gRPC server.
 type GRPCConfig struct {
+	RootDir string `mapstructure:"home"`
+
 	// TCP or Unix socket address for the gRPC server to listen on. If empty,
 	// the gRPC server will be disabled.
 	ListenAddress string `mapstructure:"laddr"`
@@ -628,9 +694,20 @@ type GRPCConfig struct {
 	// If no height is provided, the block results of the latest height are returned
 	BlockResultsService *GRPCBlockResultsServiceConfig `mapstructure:"block_results_service"`
 
+	// The gRPC light block service provides the light blocks and consensus
+	// parameters needed by light clients, e.g. to state sync over gRPC
+	LightBlockService *GRPCLightBlockServiceConfig `mapstructure:"light_block_service"`
+
 	// The "privileged" section provides configuration for the gRPC server
 	// dedicated to privileged clients.
 	Privileged *GRPCPrivilegedConfig `mapstructure:"privileged"`
+
+	// The path to a JSON file listing the bearer tokens the clients of the
+	// gRPC servers must authenticate with, and the methods each of them is
+	// allowed to call.
+	// Might be either absolute path or path related to CometBFT's config directory.
+	// If empty, the clients are not authenticated.
+	AuthTokensFile string `mapstructure:"auth_tokens_file"`
 }
 
 func DefaultGRPCConfig() *GRPCConfig {
@@ -639,6 +716,7 @@ func DefaultGRPCConfig() *GRPCConfig {
 		VersionService:      DefaultGRPCVersionServiceConfig(),
 		BlockService:        DefaultGRPCBlockServiceConfig(),
 		BlockResultsService: DefaultGRPCBlockResultsServiceConfig(),
+		LightBlockService:   DefaultGRPCLightBlockServiceConfig(),
 		Privileged:          DefaultGRPCPrivilegedConfig(),
 	}
 }
@@ -649,10 +727,22 @@ func TestGRPCConfig() *GRPCConfig {
 		VersionService:      TestGRPCVersionServiceConfig(),
 		BlockService:        TestGRPCBlockServiceConfig(),
 		BlockResultsService: DefaultGRPCBlockResultsServiceConfig(),
+		LightBlockService:   DefaultGRPCLightBlockServiceConfig(),
 		Privileged:          TestGRPCPrivilegedConfig(),
 	}
 }
 
+// AuthTokensFilePath returns the full path to the file listing the tokens of
+// the clients.
+func (cfg *GRPCConfig) AuthTokensFilePath() string {
+	return authTokensFilePath(cfg.AuthTokensFile, cfg.RootDir)
+}
+
+// IsAuthEnabled returns true if the clients must authenticate with a token.
+func (cfg *GRPCConfig) IsAuthEnabled() bool {
+	return cfg.AuthTokensFile != ""
+}
+
 func (cfg *GRPCConfig) ValidateBasic() error {
 	if len(cfg.ListenAddress) > 0 {
 		addrParts := strings.SplitN(cfg.ListenAddress, "://", 2)
@@ -708,6 +798,16 @@ func TestGRPCBlockServiceConfig() *GRPCBlockServiceConfig {
 	}
 }
 
+type GRPCLightBlockServiceConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+func DefaultGRPCLightBlockServiceConfig() *GRPCLightBlockServiceConfig {
+	return &GRPCLightBlockServiceConfig{
+		Enabled: true,
+	}
+}
+
 // -----------------------------------------------------------------------------
 // GRPCPrivilegedConfig
 
@@ -1102,15 +1202,40 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 
 // StateSyncConfig defines the configuration for the CometBFT state sync service.
 type StateSyncConfig struct {
+	// The root directory for all data.
+	// This should be set in viper so it can unmarshal into this struct
+	RootDir string `mapstructure:"home"`
+
 	Enable              bool          `mapstructure:"enable"`
 	TempDir             string        `mapstructure:"temp_dir"`
+	StateProvider       string        `mapstructure:"state_provider"`
 	RPCServers          []string      `mapstructure:"rpc_servers"`
+	GRPCServers         []string      `mapstructure:"grpc_servers"`
 	TrustPeriod         time.Duration `mapstructure:"trust_period"`
 	TrustHeight         int64         `mapstructure:"trust_height"`
 	TrustHash           string        `mapstructure:"trust_hash"`
 	MaxDiscoveryTime    time.Duration `mapstructure:"max_discovery_time"`
 	ChunkRequestTimeout time.Duration `mapstructure:"chunk_request_timeout"`
 	ChunkFetchers       int32         `mapstructure:"chunk_fetchers"`
+
+	// Limits on the chunk requests from peers served at the same time,
+	// overall and per peer. Requests over the limits are answered as missing
+	// chunks. 0 means unlimited.
+	ServeMaxConcurrent        int `mapstructure:"serve_max_concurrent"`
+	ServeMaxConcurrentPerPeer int `mapstructure:"serve_max_concurrent_per_peer"`
+
+	// ResumePath is the directory where the snapshot being restored and its
+	// chunks are persisted, so that an interrupted state sync resumes the
+	// same snapshot. Empty disables resuming.
+	ResumePath string `mapstructure:"resume_dir"`
+
+	// BackfillHeight is the lowest height down to which the headers, commits
+	// and validator sets below the snapshot are fetched and verified after
+	// state sync. 0 disables backfilling.
+	BackfillHeight int64 `mapstructure:"backfill_height"`
+	// BackfillBlocks also backfills the full blocks, which are then served
+	// like the blocks synced after the snapshot.
+	BackfillBlocks bool `mapstructure:"backfill_blocks"`
 }
 
 func (cfg *StateSyncConfig) TrustHashBytes() []byte {
@@ -1125,6 +1250,7 @@ func (cfg *StateSyncConfig) TrustHashBytes() []byte {
 // DefaultStateSyncConfig returns a default configuration for the state sync service.
 func DefaultStateSyncConfig() *StateSyncConfig {
 	return &StateSyncConfig{
+		StateProvider:       StateProviderRPC,
 		TrustPeriod:         168 * time.Hour,
 		MaxDiscoveryTime:    2 * time.Minute,
 		ChunkRequestTimeout: 10 * time.Second,
@@ -1137,21 +1263,59 @@ func TestStateSyncConfig() *StateSyncConfig {
 	return DefaultStateSyncConfig()
 }
 
+// ResumeDir returns the full path to the state sync resume directory, or an
+// empty string if resuming is disabled.
+func (cfg *StateSyncConfig) ResumeDir() string {
+	if cfg.ResumePath == "" {
+		return ""
+	}
+	return rootify(cfg.ResumePath, cfg.RootDir)
+}
+
 // ValidateBasic performs basic validation.
 func (cfg *StateSyncConfig) ValidateBasic() error {
+	if cfg.ServeMaxConcurrent < 0 {
+		return cmterrors.ErrNegativeField{Field: "serve_max_concurrent"}
+	}
+	if cfg.ServeMaxConcurrentPerPeer < 0 {
+		return cmterrors.ErrNegativeField{Field: "serve_max_concurrent_per_peer"}
+	}
+	if cfg.BackfillHeight < 0 {
+		return cmterrors.ErrNegativeField{Field: "backfill_height"}
+	}
+
 	if cfg.Enable {
-		if len(cfg.RPCServers) == 0 {
-			return cmterrors.ErrRequiredField{Field: "rpc_servers"}
-		}
+		switch cfg.StateProvider {
+		case StateProviderRPC, "":
+			if len(cfg.RPCServers) == 0 {
+				return cmterrors.ErrRequiredField{Field: "rpc_servers"}
+			}
 
-		if len(cfg.RPCServers) < 2 {
-			return ErrNotEnoughRPCServers
-		}
+			if len(cfg.RPCServers) < 2 {
+				return ErrNotEnoughRPCServers
+			}
 
-		for _, server := range cfg.RPCServers {
-			if len(server) == 0 {
-				return ErrEmptyRPCServerEntry
+			for _, server := range cfg.RPCServers {
+				if len(server) == 0 {
+					return ErrEmptyRPCServerEntry
+				}
+			}
+		case StateProviderGRPC:
+			if len(cfg.GRPCServers) == 0 {
+				return cmterrors.ErrRequiredField{Field: "grpc_servers"}
+			}
+
+			if len(cfg.GRPCServers) < 2 {
+				return ErrNotEnoughGRPCServers
 			}
+
+			for _, server := range cfg.GRPCServers {
+				if len(server) == 0 {
+					return ErrEmptyGRPCServerEntry
+				}
+			}
+		default:
+			return ErrUnknownStateProvider{Provider: cfg.StateProvider}
 		}
 
 		if cfg.MaxDiscoveryTime < 0 {
@@ -1193,6 +1357,9 @@ func (cfg *StateSyncConfig) PossibleMisconfigurations() []string {
 	if !cfg.Enable && len(cfg.RPCServers) != 0 {
 		return []string{"rpc_servers specified but enable = false"}
 	}
+	if !cfg.Enable && len(cfg.GRPCServers) != 0 {
+		return []string{"grpc_servers specified but enable = false"}
+	}
 	return []string{}
 }
 
@@ -1202,12 +1369,39 @@ func (cfg *StateSyncConfig) PossibleMisconfigurations() []string {
 // BlockSyncConfig (formerly known as FastSync) defines the configuration for the CometBFT block sync service.
 type BlockSyncConfig struct {
 	Version string `mapstructure:"version"`
+
+	// VerifyWindow is the number of blocks ahead of the one being applied
+	// whose commits are verified concurrently. 0 disables pipelined
+	// verification.
+	VerifyWindow int `mapstructure:"verify_window"`
+
+	// HeaderSync enables advancing a chain of trusted headers ahead of the
+	// downloaded blocks, using the light client's skipping verification
+	// against HeaderSyncRPCServers. Blocks linked to a trusted header by hash
+	// do not need their commit verified.
+	HeaderSync              bool          `mapstructure:"header_sync"`
+	HeaderSyncRPCServers    []string      `mapstructure:"header_sync_rpc_servers"`
+	HeaderSyncTrustPeriod   time.Duration `mapstructure:"header_sync_trust_period"`
+	HeaderSyncPivotInterval int64         `mapstructure:"header_sync_pivot_interval"`
+
+	// Limits on the block requests from peers served at the same time,
+	// overall and per peer. Requests over the limits are answered as if the
+	// block was missing. 0 means unlimited.
+	ServeMaxConcurrent        int `mapstructure:"serve_max_concurrent"`
+	ServeMaxConcurrentPerPeer int `mapstructure:"serve_max_concurrent_per_peer"`
+
+	// ServeWindow restricts the blocks served to peers to the latest
+	// ServeWindow heights of the block store. 0 serves all stored blocks.
+	ServeWindow int64 `mapstructure:"serve_window"`
 }
 
 // DefaultBlockSyncConfig returns a default configuration for the block sync service.
 func DefaultBlockSyncConfig() *BlockSyncConfig {
 	return &BlockSyncConfig{
-		Version: "v0",
+		Version:                 "v0",
+		VerifyWindow:            16,
+		HeaderSyncTrustPeriod:   168 * time.Hour,
+		HeaderSyncPivotInterval: 100,
 	}
 }
 
@@ -1218,6 +1412,36 @@ func TestBlockSyncConfig() *BlockSyncConfig {
 
 // ValidateBasic performs basic validation.
 func (cfg *BlockSyncConfig) ValidateBasic() error {
+	if cfg.VerifyWindow < 0 {
+		return cmterrors.ErrNegativeField{Field: "verify_window"}
+	}
+	if cfg.ServeMaxConcurrent < 0 {
+		return cmterrors.ErrNegativeField{Field: "serve_max_concurrent"}
+	}
+	if cfg.ServeMaxConcurrentPerPeer < 0 {
+		return cmterrors.ErrNegativeField{Field: "serve_max_concurrent_per_peer"}
+	}
+	if cfg.ServeWindow < 0 {
+		return cmterrors.ErrNegativeField{Field: "serve_window"}
+	}
+
+	if cfg.HeaderSync {
+		if len(cfg.HeaderSyncRPCServers) == 0 {
+			return cmterrors.ErrRequiredField{Field: "header_sync_rpc_servers"}
+		}
+		for _, server := range cfg.HeaderSyncRPCServers {
+			if len(server) == 0 {
+				return ErrEmptyRPCServerEntry
+			}
+		}
+		if cfg.HeaderSyncTrustPeriod <= 0 {
+			return cmterrors.ErrRequiredField{Field: "header_sync_trust_period"}
+		}
+		if cfg.HeaderSyncPivotInterval <= 0 {
+			return cmterrors.ErrRequiredField{Field: "header_sync_pivot_interval"}
+		}
+	}
+
 	switch cfg.Version {
 	case v0:
 		return nil
@@ -1405,6 +1629,14 @@ type StorageConfig struct {
 	// Not that this is an experimental feature and switching back from v2 to v1
 	// is not supported by CometBFT.
 	ExperimentalKeyLayout string `mapstructure:"experimental_db_key_layout"`
+
+	// Compression of the block parts and ABCI responses written to the
+	// databases: "none" or "zstd". Records written before a change remain
+	// readable; the recompress-db command rewrites them.
+	Compression string `mapstructure:"compression"`
+
+	// Configuration related to moving old blocks to a cold database.
+	ColdStorage *ColdStorageConfig `mapstructure:"cold_storage"`
 }
 
 // DefaultStorageConfig returns the default configuration options relating to
@@ -1416,6 +1648,8 @@ func DefaultStorageConfig() *StorageConfig {
 		Compact:               false,
 		CompactionInterval:    1000,
 		ExperimentalKeyLayout: "v1",
+		Compression:           "none",
+		ColdStorage:           DefaultColdStorageConfig(),
 	}
 }
 
@@ -1425,6 +1659,8 @@ func TestStorageConfig() *StorageConfig {
 	return &StorageConfig{
 		DiscardABCIResponses: false,
 		Pruning:              TestPruningConfig(),
+		Compression:          "none",
+		ColdStorage:          DefaultColdStorageConfig(),
 	}
 }
 
@@ -1435,6 +1671,12 @@ func (cfg *StorageConfig) ValidateBasic() error {
 	if cfg.ExperimentalKeyLayout != "v1" && cfg.ExperimentalKeyLayout != "v2" {
 		return fmt.Errorf("unsupported version of DB Key layout, expected v1 or v2, got %s", cfg.ExperimentalKeyLayout)
 	}
+	if cfg.Compression != "none" && cfg.Compression != "zstd" {
+		return fmt.Errorf("unsupported compression, expected none or zstd, got %s", cfg.Compression)
+	}
+	if err := cfg.ColdStorage.ValidateBasic(); err != nil {
+		return fmt.Errorf("error in [cold_storage] section: %w", err)
+	}
 	return nil
 }
 
@@ -1452,6 +1694,8 @@ func (cfg *StorageConfig) ValidateBasic() error {
 // TxIndexConfig defines the configuration for the transaction indexer,
 // including composite keys to index.
 type TxIndexConfig struct {
+	RootDir string `mapstructure:"home"`
+
 	// What indexer to use for transactions
 	//
 	// Options:
@@ -1459,12 +1703,39 @@ type TxIndexConfig struct {
 	//   2) "kv" (default) - the simplest possible indexer,
 	//      backed by key-value storage (defaults to levelDB; see DBBackend).
 	//   3) "psql" - the indexer services backed by PostgreSQL.
+	//   4) "sqlite" - the indexer services backed by an embedded SQLite
+	//      database (see SqlitePath).
+	//   5) "jsonl" - the events written as JSON lines into files rotated by
+	//      height range, for offline analytics (see JSONLDir).
+	//
+	// A comma-separated list of "kv", "psql", "sqlite" and "jsonl" indexes the
+	// events to each of them, e.g. "kv,psql". The first one serves the RPC
+	// searches.
 	Indexer string `mapstructure:"indexer"`
 
 	// The PostgreSQL connection configuration, the connection format:
 	// postgresql://<user>:<password>@<host>:<port>/<db>?<opts>
 	PsqlConn string `mapstructure:"psql-conn"`
 
+	// The SQLite database file, relative to the home directory if not
+	// absolute.
+	SqlitePath string `mapstructure:"sqlite-path"`
+
+	// The directory of the files of the "jsonl" indexer, relative to the home
+	// directory if not absolute.
+	JSONLDir string `mapstructure:"jsonl-dir"`
+
+	// The number of consecutive heights written to each file of the "jsonl"
+	// indexer before rotating to the next one.
+	JSONLFileHeights int64 `mapstructure:"jsonl-file-heights"`
+
+	// The types of the events indexed by the "kv" indexer. All the types are
+	// indexed if empty.
+	IndexEvents []string `mapstructure:"index_events"`
+
+	// The types of the events not indexed by the "kv" indexer.
+	ExcludeEvents []string `mapstructure:"exclude_events"`
+
 	// The PostgreSQL table that stores indexed blocks.
 	TableBlocks string `mapstructure:"table_blocks"`
 	// The PostgreSQL table that stores indexed transaction results.
@@ -1478,8 +1749,69 @@ type TxIndexConfig struct {
 // DefaultTxIndexConfig returns a default configuration for the transaction indexer.
 func DefaultTxIndexConfig() *TxIndexConfig {
 	return &TxIndexConfig{
-		Indexer: "kv",
+		Indexer:          "kv",
+		SqlitePath:       filepath.Join(DefaultDataDir, "tx_index.sqlite"),
+		JSONLDir:         filepath.Join(DefaultDataDir, "tx_index_jsonl"),
+		JSONLFileHeights: 10000,
+		IndexEvents:      []string{},
+		ExcludeEvents:    []string{},
+	}
+}
+
+// SqliteFile returns the full path to the database file of the sqlite
+// indexer.
+func (cfg *TxIndexConfig) SqliteFile() string {
+	return rootify(cfg.SqlitePath, cfg.RootDir)
+}
+
+// JSONLDirPath returns the full path to the directory of the files of the
+// jsonl indexer.
+func (cfg *TxIndexConfig) JSONLDirPath() string {
+	return rootify(cfg.JSONLDir, cfg.RootDir)
+}
+
+// Indexers returns the list of indexers to which the events are indexed. The
+// first one serves the RPC searches.
+func (cfg *TxIndexConfig) Indexers() []string {
+	var indexers []string
+	for _, indexer := range strings.Split(cfg.Indexer, ",") {
+		if indexer = strings.TrimSpace(indexer); indexer != "" {
+			indexers = append(indexers, indexer)
+		}
+	}
+	return indexers
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *TxIndexConfig) ValidateBasic() error {
+	indexers := cfg.Indexers()
+	seen := make(map[string]bool, len(indexers))
+	for _, indexer := range indexers {
+		if len(indexers) > 1 {
+			switch indexer {
+			case "kv", "psql", "sqlite", "jsonl":
+			default:
+				return fmt.Errorf("indexer %q cannot be combined with other indexers (must be \"kv\", \"psql\", \"sqlite\" or \"jsonl\")", indexer)
+			}
+		}
+		if seen[indexer] {
+			return fmt.Errorf("duplicate indexer %q", indexer)
+		}
+		seen[indexer] = true
+	}
+	if seen["jsonl"] && cfg.JSONLFileHeights <= 0 {
+		return errors.New("jsonl-file-heights must be positive")
+	}
+	if slices.Contains(cfg.IndexEvents, "") || slices.Contains(cfg.ExcludeEvents, "") {
+		return errors.New("found empty event type in index_events or exclude_events")
+	}
+	for _, eventType := range cfg.ExcludeEvents {
+		if slices.Contains(cfg.IndexEvents, eventType) {
+			return fmt.Errorf("event type %q is both in index_events and exclude_events", eventType)
+		}
 	}
+	return nil
 }
 
 // TestTxIndexConfig returns a default configuration for the transaction indexer.
@@ -1572,6 +1904,12 @@ func getDefaultMoniker() string {
 type PruningConfig struct {
 	// The time period between automated background pruning operations.
 	Interval time.Duration `mapstructure:"interval"`
+	// The blocks, ABCI results and indexed data less than these durations old
+	// are not pruned, even if the retain heights set by the application or the
+	// data companion allow it. 0 to only use the retain heights.
+	BlocksRetainTime      time.Duration `mapstructure:"blocks_retain_time"`
+	ABCIResultsRetainTime time.Duration `mapstructure:"abci_results_retain_time"`
+	IndexerRetainTime     time.Duration `mapstructure:"indexer_retain_time"`
 	// Data companion-related pruning configuration.
 	DataCompanion *DataCompanionPruningConfig `mapstructure:"data_companion"`
 }
@@ -1594,12 +1932,81 @@ func (cfg *PruningConfig) ValidateBasic() error {
 	if cfg.Interval <= 0 {
 		return errors.New("interval must be > 0")
 	}
+	if cfg.BlocksRetainTime < 0 {
+		return cmterrors.ErrNegativeField{Field: "blocks_retain_time"}
+	}
+	if cfg.ABCIResultsRetainTime < 0 {
+		return cmterrors.ErrNegativeField{Field: "abci_results_retain_time"}
+	}
+	if cfg.IndexerRetainTime < 0 {
+		return cmterrors.ErrNegativeField{Field: "indexer_retain_time"}
+	}
 	if err := cfg.DataCompanion.ValidateBasic(); err != nil {
 		return fmt.Errorf("error in [data_companion] section: %w", err)
 	}
 	return nil
 }
 
+// -----------------------------------------------------------------------------
+// ColdStorageConfig
+
+// ColdStorageConfig defines the configuration for moving old blocks from the
+// blockstore database to a secondary, cold, database, e.g. on cheaper disks.
+type ColdStorageConfig struct {
+	RootDir string `mapstructure:"home"`
+
+	// Whether old blocks are moved to the cold database. Disabled by default.
+	Enabled bool `mapstructure:"enabled"`
+	// Database backend of the cold database. If empty, db_backend is used.
+	DBBackend string `mapstructure:"db_backend"`
+	// Database directory of the cold database, relative to the home directory
+	// if not absolute.
+	DBPath string `mapstructure:"db_dir"`
+	// Blocks are moved once they are more than AfterBlocks blocks behind the
+	// latest height. 0 to ignore the height of blocks.
+	AfterBlocks int64 `mapstructure:"after_blocks"`
+	// Blocks are moved once they are older than AfterTime. 0 to ignore the
+	// age of blocks.
+	AfterTime time.Duration `mapstructure:"after_time"`
+}
+
+// DefaultColdStorageConfig returns a default configuration for the cold
+// storage of blocks.
+func DefaultColdStorageConfig() *ColdStorageConfig {
+	return &ColdStorageConfig{
+		Enabled:     false,
+		DBPath:      "data/cold",
+		AfterBlocks: 100000,
+		AfterTime:   0,
+	}
+}
+
+// DBDir returns the full path to the directory of the cold database.
+func (cfg *ColdStorageConfig) DBDir() string {
+	return rootify(cfg.DBPath, cfg.RootDir)
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *ColdStorageConfig) ValidateBasic() error {
+	if cfg.AfterBlocks < 0 {
+		return cmterrors.ErrNegativeField{Field: "after_blocks"}
+	}
+	if cfg.AfterTime < 0 {
+		return cmterrors.ErrNegativeField{Field: "after_time"}
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.DBPath == "" {
+		return errors.New("db_dir must be set")
+	}
+	if cfg.AfterBlocks == 0 && cfg.AfterTime == 0 {
+		return errors.New("either after_blocks or after_time must be set")
+	}
+	return nil
+}
+
 // -----------------------------------------------------------------------------
 // DataCompanionPruningConfig
 
@@ -158,6 +158,26 @@ timeout_broadcast_tx_commit = "{{ .RPC.TimeoutBroadcastTxCommit }}"
 # enforced for a JSON-RPC batch request.
 max_request_batch_size = {{ .RPC.MaxRequestBatchSize }}
 
+# Number of requests per second a client, identified by its IP address, may
+# send on average, over all the routes. The requests above the limit are
+# rejected with a JSON-RPC error (code -32005) and, if not in a batch, the HTTP
+# status 429. If the value is set to '0' (zero-value), the clients are not
+# rate limited.
+rate_limit_per_client = {{ .RPC.RateLimitPerClient }}
+
+# Number of requests a client may send at once above rate_limit_per_client.
+# If the value is set to '0' (zero-value), it defaults to
+# rate_limit_per_client rounded up.
+rate_limit_burst = {{ .RPC.RateLimitBurst }}
+
+# Maximum number of concurrent calls to each of the expensive_routes, over all
+# the clients. The calls above the limit are rejected like the rate limited
+# ones. If the value is set to '0' (zero-value), the calls are not limited.
+max_concurrent_expensive_calls = {{ .RPC.MaxConcurrentExpensiveCalls }}
+
+# Routes to which max_concurrent_expensive_calls applies.
+expensive_routes = [{{ range .RPC.ExpensiveRoutes }}{{ printf "%q, " . }}{{end}}]
+
 # Maximum size of request body, in bytes
 max_body_bytes = {{ .RPC.MaxBodyBytes }}
 
@@ -179,6 +199,25 @@ tls_cert_file = "{{ .RPC.TLSCertFile }}"
 # Otherwise, HTTP server is run.
 tls_key_file = "{{ .RPC.TLSKeyFile }}"
 
+# The path to a JSON file listing the bearer tokens the clients must
+# authenticate with, in the "Authorization: Bearer <token>" header, and the
+# routes each of them is allowed to call, e.g.:
+#
+#   {
+#     "tokens": [
+#       {"name": "explorer", "token": "<secret>", "routes": ["status", "block*", "tx*"]},
+#       {"name": "wallet", "token": "<secret>", "routes": ["broadcast_tx_*"]}
+#     ],
+#     "jwt_secret": "<secret>"
+#   }
+#
+# The routes are patterns in the syntax of Go's path.Match. If jwt_secret is
+# set, JWTs signed with it (HS256) are accepted too, with the patterns of the
+# routes they allow in their "routes" claim.
+# Might be either absolute path or path related to CometBFT's config directory.
+# If empty, the clients are not authenticated.
+auth_tokens_file = "{{ .RPC.AuthTokensFile }}"
+
 # pprof listen address (https://golang.org/pkg/net/http/pprof)
 pprof_laddr = "{{ .RPC.PprofListenAddress }}"
 
@@ -201,6 +240,19 @@ pprof_laddr = "{{ .RPC.PprofListenAddress }}"
 # the gRPC server will be disabled.
 laddr = "{{ .GRPC.ListenAddress }}"
 
+# The path to a JSON file listing the bearer tokens the clients of the gRPC
+# servers, including the privileged one, must authenticate with, in the
+# "authorization" metadata, and the methods each of them is allowed to call.
+# The format is the same as the one of rpc.auth_tokens_file, the routes being
+# the full names of the methods, e.g.
+# "cometbft.services.block.v2.BlockService/GetByHeight". As in path.Match, "*"
+# does not match the "/" before the method: all the methods of a service are
+# allowed by e.g. "cometbft.services.block.v2.BlockService/*", and all the
+# methods of every service by a bare "*".
+# Might be either absolute path or path related to CometBFT's config directory.
+# If empty, the clients are not authenticated.
+auth_tokens_file = "{{ .GRPC.AuthTokensFile }}"
+
 #
 # Each gRPC service can be turned on/off, and in some cases configured,
 # individually. If the gRPC server is not enabled, all individual services'
@@ -221,6 +273,11 @@ enabled = {{ .GRPC.BlockService.Enabled }}
 [grpc.block_results_service]
 enabled = {{ .GRPC.BlockResultsService.Enabled }}
 
+# The gRPC light block service returns the signed headers, validator sets and
+# consensus parameters light clients need, e.g. for state sync over gRPC.
+[grpc.light_block_service]
+enabled = {{ .GRPC.LightBlockService.Enabled }}
+
 #
 # Configuration for privileged gRPC endpoints, which should **never** be exposed
 # to the public internet.
@@ -408,6 +465,11 @@ dog_adjust_interval = "{{ .Mempool.DOGAdjustInterval }}"
 # starting from the height of the snapshot.
 enable = {{ .StateSync.Enable }}
 
+# Where the light client fetches the data used to verify the synced state machine:
+# "rpc" uses the RPC servers in rpc_servers, "grpc" uses the light block service of the gRPC
+# servers in grpc_servers ([grpc.light_block_service] must be enabled on these nodes).
+state_provider = "{{ .StateSync.StateProvider }}"
+
 # RPC servers (comma-separated) for light client verification of the synced state machine and
 # retrieval of state data for node bootstrapping. Also needs a trusted height and corresponding
 # header hash obtained from a trusted source, and a period during which validators can be trusted.
@@ -419,6 +481,10 @@ trust_height = {{ .StateSync.TrustHeight }}
 trust_hash = "{{ .StateSync.TrustHash }}"
 trust_period = "{{ .StateSync.TrustPeriod }}"
 
+# gRPC servers (comma-separated host:port addresses) used instead of rpc_servers when
+# state_provider = "grpc".
+grpc_servers = "{{ StringsJoin .StateSync.GRPCServers "," }}"
+
 # Time to spend discovering snapshots before switching to blocksync. If set to
 # 0, state sync will be trying indefinitely.
 max_discovery_time = "{{ .StateSync.MaxDiscoveryTime }}"
@@ -434,6 +500,32 @@ chunk_request_timeout = "{{ .StateSync.ChunkRequestTimeout }}"
 # The number of concurrent chunk fetchers to run (default: 1).
 chunk_fetchers = "{{ .StateSync.ChunkFetchers }}"
 
+# Maximum number of snapshot chunk requests from peers served at the same time,
+# overall and per peer. Requests over these limits are answered as missing, so
+# that the peer asks someone else. 0 means unlimited.
+serve_max_concurrent = {{ .StateSync.ServeMaxConcurrent }}
+serve_max_concurrent_per_peer = {{ .StateSync.ServeMaxConcurrentPerPeer }}
+
+# Directory, relative to the home directory if not absolute, where the snapshot
+# being restored and the chunks already fetched are kept, so that a state sync
+# interrupted by a restart resumes the same snapshot. Chunks acknowledged by the
+# application are not applied again, so the application must keep its restore
+# progress across restarts when the same snapshot is offered again.
+# Empty disables resuming.
+resume_dir = "{{ js .StateSync.ResumePath }}"
+
+# Lowest height down to which the headers, commits and validator sets below the
+# snapshot are fetched from the state provider after state sync, verified
+# against the snapshot commit, and stored, in the background while the node
+# syncs the following blocks. They are then available to verify evidence.
+# 0 disables backfilling.
+backfill_height = {{ .StateSync.BackfillHeight }}
+
+# Also backfill the full blocks, lowering the base of the block store so that
+# they are served to peers and RPC clients. With state_provider = "grpc", the
+# block service must be enabled on the gRPC servers.
+backfill_blocks = {{ .StateSync.BackfillBlocks }}
+
 #######################################################
 ###       Block Sync Configuration Options          ###
 #######################################################
@@ -447,6 +539,35 @@ chunk_fetchers = "{{ .StateSync.ChunkFetchers }}"
 #   1) "v0" - the default block sync implementation
 version = "{{ .BlockSync.Version }}"
 
+# Number of blocks ahead of the one being applied whose commit signatures are
+# verified concurrently, provided their validator set is already known.
+# Blocks are still applied in order. Set to 0 to disable.
+verify_window = {{ .BlockSync.VerifyWindow }}
+
+# When far behind, first advance a chain of trusted headers using the light
+# client's skipping verification against the RPC servers below (comma-separated),
+# then verify downloaded blocks by linking them by hash to those headers instead
+# of checking every commit. Requires the node's latest header to be within
+# header_sync_trust_period. Blocks are still validated and executed in order.
+header_sync = {{ .BlockSync.HeaderSync }}
+header_sync_rpc_servers = "{{ StringsJoin .BlockSync.HeaderSyncRPCServers "," }}"
+header_sync_trust_period = "{{ .BlockSync.HeaderSyncTrustPeriod }}"
+
+# Maximum distance between two headers verified by the light client.
+header_sync_pivot_interval = {{ .BlockSync.HeaderSyncPivotInterval }}
+
+# Maximum number of block requests from peers served at the same time, overall
+# and per peer. Requests over these limits are answered as if the block was
+# missing, so that the peer asks someone else. 0 means unlimited. A request
+# counts against the limits until its block is sent, and the requests of a peer
+# are answered in the order they were received.
+serve_max_concurrent = {{ .BlockSync.ServeMaxConcurrent }}
+serve_max_concurrent_per_peer = {{ .BlockSync.ServeMaxConcurrentPerPeer }}
+
+# Only serve the latest serve_window blocks of the block store to peers.
+# 0 serves every stored block.
+serve_window = {{ .BlockSync.ServeWindow }}
+
 #######################################################
 ###         Consensus Configuration Options         ###
 #######################################################
@@ -502,6 +623,14 @@ discard_abci_responses = {{ .Storage.DiscardABCIResponses}}
 # v2 - Order preserving representation ordering entries by height.
 experimental_db_key_layout = "{{ .Storage.ExperimentalKeyLayout }}"
 
+# Compression of the block parts and ABCI responses written to the databases.
+# none - records are stored as they are.
+# zstd - records are compressed with zstd.
+# Records written before changing this setting remain readable. To rewrite
+# them with the new setting, run "cometbft recompress-db" while the node is
+# stopped.
+compression = "{{ .Storage.Compression }}"
+
 # If set to true, CometBFT will force compaction to happen for databases that support this feature.
 # and save on storage space. Setting this to true is most benefits when used in combination
 # with pruning as it will physically delete the entries marked for deletion.
@@ -515,11 +644,42 @@ compact = {{ .Storage.Compact }}
 # large multiple of your retain height as it might occur bigger overheads.
 compaction_interval = "{{ .Storage.CompactionInterval }}"
 
+#
+# Storage of old blocks in a secondary, cold, database, e.g. on cheaper disks.
+# The parts and commits of the blocks are moved by the pruner, and are read
+# from the cold database transparently.
+#
+[storage.cold_storage]
+
+# Whether old blocks are moved to the cold database. Disabled by default.
+enabled = {{ .Storage.ColdStorage.Enabled }}
+
+# Database backend of the cold database. If empty, db_backend is used.
+db_backend = "{{ .Storage.ColdStorage.DBBackend }}"
+
+# Database directory of the cold database, relative to the home directory if
+# not absolute.
+db_dir = "{{ js .Storage.ColdStorage.DBPath }}"
+
+# Blocks are moved once they are more than after_blocks blocks behind the
+# latest height, and older than after_time. Set either one to 0 to ignore it.
+after_blocks = {{ .Storage.ColdStorage.AfterBlocks }}
+after_time = "{{ .Storage.ColdStorage.AfterTime }}"
+
 [storage.pruning]
 
 # The time period between automated background pruning operations.
 interval = "{{ .Storage.Pruning.Interval }}"
 
+# Minimum time to keep the blocks, the ABCI results and the indexed data,
+# whatever the retain heights set by the application or the data companion.
+# The pruner lowers the retain heights to the height of the first block within
+# each duration, using the block times. For example, "2160h" keeps at least 90
+# days of data. 0 to only use the retain heights.
+blocks_retain_time = "{{ .Storage.Pruning.BlocksRetainTime }}"
+abci_results_retain_time = "{{ .Storage.Pruning.ABCIResultsRetainTime }}"
+indexer_retain_time = "{{ .Storage.Pruning.IndexerRetainTime }}"
+
 #
 # Storage pruning configuration relating only to the data companion.
 #
@@ -560,13 +720,41 @@ initial_block_results_retain_height = {{ .Storage.Pruning.DataCompanion.InitialB
 #   2) "kv" (default) - the simplest possible indexer, backed by key-value storage (defaults to levelDB; see DBBackend).
 # 		- When "kv" is chosen "tx.height" and "tx.hash" will always be indexed.
 #   3) "psql" - the indexer services backed by PostgreSQL.
-# When "kv" or "psql" is chosen "tx.height" and "tx.hash" will always be indexed.
+#   4) "sqlite" - the indexer services backed by an embedded SQLite database.
+#   5) "jsonl" - the events written as JSON lines into files rotated by height range.
+# When "kv", "psql" or "sqlite" is chosen "tx.height" and "tx.hash" will always be indexed.
+#
+# A comma-separated list of "kv", "psql", "sqlite" and "jsonl" indexes the
+# events to each of them, e.g. "kv,psql" for local searches and analytics in
+# PostgreSQL. The first one serves the RPC searches. A sink lagging behind, e.g. because its
+# database is unavailable, catches up independently of the others.
 indexer = "{{ .TxIndex.Indexer }}"
 
 # The PostgreSQL connection configuration, the connection format:
 #   postgresql://<user>:<password>@<host>:<port>/<db>?<opts>
 psql-conn = "{{ .TxIndex.PsqlConn }}"
 
+# The SQLite database file of the "sqlite" indexer, relative to the home
+# directory if not absolute.
+sqlite-path = "{{ js .TxIndex.SqlitePath }}"
+
+# The directory of the files of the "jsonl" indexer, relative to the home
+# directory if not absolute.
+jsonl-dir = "{{ js .TxIndex.JSONLDir }}"
+
+# The number of consecutive heights written to each file of the "jsonl"
+# indexer before rotating to the next one.
+jsonl-file-heights = {{ .TxIndex.JSONLFileHeights }}
+
+# The types of the events indexed by the "kv" indexer, e.g. ["transfer",
+# "message"], to reduce the cost of indexing the events which are never
+# searched. All the types are indexed if empty. The height and hash of the
+# transactions, and the height of the blocks, are always indexed.
+index_events = [{{ range .TxIndex.IndexEvents }}{{ printf "%q, " . }}{{end}}]
+
+# The types of the events not indexed by the "kv" indexer.
+exclude_events = [{{ range .TxIndex.ExcludeEvents }}{{ printf "%q, " . }}{{end}}]
+
 #######################################################
 ###       Instrumentation Configuration Options     ###
 #######################################################
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/cometbft/cometbft/v2/config"
+	cmterrors "github.com/cometbft/cometbft/v2/types/errors"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -59,6 +60,21 @@ func TestTLSConfiguration(t *testing.T) {
 	assert.Equal("/abs/path/to/file.key", cfg.RPC.KeyFile())
 }
 
+func TestAuthTokensFile(t *testing.T) {
+	assert := assert.New(t)
+	cfg := config.DefaultConfig()
+	cfg.SetRoot("/home/user")
+	assert.False(cfg.RPC.IsAuthEnabled())
+	assert.False(cfg.GRPC.IsAuthEnabled())
+
+	cfg.RPC.AuthTokensFile = "rpc_auth.json"
+	assert.True(cfg.RPC.IsAuthEnabled())
+	assert.Equal("/home/user/config/rpc_auth.json", cfg.RPC.AuthTokensFilePath())
+	cfg.GRPC.AuthTokensFile = "/abs/path/to/grpc_auth.json"
+	assert.True(cfg.GRPC.IsAuthEnabled())
+	assert.Equal("/abs/path/to/grpc_auth.json", cfg.GRPC.AuthTokensFilePath())
+}
+
 func TestBaseConfigValidateBasic(t *testing.T) {
 	cfg := config.TestBaseConfig()
 	require.NoError(t, cfg.ValidateBasic())
@@ -109,6 +125,8 @@ func TestRPCConfigValidateBasic(t *testing.T) {
 		"MaxBodyBytes",
 		"MaxHeaderBytes",
 		"MaxRequestBatchSize",
+		"RateLimitBurst",
+		"MaxConcurrentExpensiveCalls",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -116,6 +134,9 @@ func TestRPCConfigValidateBasic(t *testing.T) {
 		require.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg.RateLimitPerClient = -1
+	require.Error(t, cfg.ValidateBasic())
 }
 
 func TestP2PConfigValidateBasic(t *testing.T) {
@@ -204,6 +225,23 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 func TestStateSyncConfigValidateBasic(t *testing.T) {
 	cfg := config.TestStateSyncConfig()
 	require.NoError(t, cfg.ValidateBasic())
+
+	cfg.Enable = true
+	cfg.TrustHeight = 1
+	cfg.TrustHash = "0A"
+	cfg.RPCServers = []string{"first_rpc", "second_rpc"}
+	require.NoError(t, cfg.ValidateBasic())
+
+	// the gRPC state provider requires gRPC servers
+	cfg.StateProvider = config.StateProviderGRPC
+	require.ErrorIs(t, cfg.ValidateBasic(), cmterrors.ErrRequiredField{Field: "grpc_servers"})
+	cfg.GRPCServers = []string{"first_grpc"}
+	require.ErrorIs(t, cfg.ValidateBasic(), config.ErrNotEnoughGRPCServers)
+	cfg.GRPCServers = []string{"first_grpc", "second_grpc"}
+	require.NoError(t, cfg.ValidateBasic())
+
+	cfg.StateProvider = "invalid"
+	require.ErrorAs(t, cfg.ValidateBasic(), &config.ErrUnknownStateProvider{})
 }
 
 func TestBlockSyncConfigValidateBasic(t *testing.T) {
@@ -264,6 +302,36 @@ func TestInstrumentationConfigValidateBasic(t *testing.T) {
 	require.Error(t, cfg.ValidateBasic())
 }
 
+func TestTxIndexConfigValidateBasic(t *testing.T) {
+	cfg := config.TestTxIndexConfig()
+	require.NoError(t, cfg.ValidateBasic())
+	require.Equal(t, []string{"kv"}, cfg.Indexers())
+
+	cfg.Indexer = "kv, psql"
+	require.NoError(t, cfg.ValidateBasic())
+	require.Equal(t, []string{"kv", "psql"}, cfg.Indexers())
+
+	cfg.Indexer = "kv,null"
+	require.Error(t, cfg.ValidateBasic())
+
+	cfg.Indexer = "kv,sqlite,kv"
+	require.Error(t, cfg.ValidateBasic())
+
+	cfg.Indexer = "kv,jsonl"
+	require.NoError(t, cfg.ValidateBasic())
+	cfg.JSONLFileHeights = 0
+	require.Error(t, cfg.ValidateBasic())
+
+	cfg = config.TestTxIndexConfig()
+	cfg.IndexEvents = []string{"transfer", "message"}
+	cfg.ExcludeEvents = []string{"coin_received"}
+	require.NoError(t, cfg.ValidateBasic())
+	cfg.ExcludeEvents = []string{"message"}
+	require.Error(t, cfg.ValidateBasic())
+	cfg.ExcludeEvents = []string{""}
+	require.Error(t, cfg.ValidateBasic())
+}
+
 func TestConfigPossibleMisconfigurations(t *testing.T) {
 	cfg := config.DefaultConfig()
 	require.Len(t, cfg.PossibleMisconfigurations(), 0)
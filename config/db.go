@@ -27,3 +27,18 @@ func DefaultDBProvider(ctx *DBContext) (dbm.DB, error) {
 
 	return dbm.NewDB(ctx.ID, dbType, ctx.Config.DBDir())
 }
+
+// ColdBlockStoreDB returns the cold database of the blockstore, using the
+// backend and directory specified in the [storage.cold_storage] section of the
+// Config, or nil if the cold storage of blocks is disabled.
+func ColdBlockStoreDB(config *Config) (dbm.DB, error) {
+	coldCfg := config.Storage.ColdStorage
+	if coldCfg == nil || !coldCfg.Enabled {
+		return nil, nil
+	}
+	backend := coldCfg.DBBackend
+	if backend == "" {
+		backend = config.DBBackend
+	}
+	return dbm.NewDB("blockstore", dbm.BackendType(backend), coldCfg.DBDir())
+}
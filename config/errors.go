@@ -8,6 +8,8 @@ import (
 var (
 	ErrEmptyRPCServerEntry             = errors.New("found empty rpc_servers entry")
 	ErrNotEnoughRPCServers             = errors.New("at least two rpc_servers entries are required")
+	ErrEmptyGRPCServerEntry            = errors.New("found empty grpc_servers entry")
+	ErrNotEnoughGRPCServers            = errors.New("at least two grpc_servers entries are required")
 	ErrInsufficientChunkRequestTimeout = errors.New("timeout for re-requesting a chunk (chunk_request_timeout) is less than 5 seconds")
 	ErrUnknownLogFormat                = errors.New("unknown log_format (must be 'plain' or 'json')")
 	ErrSubscriptionBufferSizeInvalid   = fmt.Errorf("experimental_subscription_buffer_size must be >= %d", minSubscriptionBufferSize)
@@ -27,6 +29,14 @@ func (e ErrInSection) Unwrap() error {
 	return e.Err
 }
 
+type ErrUnknownStateProvider struct {
+	Provider string
+}
+
+func (e ErrUnknownStateProvider) Error() string {
+	return fmt.Sprintf("unknown state_provider %q (must be %q or %q)", e.Provider, StateProviderRPC, StateProviderGRPC)
+}
+
 type ErrDeprecatedBlocksyncVersion struct {
 	Version string
 	Allowed []string
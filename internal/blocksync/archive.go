@@ -0,0 +1,175 @@
+package blocksync
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	bcproto "github.com/cometbft/cometbft/api/cometbft/blocksync/v2"
+	"github.com/cometbft/cometbft/v2/libs/log"
+	"github.com/cometbft/cometbft/v2/libs/protoio"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// A block archive is a stream of varint length-prefixed BlockResponse
+// messages, one per height and in increasing height order. Unlike on the
+// wire, the commit carried by each message is the commit for the block in the
+// same message, so that every block in the archive, including the last one,
+// can be verified on its own. If vote extensions were disabled at a height,
+// the plain commit is wrapped as an extended commit without extensions.
+
+// ArchiveWriter writes blocks to a block archive.
+type ArchiveWriter struct {
+	w protoio.Writer
+}
+
+// NewArchiveWriter returns an ArchiveWriter writing to w.
+func NewArchiveWriter(w io.Writer) *ArchiveWriter {
+	return &ArchiveWriter{w: protoio.NewDelimitedWriter(w)}
+}
+
+// WriteBlock appends block and its commit to the archive. extCommit must be
+// non-nil iff vote extensions were enabled at the block's height; otherwise
+// commit is used.
+func (aw *ArchiveWriter) WriteBlock(block *types.Block, commit *types.Commit, extCommit *types.ExtendedCommit) error {
+	if extCommit == nil {
+		if commit == nil {
+			return fmt.Errorf("no commit for block at height %d", block.Height)
+		}
+		extCommit = commit.WrappedExtendedCommit()
+	}
+	if extCommit.Height != block.Height {
+		return fmt.Errorf("commit height %d does not match block height %d", extCommit.Height, block.Height)
+	}
+
+	pb, err := block.ToProto()
+	if err != nil {
+		return err
+	}
+	_, err = aw.w.WriteMsg(&bcproto.BlockResponse{
+		Block:     pb,
+		ExtCommit: extCommit.ToProto(),
+	})
+	return err
+}
+
+// ArchiveReader reads blocks from a block archive.
+type ArchiveReader struct {
+	r protoio.Reader
+}
+
+// NewArchiveReader returns an ArchiveReader reading from r.
+func NewArchiveReader(r io.Reader) *ArchiveReader {
+	return &ArchiveReader{r: protoio.NewDelimitedReader(bufio.NewReader(r), MaxMsgSize)}
+}
+
+// ReadBlock returns the next block in the archive along with its commit, as
+// an extended commit. It returns io.EOF once the archive is exhausted.
+func (ar *ArchiveReader) ReadBlock() (*types.Block, *types.ExtendedCommit, error) {
+	msg := new(bcproto.BlockResponse)
+	if _, err := ar.r.ReadMsg(msg); err != nil {
+		return nil, nil, err
+	}
+
+	block, err := types.BlockFromProto(msg.Block)
+	if err != nil {
+		return nil, nil, err
+	}
+	if msg.ExtCommit == nil {
+		return nil, nil, fmt.Errorf("missing commit for block at height %d", block.Height)
+	}
+	extCommit, err := types.ExtendedCommitFromProto(msg.ExtCommit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if extCommit.Height != block.Height {
+		return nil, nil, fmt.Errorf("commit height %d does not match block height %d", extCommit.Height, block.Height)
+	}
+	return block, extCommit, nil
+}
+
+// ImportBlocks reads blocks from ar, verifies them against state the same way
+// block sync does, saves them to blockStore and executes them against the
+// application. Blocks at or below state.LastBlockHeight are skipped, so an
+// interrupted import can be resumed by running it again over the same
+// archive. It returns the state after the last applied block.
+func ImportBlocks(
+	ctx context.Context,
+	ar *ArchiveReader,
+	state sm.State,
+	blockExec *sm.BlockExecutor,
+	blockStore sm.BlockStore,
+	logger log.Logger,
+) (sm.State, error) {
+	var (
+		imported    int64
+		lastHundred = time.Now()
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return state, ctx.Err()
+		default:
+		}
+
+		block, extCommit, err := ar.ReadBlock()
+		if errors.Is(err, io.EOF) {
+			return state, nil
+		}
+		if err != nil {
+			return state, fmt.Errorf("reading archive after height %d: %w", state.LastBlockHeight, err)
+		}
+
+		nextHeight := state.LastBlockHeight + 1
+		if state.LastBlockHeight == 0 {
+			nextHeight = state.InitialHeight
+		}
+		if block.Height < nextHeight {
+			continue
+		}
+		if block.Height > nextHeight {
+			return state, ErrInvalidHeight{
+				Height: block.Height,
+				Reason: fmt.Sprintf("archive is missing blocks, expected height %d", nextHeight),
+			}
+		}
+
+		parts, err := block.MakePartSet(types.BlockPartSizeBytes)
+		if err != nil {
+			return state, err
+		}
+		blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+
+		commit := extCommit.ToCommit()
+		extensionsEnabled := state.ConsensusParams.Feature.VoteExtensionsEnabled(block.Height)
+		if !extensionsEnabled {
+			extCommit = nil
+		}
+		err = VerifyBlock(blockExec, state, state.ChainID, block, blockID, commit, extCommit, nil)
+		if err != nil {
+			return state, fmt.Errorf("invalid block at height %d: %w", block.Height, err)
+		}
+
+		if extensionsEnabled {
+			blockStore.SaveBlockWithExtendedCommit(block, parts, extCommit)
+		} else {
+			blockStore.SaveBlock(block, parts, commit)
+		}
+
+		state, err = blockExec.ApplyVerifiedBlock(state, blockID, block, block.Height)
+		if err != nil {
+			return state, fmt.Errorf("applying block at height %d: %w", block.Height, err)
+		}
+
+		imported++
+		if imported%100 == 0 {
+			logger.Info("Block Import Rate", "height", block.Height,
+				"blocks/s", 100/time.Since(lastHundred).Seconds())
+			lastHundred = time.Now()
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package blocksync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+	abci "github.com/cometbft/cometbft/v2/abci/types"
+	"github.com/cometbft/cometbft/v2/libs/log"
+	mpmocks "github.com/cometbft/cometbft/v2/mempool/mocks"
+	"github.com/cometbft/cometbft/v2/proxy"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/store"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// exportArchive writes the blocks from 1 to height of bs to a block archive.
+func exportArchive(t *testing.T, bs sm.BlockStore, height int64) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	aw := NewArchiveWriter(&buf)
+	for h := int64(1); h <= height; h++ {
+		block, _ := bs.LoadBlock(h)
+		require.NotNil(t, block)
+		extCommit := bs.LoadBlockExtendedCommit(h)
+		require.NotNil(t, extCommit)
+		require.NoError(t, aw.WriteBlock(block, nil, extCommit))
+	}
+	return buf.Bytes()
+}
+
+type importTarget struct {
+	state      sm.State
+	blockExec  *sm.BlockExecutor
+	blockStore *store.BlockStore
+}
+
+func newImportTarget(t *testing.T, genDoc *types.GenesisDoc) importTarget {
+	t.Helper()
+
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(abci.NewBaseApplication()), proxy.NopMetrics())
+	require.NoError(t, proxyApp.Start())
+	t.Cleanup(func() { _ = proxyApp.Stop() })
+
+	stateStore := sm.NewStore(dbm.NewMemDB(), sm.StoreOptions{DiscardABCIResponses: false})
+	state, err := stateStore.LoadFromDBOrGenesisDoc(genDoc)
+	require.NoError(t, err)
+	require.NoError(t, stateStore.Save(state))
+
+	mp := &mpmocks.Mempool{}
+	mp.On("Lock").Return()
+	mp.On("Unlock").Return()
+	mp.On("PreUpdate").Return()
+	mp.On("FlushAppConn", mock.Anything).Return(nil)
+	mp.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	blockStore := store.NewBlockStore(dbm.NewMemDB())
+	blockExec := sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mp, sm.EmptyEvidencePool{}, blockStore)
+	return importTarget{state: state, blockExec: blockExec, blockStore: blockStore}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	genDoc, privVals := randGenesisDoc()
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, 5)
+	defer func() { _ = source.app.Stop() }()
+
+	ar := NewArchiveReader(bytes.NewReader(exportArchive(t, source.reactor.store, 5)))
+	for h := int64(1); h <= 5; h++ {
+		block, extCommit, err := ar.ReadBlock()
+		require.NoError(t, err)
+		expected, _ := source.reactor.store.LoadBlock(h)
+		assert.Equal(t, expected.Hash(), block.Hash())
+		assert.Equal(t, h, extCommit.Height)
+		assert.Equal(t, block.Hash(), extCommit.BlockID.Hash)
+	}
+	_, _, err := ar.ReadBlock()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestImportBlocks(t *testing.T) {
+	genDoc, privVals := randGenesisDoc()
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, 10)
+	defer func() { _ = source.app.Stop() }()
+	archive := exportArchive(t, source.reactor.store, 10)
+
+	target := newImportTarget(t, genDoc)
+
+	// Import a prefix of the archive first, to simulate an interrupted import.
+	prefix := exportArchive(t, source.reactor.store, 4)
+	state, err := ImportBlocks(context.Background(), NewArchiveReader(bytes.NewReader(prefix)),
+		target.state, target.blockExec, target.blockStore, log.TestingLogger())
+	require.NoError(t, err)
+	require.EqualValues(t, 4, state.LastBlockHeight)
+
+	// Resuming with the whole archive skips the blocks already imported.
+	state, err = ImportBlocks(context.Background(), NewArchiveReader(bytes.NewReader(archive)),
+		state, target.blockExec, target.blockStore, log.TestingLogger())
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, state.LastBlockHeight)
+	assert.EqualValues(t, 10, target.blockStore.Height())
+
+	expected, _ := source.reactor.store.LoadBlock(10)
+	imported, _ := target.blockStore.LoadBlock(10)
+	assert.Equal(t, expected.Hash(), imported.Hash())
+}
+
+func TestImportBlocksRejectsInvalidArchive(t *testing.T) {
+	genDoc, privVals := randGenesisDoc()
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, 3)
+	defer func() { _ = source.app.Stop() }()
+
+	t.Run("gap", func(t *testing.T) {
+		var buf bytes.Buffer
+		aw := NewArchiveWriter(&buf)
+		block, _ := source.reactor.store.LoadBlock(2)
+		require.NoError(t, aw.WriteBlock(block, nil, source.reactor.store.LoadBlockExtendedCommit(2)))
+
+		target := newImportTarget(t, genDoc)
+		_, err := ImportBlocks(context.Background(), NewArchiveReader(&buf),
+			target.state, target.blockExec, target.blockStore, log.TestingLogger())
+		var errHeight ErrInvalidHeight
+		require.True(t, errors.As(err, &errHeight))
+		assert.EqualValues(t, 2, errHeight.Height)
+	})
+
+	t.Run("wrong commit", func(t *testing.T) {
+		var buf bytes.Buffer
+		aw := NewArchiveWriter(&buf)
+		block, _ := source.reactor.store.LoadBlock(1)
+		extCommit := source.reactor.store.LoadBlockExtendedCommit(1)
+		extCommit.ExtendedSignatures[0].Signature = make([]byte, len(extCommit.ExtendedSignatures[0].Signature))
+		require.NoError(t, aw.WriteBlock(block, nil, extCommit))
+
+		target := newImportTarget(t, genDoc)
+		state, err := ImportBlocks(context.Background(), NewArchiveReader(&buf),
+			target.state, target.blockExec, target.blockStore, log.TestingLogger())
+		require.Error(t, err)
+		assert.EqualValues(t, 0, state.LastBlockHeight)
+		assert.EqualValues(t, 0, target.blockStore.Height())
+	})
+}
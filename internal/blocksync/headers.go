@@ -0,0 +1,262 @@
+package blocksync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cometbft/cometbft/v2/libs/log"
+	cmtsync "github.com/cometbft/cometbft/v2/libs/sync"
+	"github.com/cometbft/cometbft/v2/light"
+	lightprovider "github.com/cometbft/cometbft/v2/light/provider"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/types"
+	cmttime "github.com/cometbft/cometbft/v2/types/time"
+)
+
+const (
+	// how much a header's time may drift into the future.
+	headerSyncMaxClockDrift = 10 * time.Second
+	// how long to wait before asking the providers for a newer height, once
+	// the trusted header chain has caught up with them.
+	headerSyncPollInterval = 5 * time.Second
+)
+
+// headerSync advances a chain of trusted headers ahead of block sync, using
+// the light client's skipping verification against a set of light block
+// providers. Each verified header becomes a pivot: downloaded blocks are
+// linked to it backwards through their LastBlockID hashes, so that they are
+// known to be committed without having to verify their commits.
+type headerSync struct {
+	chainID       string
+	providers     []lightprovider.Provider
+	trustPeriod   time.Duration
+	pivotInterval int64
+	logger        log.Logger
+
+	mtx cmtsync.Mutex
+	// header hashes of blocks known to be committed, by height.
+	trusted map[int64][]byte
+}
+
+func newHeaderSync(
+	chainID string,
+	providers []lightprovider.Provider,
+	trustPeriod time.Duration,
+	pivotInterval int64,
+) *headerSync {
+	return &headerSync{
+		chainID:       chainID,
+		providers:     providers,
+		trustPeriod:   trustPeriod,
+		pivotInterval: pivotInterval,
+		logger:        log.NewNopLogger(),
+		trusted:       make(map[int64][]byte),
+	}
+}
+
+// run verifies headers every pivotInterval heights, starting from state,
+// until ctx is canceled or an error occurs. Block sync keeps working without
+// the trusted headers in the latter case.
+func (hs *headerSync) run(ctx context.Context, state sm.State, store sm.BlockStore) error {
+	trusted, err := hs.anchor(ctx, state, store)
+	if err != nil {
+		return fmt.Errorf("establishing trusted header: %w", err)
+	}
+
+	step := hs.pivotInterval
+	for {
+		latest, err := hs.lightBlock(ctx, 0)
+		if err != nil {
+			return err
+		}
+		if latest.Height <= trusted.Height {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(headerSyncPollInterval):
+				continue
+			}
+		}
+
+		target := trusted.Height + step
+		if target > latest.Height {
+			target = latest.Height
+		}
+		untrusted := latest
+		if target != latest.Height {
+			if untrusted, err = hs.lightBlock(ctx, target); err != nil {
+				return err
+			}
+		}
+
+		err = light.Verify(trusted.SignedHeader, trusted.ValidatorSet, untrusted.SignedHeader, untrusted.ValidatorSet,
+			hs.trustPeriod, cmttime.Now(), headerSyncMaxClockDrift, light.DefaultTrustLevel)
+		var errNewValSet light.ErrNewValSetCantBeTrusted
+		switch {
+		case errors.As(err, &errNewValSet):
+			// Too much of the validator set changed in between: bisect.
+			step = (target - trusted.Height) / 2
+			continue
+		case err != nil:
+			return fmt.Errorf("verifying header at height %d: %w", untrusted.Height, err)
+		}
+
+		hs.addTrusted(untrusted.Height, untrusted.Header.Hash())
+		hs.logger.Debug("Verified header", "height", untrusted.Height, "hash", untrusted.Header.Hash())
+		trusted, step = untrusted, hs.pivotInterval
+	}
+}
+
+// anchor returns the light block to start verifying from, at the latest
+// height of state. At genesis, the first light block is verified against the
+// genesis validators instead. After state sync, it is fetched from the
+// providers and verified against the state.
+func (hs *headerSync) anchor(ctx context.Context, state sm.State, store sm.BlockStore) (*types.LightBlock, error) {
+	if state.LastBlockHeight == 0 {
+		lb, err := hs.lightBlock(ctx, state.InitialHeight)
+		if err != nil {
+			return nil, err
+		}
+		if err := lb.ValidateBasic(hs.chainID); err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(lb.ValidatorsHash, state.Validators.Hash()) {
+			return nil, fmt.Errorf("validators hash %X of the initial header does not match genesis validators %X",
+				lb.ValidatorsHash, state.Validators.Hash())
+		}
+		if err := state.Validators.VerifyCommitLight(hs.chainID, lb.Commit.BlockID, lb.Height, lb.Commit); err != nil {
+			return nil, err
+		}
+		hs.addTrusted(lb.Height, lb.Header.Hash())
+		return lb, nil
+	}
+
+	meta := store.LoadBlockMeta(state.LastBlockHeight)
+	if meta == nil {
+		// After state sync, the block store has no block at the height of the
+		// state: the light block is fetched instead and checked against it.
+		return hs.anchorStateSynced(ctx, state)
+	}
+	commit := store.LoadBlockCommit(state.LastBlockHeight)
+	if commit == nil {
+		commit = store.LoadSeenCommit(state.LastBlockHeight)
+	}
+	if commit == nil {
+		return nil, fmt.Errorf("no commit for height %d in the block store", state.LastBlockHeight)
+	}
+	return &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: &meta.Header, Commit: commit},
+		ValidatorSet: state.LastValidators,
+	}, nil
+}
+
+// anchorStateSynced returns the light block at the latest height of state,
+// fetched from the providers and authenticated by the last block ID and the
+// last validators of state.
+func (hs *headerSync) anchorStateSynced(ctx context.Context, state sm.State) (*types.LightBlock, error) {
+	if state.LastBlockID.IsNil() || state.LastValidators == nil {
+		return nil, fmt.Errorf("no block at height %d in the block store, and no last block ID in the state",
+			state.LastBlockHeight)
+	}
+	lb, err := hs.lightBlock(ctx, state.LastBlockHeight)
+	if err != nil {
+		return nil, err
+	}
+	if err := lb.ValidateBasic(hs.chainID); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(lb.Header.Hash(), state.LastBlockID.Hash) {
+		return nil, fmt.Errorf("header hash %X at height %d does not match the last block ID %X of the state",
+			lb.Header.Hash(), lb.Height, state.LastBlockID.Hash)
+	}
+	if !bytes.Equal(lb.ValidatorsHash, state.LastValidators.Hash()) {
+		return nil, fmt.Errorf("validators hash %X at height %d does not match the last validators %X of the state",
+			lb.ValidatorsHash, lb.Height, state.LastValidators.Hash())
+	}
+	if err := state.LastValidators.VerifyCommitLight(hs.chainID, lb.Commit.BlockID, lb.Height, lb.Commit); err != nil {
+		return nil, err
+	}
+	hs.addTrusted(lb.Height, lb.Header.Hash())
+	return lb, nil
+}
+
+// lightBlock fetches the light block at height (0 for the latest one) from the
+// first provider able to serve it.
+func (hs *headerSync) lightBlock(ctx context.Context, height int64) (*types.LightBlock, error) {
+	var err error
+	for _, p := range hs.providers {
+		var lb *types.LightBlock
+		if lb, err = p.LightBlock(ctx, height); err == nil {
+			return lb, nil
+		}
+		hs.logger.Debug("Failed to fetch light block", "provider", p, "height", height, "err", err)
+	}
+	return nil, fmt.Errorf("fetching light block at height %d: %w", height, err)
+}
+
+func (hs *headerSync) addTrusted(height int64, hash []byte) {
+	hs.mtx.Lock()
+	defer hs.mtx.Unlock()
+	hs.trusted[height] = hash
+}
+
+// link marks as trusted the blocks that are linked, through the LastBlockID
+// of their successor, to a trusted header. blocks must be consecutive.
+func (hs *headerSync) link(blocks []*types.Block) {
+	hs.mtx.Lock()
+	defer hs.mtx.Unlock()
+
+	var expected []byte // the hash the current block must have to be trusted
+	for i := len(blocks) - 1; i >= 0; i-- {
+		block := blocks[i]
+		hash, ok := hs.trusted[block.Height]
+		switch {
+		case ok && bytes.Equal(hash, block.Hash()):
+		case !ok && expected != nil && bytes.Equal(expected, block.Hash()):
+			hs.trusted[block.Height] = expected
+		default:
+			expected = nil
+			continue
+		}
+		expected = block.LastBlockID.Hash
+	}
+}
+
+// trustedHash returns the hash of the trusted header at height, if any.
+func (hs *headerSync) trustedHash(height int64) ([]byte, bool) {
+	hs.mtx.Lock()
+	defer hs.mtx.Unlock()
+	hash, ok := hs.trusted[height]
+	return hash, ok
+}
+
+// forget drops the trusted header at height, once the block at that height
+// has been applied.
+func (hs *headerSync) forget(height int64) {
+	hs.mtx.Lock()
+	defer hs.mtx.Unlock()
+	delete(hs.trusted, height)
+}
+
+// committed reports whether first, and second.LastCommit as its commit, are
+// authenticated by the trusted header chain, in which case the commit does
+// not need to be verified. It returns an error if first contradicts a trusted
+// header.
+func (hs *headerSync) committed(first, second *types.Block) (bool, error) {
+	hash, ok := hs.trustedHash(first.Height)
+	if !ok {
+		return false, nil
+	}
+	if !bytes.Equal(hash, first.Hash()) {
+		return false, fmt.Errorf("block hash %X does not match trusted header hash %X", first.Hash(), hash)
+	}
+	// The commit is part of the next block, which needs to be trusted too.
+	nextHash, ok := hs.trustedHash(second.Height)
+	if !ok || !bytes.Equal(nextHash, second.Hash()) {
+		return false, nil
+	}
+	return bytes.Equal(second.LastCommit.Hash(), second.LastCommitHash), nil
+}
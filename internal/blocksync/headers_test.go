@@ -0,0 +1,171 @@
+package blocksync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/v2/libs/log"
+	"github.com/cometbft/cometbft/v2/light"
+	lightprovider "github.com/cometbft/cometbft/v2/light/provider"
+	mockp "github.com/cometbft/cometbft/v2/light/provider/mock"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/store"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// newHeaderProvider returns a light block provider serving the headers of the
+// blocks in source.
+func newHeaderProvider(t *testing.T, source ReactorPair, height int64) lightprovider.Provider {
+	t.Helper()
+
+	var (
+		bs      = source.reactor.store
+		vals    = source.reactor.initialState.Validators
+		headers = make(map[int64]*types.SignedHeader, height)
+		valSets = make(map[int64]*types.ValidatorSet, height)
+	)
+	for h := int64(1); h <= height; h++ {
+		meta := bs.LoadBlockMeta(h)
+		require.NotNil(t, meta)
+		commit := bs.LoadBlockCommit(h)
+		if commit == nil {
+			commit = bs.LoadSeenCommit(h)
+		}
+		require.NotNil(t, commit)
+		headers[h] = &types.SignedHeader{Header: &meta.Header, Commit: commit}
+		valSets[h] = vals
+	}
+	return mockp.New(source.reactor.initialState.ChainID, headers, valSets)
+}
+
+func TestHeaderSyncVerifiesPivots(t *testing.T) {
+	genDoc, privVals := randGenesisDoc()
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, 30)
+	defer func() { _ = source.app.Stop() }()
+
+	state, err := sm.MakeGenesisState(genDoc)
+	require.NoError(t, err)
+
+	hs := newHeaderSync(genDoc.ChainID, []lightprovider.Provider{newHeaderProvider(t, source, 30)}, time.Hour, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- hs.run(ctx, state, source.reactor.store) }()
+
+	require.Eventually(t, func() bool {
+		_, ok := hs.trustedHash(30)
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	for _, h := range []int64{1, 11, 21, 30} {
+		hash, ok := hs.trustedHash(h)
+		require.True(t, ok, "height %d", h)
+		block, _ := source.reactor.store.LoadBlock(h)
+		assert.Equal(t, []byte(block.Hash()), hash)
+	}
+	_, ok := hs.trustedHash(2)
+	assert.False(t, ok)
+}
+
+func TestHeaderSyncExpiredAnchor(t *testing.T) {
+	genDoc, privVals := randGenesisDoc()
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, 5)
+	defer func() { _ = source.app.Stop() }()
+
+	hs := newHeaderSync(genDoc.ChainID, []lightprovider.Provider{newHeaderProvider(t, source, 5)}, time.Nanosecond, 10)
+
+	// Start from the state at height 2, whose header is long expired.
+	state := source.reactor.initialState.Copy()
+	state.LastBlockHeight = 2
+	err := hs.run(context.Background(), state, source.reactor.store)
+	var errExpired light.ErrOldHeaderExpired
+	assert.ErrorAs(t, err, &errExpired)
+}
+
+func TestHeaderSyncStateSyncedAnchor(t *testing.T) {
+	genDoc, privVals := randGenesisDoc()
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, 20)
+	defer func() { _ = source.app.Stop() }()
+	provider := newHeaderProvider(t, source, 20)
+
+	// A state synced at height 10 has no block in the block store.
+	meta := source.reactor.store.LoadBlockMeta(10)
+	require.NotNil(t, meta)
+	state := source.reactor.initialState.Copy()
+	state.LastBlockHeight = 10
+	state.LastBlockID = meta.BlockID
+	state.LastValidators = state.Validators
+	emptyStore := store.NewBlockStore(dbm.NewMemDB())
+
+	hs := newHeaderSync(genDoc.ChainID, []lightprovider.Provider{provider}, time.Hour, 5)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- hs.run(ctx, state, emptyStore) }()
+
+	require.Eventually(t, func() bool {
+		_, ok := hs.trustedHash(20)
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	hash, ok := hs.trustedHash(10)
+	require.True(t, ok)
+	assert.Equal(t, []byte(meta.BlockID.Hash), hash)
+
+	// The fetched header must match the last block ID of the state.
+	state.LastBlockID = source.reactor.store.LoadBlockMeta(9).BlockID
+	hs = newHeaderSync(genDoc.ChainID, []lightprovider.Provider{provider}, time.Hour, 5)
+	err := hs.run(context.Background(), state, emptyStore)
+	assert.ErrorContains(t, err, "does not match the last block ID")
+}
+
+func TestHeaderSyncLink(t *testing.T) {
+	genDoc, privVals := randGenesisDoc()
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, 12)
+	defer func() { _ = source.app.Stop() }()
+
+	blocks := make([]*types.Block, 0, 8)
+	for h := int64(5); h <= 12; h++ {
+		block, _ := source.reactor.store.LoadBlock(h)
+		blocks = append(blocks, block)
+	}
+
+	hs := newHeaderSync(genDoc.ChainID, nil, time.Hour, 10)
+	hs.addTrusted(11, blocks[6].Hash())
+	hs.link(blocks)
+
+	for _, block := range blocks[:7] {
+		hash, ok := hs.trustedHash(block.Height)
+		require.True(t, ok, "height %d", block.Height)
+		assert.Equal(t, []byte(block.Hash()), hash)
+	}
+	_, ok := hs.trustedHash(12)
+	assert.False(t, ok)
+
+	committed, err := hs.committed(blocks[0], blocks[1])
+	require.NoError(t, err)
+	assert.True(t, committed)
+
+	// Block 12 is not trusted, so neither is the commit for block 11.
+	committed, err = hs.committed(blocks[6], blocks[7])
+	require.NoError(t, err)
+	assert.False(t, committed)
+
+	// A block contradicting a trusted header is rejected.
+	hs.addTrusted(12, blocks[0].Hash())
+	_, err = hs.committed(blocks[7], blocks[7])
+	require.Error(t, err)
+
+	hs.forget(12)
+	_, ok = hs.trustedHash(12)
+	assert.False(t, ok)
+}
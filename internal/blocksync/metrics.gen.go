@@ -44,6 +44,18 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "latest_block_height",
 			Help:      "The height of the latest block.",
 		}, labels).With(labelsAndValues...),
+		ServedRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "served_requests",
+			Help:      "Number of block requests from peers that were served.",
+		}, labels).With(labelsAndValues...),
+		ThrottledRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "throttled_requests",
+			Help:      "Number of block requests from peers that were turned down because of the serving limits or window.",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
@@ -54,5 +66,7 @@ func NopMetrics() *Metrics {
 		TotalTxs:          discard.NewGauge(),
 		BlockSizeBytes:    discard.NewGauge(),
 		LatestBlockHeight: discard.NewGauge(),
+		ServedRequests:    discard.NewCounter(),
+		ThrottledRequests: discard.NewCounter(),
 	}
 }
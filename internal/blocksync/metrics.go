@@ -25,6 +25,11 @@ type Metrics struct {
 	BlockSizeBytes metrics.Gauge
 	// The height of the latest block.
 	LatestBlockHeight metrics.Gauge
+	// Number of block requests from peers that were served.
+	ServedRequests metrics.Counter
+	// Number of block requests from peers that were turned down because
+	// of the serving limits or window.
+	ThrottledRequests metrics.Counter
 }
 
 func (m *Metrics) recordBlockMetrics(block *types.Block) {
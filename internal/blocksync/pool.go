@@ -229,6 +229,27 @@ func (pool *BlockPool) PeekTwoBlocks() (first, second *types.Block, firstExtComm
 	return first, second, firstExtCommit
 }
 
+// PeekBlocks returns up to n consecutive blocks starting at pool.height. It
+// stops at the first height for which no block has been received yet.
+func (pool *BlockPool) PeekBlocks(n int) []*types.Block {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	blocks := make([]*types.Block, 0, n)
+	for h := pool.height; h < pool.height+int64(n); h++ {
+		r := pool.requesters[h]
+		if r == nil {
+			break
+		}
+		block := r.getBlock()
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
 // PopRequest removes the requester at pool.height and increments pool.height.
 func (pool *BlockPool) PopRequest() {
 	pool.mtx.Lock()
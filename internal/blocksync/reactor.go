@@ -1,14 +1,20 @@
 package blocksync
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"runtime"
 	"sync"
 	"time"
 
 	bcproto "github.com/cometbft/cometbft/api/cometbft/blocksync/v2"
 	"github.com/cometbft/cometbft/v2/crypto"
+	"github.com/cometbft/cometbft/v2/internal/servelimit"
 	"github.com/cometbft/cometbft/v2/libs/log"
+	cmtsync "github.com/cometbft/cometbft/v2/libs/sync"
+	lightprovider "github.com/cometbft/cometbft/v2/light/provider"
 	"github.com/cometbft/cometbft/v2/p2p"
 	tcpconn "github.com/cometbft/cometbft/v2/p2p/transport/tcp/conn"
 	sm "github.com/cometbft/cometbft/v2/state"
@@ -71,12 +77,62 @@ type Reactor struct {
 
 	switchToConsensusMs int
 
+	// verifies the commits of upcoming blocks concurrently; nil if disabled.
+	verifier *commitVerifier
+	// advances a chain of trusted headers ahead of the blocks; nil if disabled.
+	headers *headerSync
+
+	// limits the block requests from peers served concurrently.
+	serveLimiter *servelimit.Limiter
+	// block requests admitted by serveLimiter, waiting to be served in the
+	// order they were received, by peer.
+	serveQueuesMtx cmtsync.Mutex
+	serveQueues    map[p2p.ID]*serveQueue
+	// number of latest heights served to peers; 0 if unrestricted.
+	serveWindow int64
+
 	metrics *Metrics
 }
 
+// ReactorOption sets an optional parameter on the Reactor.
+type ReactorOption func(*Reactor)
+
+// WithVerifyWindow sets the number of blocks ahead of the one being applied
+// whose commits are verified concurrently. 0 disables it.
+func WithVerifyWindow(window int) ReactorOption {
+	return func(bcR *Reactor) {
+		if window <= 0 {
+			bcR.verifier = nil
+			return
+		}
+		bcR.verifier = newCommitVerifier(bcR.initialState.ChainID, window, runtime.GOMAXPROCS(0))
+	}
+}
+
+// WithHeaderSync enables advancing a chain of trusted headers, fetched from
+// providers, ahead of the blocks using the light client's skipping
+// verification. Blocks linked by hash to that chain are applied without
+// verifying their commit.
+func WithHeaderSync(providers []lightprovider.Provider, trustPeriod time.Duration, pivotInterval int64) ReactorOption {
+	return func(bcR *Reactor) {
+		bcR.headers = newHeaderSync(bcR.initialState.ChainID, providers, trustPeriod, pivotInterval)
+	}
+}
+
+// WithServeLimits bounds the block requests from peers served at the same
+// time, overall and per peer (0 means unlimited), and restricts the blocks
+// served to the latest window heights of the store (0 means all).
+func WithServeLimits(maxConcurrent, maxConcurrentPerPeer int, window int64) ReactorOption {
+	return func(bcR *Reactor) {
+		bcR.serveLimiter = servelimit.New(maxConcurrent, maxConcurrentPerPeer)
+		bcR.serveWindow = window
+	}
+}
+
 // NewReactor returns new reactor instance.
 func NewReactor(state sm.State, blockExec *sm.BlockExecutor, store *store.BlockStore,
 	blockSync bool, localAddr crypto.Address, metrics *Metrics, offlineStateSyncHeight int64,
+	options ...ReactorOption,
 ) *Reactor {
 	storeHeight := store.Height()
 	if storeHeight == 0 {
@@ -116,6 +172,11 @@ func NewReactor(state sm.State, blockExec *sm.BlockExecutor, store *store.BlockS
 		requestsCh:   requestsCh,
 		errorsCh:     errorsCh,
 		metrics:      metrics,
+		serveLimiter: servelimit.New(0, 0),
+		serveQueues:  make(map[p2p.ID]*serveQueue),
+	}
+	for _, option := range options {
+		option(bcR)
 	}
 	bcR.BaseReactor = *p2p.NewBaseReactor("Reactor", bcR)
 	return bcR
@@ -125,6 +186,9 @@ func NewReactor(state sm.State, blockExec *sm.BlockExecutor, store *store.BlockS
 func (bcR *Reactor) SetLogger(l log.Logger) {
 	bcR.BaseService.Logger = l
 	bcR.pool.Logger = l
+	if bcR.headers != nil {
+		bcR.headers.logger = l.With("module", "headersync")
+	}
 }
 
 // OnStart implements service.Service.
@@ -192,7 +256,7 @@ func (bcR *Reactor) AddPeer(peer p2p.Peer) {
 	_ = peer.Send(p2p.Envelope{
 		ChannelID: BlocksyncChannel,
 		Message: &bcproto.StatusResponse{
-			Base:   bcR.store.Base(),
+			Base:   bcR.servedBase(),
 			Height: bcR.store.Height(),
 		},
 	})
@@ -206,13 +270,104 @@ func (bcR *Reactor) RemovePeer(peer p2p.Peer, _ any) {
 	bcR.pool.RemovePeer(peer.ID())
 }
 
-// respondToPeer loads a block and sends it to the requesting peer,
+// servedBase returns the lowest height served to peers.
+func (bcR *Reactor) servedBase() int64 {
+	base := bcR.store.Base()
+	if bcR.serveWindow > 0 {
+		if low := bcR.store.Height() - bcR.serveWindow + 1; low > base {
+			base = low
+		}
+	}
+	return base
+}
+
+// serveBlockRequest responds to a block request within the serving limits.
+// Requests over the limits, or outside the serving window, are answered with
+// a NoBlockResponse so that the peer asks someone else.
+func (bcR *Reactor) serveBlockRequest(msg *bcproto.BlockRequest, src p2p.Peer) {
+	if msg.Height < bcR.servedBase() {
+		bcR.metrics.ThrottledRequests.Add(1)
+		bcR.Logger.Debug("Peer asking for a block outside the serving window", "src", src, "height", msg.Height)
+		_ = src.TrySend(p2p.Envelope{
+			ChannelID: BlocksyncChannel,
+			Message:   &bcproto.NoBlockResponse{Height: msg.Height},
+		})
+		return
+	}
+
+	if bcR.serveLimiter.Unlimited() {
+		if bcR.respondToPeer(msg, src, src.TrySend) {
+			bcR.metrics.ServedRequests.Add(1)
+		}
+		return
+	}
+
+	if !bcR.serveLimiter.Acquire(src.ID()) {
+		bcR.metrics.ThrottledRequests.Add(1)
+		bcR.Logger.Debug("Too many block requests being served, turning down request", "src", src, "height", msg.Height)
+		_ = src.TrySend(p2p.Envelope{
+			ChannelID: BlocksyncChannel,
+			Message:   &bcproto.NoBlockResponse{Height: msg.Height},
+		})
+		return
+	}
+
+	bcR.serveQueuesMtx.Lock()
+	q, ok := bcR.serveQueues[src.ID()]
+	if !ok {
+		q = &serveQueue{}
+		bcR.serveQueues[src.ID()] = q
+	}
+	q.requests = append(q.requests, msg)
+	start := !q.serving
+	q.serving = true
+	bcR.serveQueuesMtx.Unlock()
+
+	if start {
+		go bcR.servePeer(src, q)
+	}
+}
+
+// serveQueue holds the block requests of a peer admitted by the serving
+// limits, in the order they were received.
+type serveQueue struct {
+	requests []*bcproto.BlockRequest
+	serving  bool // true while servePeer drains the queue
+}
+
+// servePeer responds to the requests queued for src one at a time, so that
+// the peer receives the responses in the order it sent the requests. The
+// slot of each request is held until its response is handed to the
+// connection, which blocks while the send queue is full, so that the limits
+// bound the responses in flight. It returns once the queue is empty.
+func (bcR *Reactor) servePeer(src p2p.Peer, q *serveQueue) {
+	for {
+		bcR.serveQueuesMtx.Lock()
+		if len(q.requests) == 0 {
+			q.serving = false
+			delete(bcR.serveQueues, src.ID())
+			bcR.serveQueuesMtx.Unlock()
+			return
+		}
+		msg := q.requests[0]
+		q.requests[0] = nil
+		q.requests = q.requests[1:]
+		bcR.serveQueuesMtx.Unlock()
+
+		if bcR.respondToPeer(msg, src, src.Send) {
+			bcR.metrics.ServedRequests.Add(1)
+		}
+		bcR.serveLimiter.Release(src.ID())
+	}
+}
+
+// respondToPeer loads a block and sends it to the requesting peer with send,
 // if we have it. Otherwise, we'll respond saying we don't have it.
-func (bcR *Reactor) respondToPeer(msg *bcproto.BlockRequest, src p2p.Peer) (queued bool) {
+func (bcR *Reactor) respondToPeer(msg *bcproto.BlockRequest, src p2p.Peer, send func(p2p.Envelope) error) (queued bool) {
 	block, _ := bcR.store.LoadBlock(msg.Height)
 	if block == nil {
 		bcR.Logger.Info("Peer asking for a block we don't have", "src", src, "height", msg.Height)
-		err := src.TrySend(p2p.Envelope{
+		err := send(p2p.Envelope{
 			ChannelID: BlocksyncChannel,
 			Message:   &bcproto.NoBlockResponse{Height: msg.Height},
 		})
@@ -239,7 +394,7 @@ func (bcR *Reactor) respondToPeer(msg *bcproto.BlockRequest, src p2p.Peer) (queu
 		return false
 	}
 
-	err = src.TrySend(p2p.Envelope{
+	err = send(p2p.Envelope{
 		ChannelID: BlocksyncChannel,
 		Message: &bcproto.BlockResponse{
 			Block:     bl,
@@ -286,7 +441,7 @@ func (bcR *Reactor) Receive(e p2p.Envelope) {
 
 	switch msg := e.Message.(type) {
 	case *bcproto.BlockRequest:
-		bcR.respondToPeer(msg, e.Src)
+		bcR.serveBlockRequest(msg, e.Src)
 	case *bcproto.BlockResponse:
 		go bcR.handlePeerResponse(msg, e.Src)
 	case *bcproto.StatusRequest:
@@ -295,7 +450,7 @@ func (bcR *Reactor) Receive(e p2p.Envelope) {
 			ChannelID: BlocksyncChannel,
 			Message: &bcproto.StatusResponse{
 				Height: bcR.store.Height(),
-				Base:   bcR.store.Base(),
+				Base:   bcR.servedBase(),
 			},
 		})
 	case *bcproto.StatusResponse:
@@ -325,6 +480,17 @@ func (bcR *Reactor) poolRoutine(stateSynced bool) {
 
 	go bcR.handleBlockRequestsRoutine()
 
+	if bcR.headers != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			err := bcR.headers.run(ctx, bcR.initialState, bcR.store)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				bcR.Logger.Info("Header sync stopped, verifying the commit of every block", "err", err)
+			}
+		}()
+	}
+
 	if bcR.switchToConsensusMs == 0 {
 		bcR.switchToConsensusMs = switchToConsensusIntervalSeconds * 1000
 	}
@@ -396,15 +562,31 @@ FOR_LOOP:
 			// Try again quickly next loop.
 			didProcessCh <- struct{}{}
 
-			firstParts, err := first.MakePartSet(types.BlockPartSizeBytes)
-			if err != nil {
-				bcR.Logger.Error("failed to make ",
-					"height", first.Height,
-					"err", err.Error())
-				break FOR_LOOP
+			var (
+				firstParts *types.PartSet
+				sigCache   types.SignatureCache
+				err        error
+			)
+			if bcR.verifier != nil {
+				// Start verifying the commits of the blocks that follow
+				// while this one is being applied.
+				bcR.verifier.schedule(state, bcR.pool.PeekBlocks(bcR.verifier.window+1))
+				firstParts, sigCache = bcR.verifier.take(first, second.LastCommit)
+			}
+			if bcR.headers != nil {
+				bcR.headers.link(bcR.pool.PeekBlocks(int(bcR.headers.pivotInterval) + 1))
+			}
+			if firstParts == nil {
+				firstParts, err = first.MakePartSet(types.BlockPartSizeBytes)
+				if err != nil {
+					bcR.Logger.Error("failed to make ",
+						"height", first.Height,
+						"err", err.Error())
+					break FOR_LOOP
+				}
 			}
 
-			if state, err = bcR.processBlock(first, second, firstParts, state, extCommit); err != nil {
+			if state, err = bcR.processBlock(first, second, firstParts, state, extCommit, sigCache); err != nil {
 				bcR.Logger.Error("Invalid block", "height", first.Height, "err", err)
 				continue FOR_LOOP
 			}
@@ -532,7 +714,16 @@ func (bcR *Reactor) isCaughtUp(state sm.State, blocksSynced uint64, stateSynced
 	return false
 }
 
-func (bcR *Reactor) processBlock(first, second *types.Block, firstParts *types.PartSet, state sm.State, extCommit *types.ExtendedCommit) (sm.State, error) {
+// processBlock verifies first against the LastCommit of second and, if valid,
+// saves and applies it. sigCache may hold signatures already verified
+// concurrently by the commitVerifier; it can be nil.
+func (bcR *Reactor) processBlock(
+	first, second *types.Block,
+	firstParts *types.PartSet,
+	state sm.State,
+	extCommit *types.ExtendedCommit,
+	sigCache types.SignatureCache,
+) (sm.State, error) {
 	var (
 		chainID            = bcR.initialState.ChainID
 		firstPartSetHeader = firstParts.Header()
@@ -544,27 +735,18 @@ func (bcR *Reactor) processBlock(first, second *types.Block, firstParts *types.P
 	// first.Hash() doesn't verify the tx contents, so MakePartSet() is
 	// currently necessary.
 	// TODO(sergio): Should we also validate against the extended commit?
-	err := state.Validators.VerifyCommitLight(
-		chainID, firstID, first.Height, second.LastCommit)
-
-	if err == nil {
-		// validate the block before we persist it
-		err = bcR.blockExec.ValidateBlock(state, first)
-	}
-
-	presentExtCommit := extCommit != nil
-	extensionsEnabled := state.ConsensusParams.Feature.VoteExtensionsEnabled(first.Height)
-	if presentExtCommit != extensionsEnabled {
-		err = fmt.Errorf("non-nil extended commit must be received iff vote extensions are enabled for its height "+
-			"(height %d, non-nil extended commit %t, extensions enabled %t)",
-			first.Height, presentExtCommit, extensionsEnabled,
-		)
+	commit := second.LastCommit
+	var err error
+	if bcR.headers != nil {
+		// Skip verifying the commit if the trusted header chain vouches for it.
+		var committed bool
+		if committed, err = bcR.headers.committed(first, second); committed {
+			commit = nil
+		}
 	}
-	if err == nil && extensionsEnabled {
-		// if vote extensions were required at this height, ensure they exist.
-		err = extCommit.EnsureExtensions(true)
+	if err == nil {
+		err = VerifyBlock(bcR.blockExec, state, chainID, first, firstID, commit, extCommit, sigCache)
 	}
-
 	if err != nil {
 		peerID := bcR.pool.RemovePeerAndRedoAllPeerRequests(first.Height)
 		peer := bcR.Switch.Peers().Get(peerID)
@@ -587,7 +769,7 @@ func (bcR *Reactor) processBlock(first, second *types.Block, firstParts *types.P
 	bcR.pool.PopRequest()
 
 	// TODO: batch saves so we dont persist to disk every block
-	if extensionsEnabled {
+	if state.ConsensusParams.Feature.VoteExtensionsEnabled(first.Height) {
 		bcR.store.SaveBlockWithExtendedCommit(first, firstParts, extCommit)
 	} else {
 		// We use LastCommit here instead of extCommit. extCommit is not
@@ -606,6 +788,57 @@ func (bcR *Reactor) processBlock(first, second *types.Block, firstParts *types.P
 	}
 
 	bcR.metrics.recordBlockMetrics(first)
+	if bcR.headers != nil {
+		bcR.headers.forget(first.Height)
+	}
 
 	return state, nil
 }
+
+// VerifyBlock checks that block, identified by blockID, was committed by
+// state.Validators according to commit, and that it is valid with respect to
+// state. extCommit must be non-nil iff vote extensions are enabled at the
+// block's height, in which case it must carry the extensions. sigCache may be
+// nil. commit may be nil if the block is already known to be committed, e.g.
+// because it is linked to a trusted header.
+func VerifyBlock(
+	blockExec *sm.BlockExecutor,
+	state sm.State,
+	chainID string,
+	block *types.Block,
+	blockID types.BlockID,
+	commit *types.Commit,
+	extCommit *types.ExtendedCommit,
+	sigCache types.SignatureCache,
+) error {
+	var err error
+	switch {
+	case commit == nil:
+	case sigCache != nil:
+		err = state.Validators.VerifyCommitLightWithCache(chainID, blockID, block.Height, commit, sigCache)
+	default:
+		err = state.Validators.VerifyCommitLight(chainID, blockID, block.Height, commit)
+	}
+	if err != nil {
+		return err
+	}
+
+	// validate the block before we persist it
+	if err := blockExec.ValidateBlock(state, block); err != nil {
+		return err
+	}
+
+	presentExtCommit := extCommit != nil
+	extensionsEnabled := state.ConsensusParams.Feature.VoteExtensionsEnabled(block.Height)
+	if presentExtCommit != extensionsEnabled {
+		return fmt.Errorf("non-nil extended commit must be received iff vote extensions are enabled for its height "+
+			"(height %d, non-nil extended commit %t, extensions enabled %t)",
+			block.Height, presentExtCommit, extensionsEnabled,
+		)
+	}
+	if extensionsEnabled {
+		// if vote extensions were required at this height, ensure they exist.
+		return extCommit.EnsureExtensions(true)
+	}
+	return nil
+}
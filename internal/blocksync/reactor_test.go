@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cosmos/gogoproto/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -20,6 +21,7 @@ import (
 	"github.com/cometbft/cometbft/v2/libs/log"
 	mpmocks "github.com/cometbft/cometbft/v2/mempool/mocks"
 	"github.com/cometbft/cometbft/v2/p2p"
+	p2pmocks "github.com/cometbft/cometbft/v2/p2p/mocks"
 	"github.com/cometbft/cometbft/v2/proxy"
 	sm "github.com/cometbft/cometbft/v2/state"
 	"github.com/cometbft/cometbft/v2/store"
@@ -180,7 +182,8 @@ func newReactor(
 	}
 
 	// As the tests only support one validator in the valSet, we pass a different address to bypass the `localNodeBlocksTheChain` check. Namely, the tested node is not an active validator.
-	bcReactor := NewByzantineReactor(incorrectBlock, NewReactor(state.Copy(), blockExec, blockStore, blockSync, []byte("anotherAddress"), NopMetrics(), 0))
+	bcReactor := NewByzantineReactor(incorrectBlock, NewReactor(state.Copy(), blockExec, blockStore, blockSync, []byte("anotherAddress"), NopMetrics(), 0,
+		WithVerifyWindow(cfg.DefaultBlockSyncConfig().VerifyWindow)))
 	bcReactor.SetLogger(logger.With("module", "blocksync"))
 
 	return ReactorPair{bcReactor, proxyApp}
@@ -594,3 +597,94 @@ func (bcR *ByzantineReactor) Receive(e p2p.Envelope) {
 		bcR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
 	}
 }
+
+func TestReactorServeLimits(t *testing.T) {
+	genDoc, privVals := randGenesisDoc()
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, 10)
+	defer func() { _ = source.app.Stop() }()
+	bcR := source.reactor.Reactor
+	WithServeLimits(0, 1, 3)(bcR)
+
+	responses := make(chan proto.Message, 10)
+	peer := &p2pmocks.Peer{}
+	peer.On("ID").Return(p2p.ID("peer"))
+	for _, method := range []string{"Send", "TrySend"} {
+		peer.On(method, mock.Anything).Run(func(args mock.Arguments) {
+			responses <- args[0].(p2p.Envelope).Message
+		}).Return(nil)
+	}
+	request := func(height int64) proto.Message {
+		bcR.Receive(p2p.Envelope{ChannelID: BlocksyncChannel, Src: peer,
+			Message: &bcproto.BlockRequest{Height: height}})
+		select {
+		case msg := <-responses:
+			return msg
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for the response at height %d", height)
+			return nil
+		}
+	}
+
+	// Only the latest 3 blocks are served, and advertised.
+	assert.Equal(t, &bcproto.NoBlockResponse{Height: 7}, request(7))
+	resp, ok := request(8).(*bcproto.BlockResponse)
+	require.True(t, ok)
+	assert.EqualValues(t, 8, resp.Block.Header.Height)
+	bcR.Receive(p2p.Envelope{ChannelID: BlocksyncChannel, Src: peer, Message: &bcproto.StatusRequest{}})
+	assert.Equal(t, &bcproto.StatusResponse{Base: 8, Height: 10}, <-responses)
+
+	// A peer with a request in flight is turned down.
+	require.Eventually(t, func() bool {
+		total, _ := bcR.serveLimiter.InFlight("peer")
+		return total == 0
+	}, time.Second, 10*time.Millisecond)
+	require.True(t, bcR.serveLimiter.Acquire("peer"))
+	assert.Equal(t, &bcproto.NoBlockResponse{Height: 9}, request(9))
+	bcR.serveLimiter.Release("peer")
+	_, ok = request(9).(*bcproto.BlockResponse)
+	assert.True(t, ok)
+}
+
+func TestReactorServeLimitsInOrder(t *testing.T) {
+	genDoc, privVals := randGenesisDoc()
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, 10)
+	defer func() { _ = source.app.Stop() }()
+	bcR := source.reactor.Reactor
+	WithServeLimits(0, 5, 0)(bcR)
+
+	// Sends block until the test lets them through.
+	unblock := make(chan struct{})
+	responses := make(chan proto.Message, 10)
+	peer := &p2pmocks.Peer{}
+	peer.On("ID").Return(p2p.ID("peer"))
+	peer.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		<-unblock
+		responses <- args[0].(p2p.Envelope).Message
+	}).Return(nil)
+
+	for height := int64(1); height <= 5; height++ {
+		bcR.Receive(p2p.Envelope{ChannelID: BlocksyncChannel, Src: peer,
+			Message: &bcproto.BlockRequest{Height: height}})
+	}
+
+	// The slots are held until the responses are sent.
+	_, inFlight := bcR.serveLimiter.InFlight("peer")
+	assert.Equal(t, 5, inFlight)
+
+	for height := int64(1); height <= 5; height++ {
+		unblock <- struct{}{}
+		select {
+		case msg := <-responses:
+			resp, ok := msg.(*bcproto.BlockResponse)
+			require.True(t, ok)
+			assert.Equal(t, height, resp.Block.Header.Height, "responses out of order")
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for the response at height %d", height)
+		}
+	}
+
+	require.Eventually(t, func() bool {
+		total, _ := bcR.serveLimiter.InFlight("peer")
+		return total == 0
+	}, time.Second, 10*time.Millisecond)
+}
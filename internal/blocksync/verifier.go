@@ -0,0 +1,136 @@
+package blocksync
+
+import (
+	"bytes"
+
+	cmtsync "github.com/cometbft/cometbft/v2/libs/sync"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// commitVerifier verifies the commits of the blocks following the one being
+// applied, so that signature checks for upcoming heights run concurrently
+// with block execution.
+//
+// Verification results are not trusted on their own: each job records the
+// signatures it verified in a per-height signature cache, which is then
+// handed to the ordered verification in processBlock. If a job used the
+// wrong validator set or the block got replaced in the meantime, the cache is
+// simply not hit and the signatures are verified again.
+type commitVerifier struct {
+	chainID string
+	window  int
+	sem     chan struct{} // bounds the number of concurrent verifications
+
+	mtx  cmtsync.Mutex
+	jobs map[int64]*verifyJob
+}
+
+type verifyJob struct {
+	block  *types.Block
+	commit *types.Commit // the LastCommit of the block at height+1
+
+	done     chan struct{}
+	parts    *types.PartSet
+	sigCache types.SignatureCache
+}
+
+func newCommitVerifier(chainID string, window, concurrency int) *commitVerifier {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &commitVerifier{
+		chainID: chainID,
+		window:  window,
+		sem:     make(chan struct{}, concurrency),
+		jobs:    make(map[int64]*verifyJob),
+	}
+}
+
+// schedule starts verifying every block in blocks, except the last one,
+// against the LastCommit of its successor. blocks must be consecutive and
+// start at state.LastBlockHeight+1. Only blocks whose validator set is known
+// from state (i.e. state.Validators or state.NextValidators, matched by the
+// header's ValidatorsHash) have their signatures checked; for the others only
+// the part set is precomputed.
+func (cv *commitVerifier) schedule(state sm.State, blocks []*types.Block) {
+	if len(blocks) < 2 {
+		return
+	}
+
+	var (
+		valsHash     = state.Validators.Hash()
+		nextValsHash []byte
+	)
+	if state.NextValidators != nil {
+		nextValsHash = state.NextValidators.Hash()
+	}
+
+	cv.mtx.Lock()
+	defer cv.mtx.Unlock()
+
+	for i := 0; i+1 < len(blocks); i++ {
+		block, commit := blocks[i], blocks[i+1].LastCommit
+		if job, ok := cv.jobs[block.Height]; ok && job.block == block && job.commit == commit {
+			continue
+		}
+
+		var vals *types.ValidatorSet
+		switch {
+		case bytes.Equal(block.ValidatorsHash, valsHash):
+			vals = state.Validators
+		case nextValsHash != nil && bytes.Equal(block.ValidatorsHash, nextValsHash):
+			vals = state.NextValidators
+		}
+
+		job := &verifyJob{
+			block:    block,
+			commit:   commit,
+			done:     make(chan struct{}),
+			sigCache: types.NewSignatureCache(),
+		}
+		cv.jobs[block.Height] = job
+		go cv.run(job, vals)
+	}
+}
+
+func (cv *commitVerifier) run(job *verifyJob, vals *types.ValidatorSet) {
+	defer close(job.done)
+
+	cv.sem <- struct{}{}
+	defer func() { <-cv.sem }()
+
+	parts, err := job.block.MakePartSet(types.BlockPartSizeBytes)
+	if err != nil {
+		return
+	}
+	job.parts = parts
+
+	if vals == nil || job.commit == nil {
+		return
+	}
+	blockID := types.BlockID{Hash: job.block.Hash(), PartSetHeader: parts.Header()}
+	// Errors are deliberately ignored: the ordered verification will fail
+	// as well and take care of punishing the peer.
+	_ = vals.VerifyCommitLightWithCache(cv.chainID, blockID, job.block.Height, job.commit, job.sigCache)
+}
+
+// take waits for the job verifying block against commit to finish and returns
+// its part set and signature cache. It returns nils if no such job was
+// scheduled. The job is removed, along with any job below block's height.
+func (cv *commitVerifier) take(block *types.Block, commit *types.Commit) (*types.PartSet, types.SignatureCache) {
+	cv.mtx.Lock()
+	job := cv.jobs[block.Height]
+	for h := range cv.jobs {
+		if h <= block.Height {
+			delete(cv.jobs, h)
+		}
+	}
+	cv.mtx.Unlock()
+
+	if job == nil || job.block != block || job.commit != commit {
+		return nil, nil
+	}
+	<-job.done
+	return job.parts, job.sigCache
+}
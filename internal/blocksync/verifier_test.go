@@ -0,0 +1,102 @@
+package blocksync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/types"
+	cmttime "github.com/cometbft/cometbft/v2/types/time"
+)
+
+// makeSignedChain returns the genesis state and n consecutive blocks, each
+// carrying a valid LastCommit for its predecessor.
+func makeSignedChain(t *testing.T, n int) (sm.State, []*types.Block) {
+	t.Helper()
+
+	genDoc, privVals := randGenesisDoc()
+	state, err := sm.MakeGenesisState(genDoc)
+	require.NoError(t, err)
+
+	pubKey, err := privVals[0].GetPubKey()
+	require.NoError(t, err)
+	idx, _ := state.Validators.GetByAddress(pubKey.Address())
+
+	blocks := make([]*types.Block, 0, n)
+	lastCommit := &types.Commit{}
+	for h := int64(1); h <= int64(n); h++ {
+		block := state.MakeBlock(h, nil, lastCommit, nil, state.Validators.Proposer.Address)
+		parts, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+
+		vote, err := types.MakeVote(privVals[0], genDoc.ChainID, idx, h, 0, types.PrecommitType, blockID, cmttime.Now())
+		require.NoError(t, err)
+		lastCommit = &types.Commit{
+			Height:     h,
+			BlockID:    blockID,
+			Signatures: []types.CommitSig{vote.CommitSig()},
+		}
+		blocks = append(blocks, block)
+	}
+	return state, blocks
+}
+
+func TestCommitVerifierTake(t *testing.T) {
+	state, blocks := makeSignedChain(t, 4)
+	cv := newCommitVerifier(state.ChainID, 3, 2)
+
+	cv.schedule(state, blocks)
+
+	parts, sigCache := cv.take(blocks[0], blocks[1].LastCommit)
+	require.NotNil(t, parts)
+	require.NotNil(t, sigCache)
+	assert.Equal(t, blocks[1].LastCommit.BlockID.PartSetHeader, parts.Header())
+	assert.Equal(t, 1, sigCache.Len())
+
+	// The cached signatures must be accepted by the ordered verification.
+	blockID := types.BlockID{Hash: blocks[0].Hash(), PartSetHeader: parts.Header()}
+	require.NoError(t, state.Validators.VerifyCommitLightWithCache(
+		state.ChainID, blockID, blocks[0].Height, blocks[1].LastCommit, sigCache))
+
+	// A job is handed out only once.
+	parts, sigCache = cv.take(blocks[0], blocks[1].LastCommit)
+	assert.Nil(t, parts)
+	assert.Nil(t, sigCache)
+
+	// The last block has no successor, so it must not have been scheduled.
+	parts, _ = cv.take(blocks[3], nil)
+	assert.Nil(t, parts)
+}
+
+func TestCommitVerifierReplacedBlock(t *testing.T) {
+	state, blocks := makeSignedChain(t, 3)
+	cv := newCommitVerifier(state.ChainID, 2, 1)
+
+	cv.schedule(state, blocks)
+
+	// The pool redid the request and now holds a different block.
+	_, other := makeSignedChain(t, 2)
+	parts, sigCache := cv.take(other[1], blocks[2].LastCommit)
+	assert.Nil(t, parts)
+	assert.Nil(t, sigCache)
+}
+
+func TestCommitVerifierInvalidSignature(t *testing.T) {
+	state, blocks := makeSignedChain(t, 2)
+	cv := newCommitVerifier(state.ChainID, 1, 1)
+
+	commit := *blocks[1].LastCommit
+	commit.Signatures = []types.CommitSig{commit.Signatures[0]}
+	commit.Signatures[0].Signature = make([]byte, len(commit.Signatures[0].Signature))
+	blocks[1].LastCommit = &commit
+
+	cv.schedule(state, blocks)
+
+	parts, sigCache := cv.take(blocks[0], &commit)
+	require.NotNil(t, parts)
+	require.NotNil(t, sigCache)
+	assert.Zero(t, sigCache.Len())
+}
@@ -0,0 +1,74 @@
+// Package compression compresses the records stored in the databases of a
+// node. A compressed record starts with a marker which cannot start a
+// protobuf-encoded message, so that uncompressed records remain readable.
+package compression
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// None stores records as they are.
+	None = "none"
+	// Zstd compresses records with zstd.
+	Zstd = "zstd"
+)
+
+// zstdMarker prefixes the records compressed with zstd. A protobuf message
+// cannot start with a zero byte, since field numbers start at 1.
+var zstdMarker = []byte{0x00, 0x01}
+
+var (
+	// EncodeAll and DecodeAll can be used concurrently.
+	encoder, _ = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	decoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+)
+
+// Validate returns an error if algorithm is not a supported compression
+// algorithm. An empty algorithm is the same as None.
+func Validate(algorithm string) error {
+	switch algorithm {
+	case "", None, Zstd:
+		return nil
+	default:
+		return fmt.Errorf("unknown compression algorithm %q, expected %q or %q", algorithm, None, Zstd)
+	}
+}
+
+// Encode returns bz compressed with algorithm, which must be valid.
+func Encode(algorithm string, bz []byte) []byte {
+	if algorithm != Zstd {
+		return bz
+	}
+	return encoder.EncodeAll(bz, bytes.Clone(zstdMarker))
+}
+
+// Decode returns the original record of bz, which may or may not be
+// compressed.
+func Decode(bz []byte) ([]byte, error) {
+	if !bytes.HasPrefix(bz, zstdMarker) {
+		return bz, nil
+	}
+	out, err := decoder.DecodeAll(bz[len(zstdMarker):], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing record: %w", err)
+	}
+	return out, nil
+}
+
+// Recode returns bz encoded with algorithm, and whether it changed, that is
+// whether bz was encoded with another algorithm.
+func Recode(algorithm string, bz []byte) ([]byte, bool, error) {
+	compressed := bytes.HasPrefix(bz, zstdMarker)
+	if compressed == (algorithm == Zstd) {
+		return bz, false, nil
+	}
+	raw, err := Decode(bz)
+	if err != nil {
+		return nil, false, err
+	}
+	return Encode(algorithm, raw), true, nil
+}
@@ -0,0 +1,49 @@
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	record := bytes.Repeat([]byte("block part"), 100)
+
+	for _, algorithm := range []string{"", None, Zstd} {
+		encoded := Encode(algorithm, record)
+		decoded, err := Decode(encoded)
+		require.NoError(t, err)
+		require.Equal(t, record, decoded)
+	}
+	require.Equal(t, record, Encode(None, record))
+	require.Less(t, len(Encode(Zstd, record)), len(record))
+
+	_, err := Decode(append([]byte{0x00, 0x01}, "garbage"...))
+	require.Error(t, err)
+}
+
+func TestRecode(t *testing.T) {
+	record := bytes.Repeat([]byte("abci response"), 100)
+	compressed := Encode(Zstd, record)
+
+	bz, changed, err := Recode(Zstd, record)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, compressed, bz)
+
+	_, changed, err = Recode(Zstd, compressed)
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	bz, changed, err = Recode(None, compressed)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, record, bz)
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(""))
+	require.NoError(t, Validate(Zstd))
+	require.Error(t, Validate("gzip"))
+}
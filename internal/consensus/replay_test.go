@@ -1221,8 +1221,9 @@ func (bs *mockBlockStore) PruneBlocks(height int64, _ sm.State) (uint64, int64,
 	return pruned, evidencePoint, nil
 }
 
-func (*mockBlockStore) DeleteLatestBlock() error { return nil }
-func (*mockBlockStore) Close() error             { return nil }
+func (*mockBlockStore) DeleteLatestBlock() error               { return nil }
+func (*mockBlockStore) DeleteBlocksAbove(int64) (int64, error) { return 0, nil }
+func (*mockBlockStore) Close() error                           { return nil }
 
 // ---------------------------------------
 // Test handshake/init chain
@@ -46,8 +46,10 @@ func Routes(cfg config.RPCConfig, s state.Store, bs state.BlockStore, txidx txin
 		"header_by_hash":   server.NewRPCFunc(env.HeaderByHash, "hash"),
 		"validators":       server.NewRPCFunc(env.Validators, "height,page,per_page"),
 		"tx":               server.NewRPCFunc(env.Tx, "hash,prove"),
-		"tx_search":        server.NewRPCFunc(env.TxSearch, "query,prove,page,per_page,order_by"),
-		"block_search":     server.NewRPCFunc(env.BlockSearch, "query,page,per_page,order_by"),
+		"tx_search":        server.NewRPCFunc(env.TxSearch, "query,prove,page,per_page,order_by,cursor,limit"),
+		"block_search":     server.NewRPCFunc(env.BlockSearch, "query,page,per_page,order_by,cursor,limit"),
+		"tx_count":         server.NewRPCFunc(env.TxCount, "query,bucket_size"),
+		"block_count":      server.NewRPCFunc(env.BlockCount, "query,bucket_size"),
 	}
 }
 
@@ -0,0 +1,82 @@
+// Package keymigration rewrites the keys of a database from one key layout to
+// another.
+package keymigration
+
+import (
+	"fmt"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+// RenameFunc returns the new key of an entry given its old key.
+type RenameFunc func(key []byte) ([]byte, error)
+
+type entry struct {
+	key, value []byte
+}
+
+// Migrate moves the values of all the keys of db starting with prefix to the
+// keys returned by rename, deleting the old keys, in batches of at most
+// batchSize keys. Each batch is written atomically, so an interrupted
+// migration is resumed by calling Migrate again. The new keys must not start
+// with prefix. It returns the number of migrated keys.
+func Migrate(db dbm.DB, prefix []byte, rename RenameFunc, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+
+	var migrated int64
+	for {
+		// The migrated keys are deleted, so each pass starts over from the
+		// first remaining key.
+		entries, err := nextEntries(db, prefix, batchSize)
+		if err != nil {
+			return migrated, err
+		}
+		if len(entries) == 0 {
+			return migrated, nil
+		}
+
+		batch := db.NewBatch()
+		for _, e := range entries {
+			newKey, err := rename(e.key)
+			if err != nil {
+				_ = batch.Close()
+				return migrated, fmt.Errorf("key %q: %w", e.key, err)
+			}
+			if err := batch.Set(newKey, e.value); err != nil {
+				_ = batch.Close()
+				return migrated, err
+			}
+			if err := batch.Delete(e.key); err != nil {
+				_ = batch.Close()
+				return migrated, err
+			}
+		}
+		err = batch.WriteSync()
+		_ = batch.Close()
+		if err != nil {
+			return migrated, err
+		}
+		migrated += int64(len(entries))
+	}
+}
+
+// nextEntries returns up to n entries of db whose keys start with prefix.
+func nextEntries(db dbm.DB, prefix []byte, n int) ([]entry, error) {
+	it, err := dbm.IteratePrefix(db, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	entries := make([]entry, 0, n)
+	for ; it.Valid() && len(entries) < n; it.Next() {
+		// The iterator owns the returned slices: copy them.
+		entries = append(entries, entry{
+			key:   append([]byte(nil), it.Key()...),
+			value: append([]byte(nil), it.Value()...),
+		})
+	}
+	return entries, it.Error()
+}
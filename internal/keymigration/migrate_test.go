@@ -0,0 +1,72 @@
+package keymigration
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+func upper(key []byte) ([]byte, error) {
+	return fmt.Appendf(nil, "B%s", key[1:]), nil
+}
+
+func TestMigrate(t *testing.T) {
+	db := dbm.NewMemDB()
+	for i := 0; i < 25; i++ {
+		require.NoError(t, db.Set(fmt.Appendf(nil, "a%02d", i), []byte{byte(i)}))
+	}
+	require.NoError(t, db.Set([]byte("other"), []byte("value")))
+
+	n, err := Migrate(db, []byte("a"), upper, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 25, n)
+
+	for i := 0; i < 25; i++ {
+		has, err := db.Has(fmt.Appendf(nil, "a%02d", i))
+		require.NoError(t, err)
+		assert.False(t, has)
+		value, err := db.Get(fmt.Appendf(nil, "B%02d", i))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{byte(i)}, value)
+	}
+	value, err := db.Get([]byte("other"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	_, err = Migrate(db, []byte("a"), upper, 0)
+	require.Error(t, err)
+}
+
+func TestMigrateResume(t *testing.T) {
+	db := dbm.NewMemDB()
+	for i := 0; i < 25; i++ {
+		require.NoError(t, db.Set(fmt.Appendf(nil, "a%02d", i), []byte{byte(i)}))
+	}
+
+	// Fail in the middle of the third batch: the first two are persisted.
+	errRename := errors.New("rename failed")
+	calls := 0
+	failing := func(key []byte) ([]byte, error) {
+		if calls++; calls == 25 {
+			return nil, errRename
+		}
+		return upper(key)
+	}
+	n, err := Migrate(db, []byte("a"), failing, 10)
+	require.ErrorIs(t, err, errRename)
+	assert.EqualValues(t, 20, n)
+
+	n, err = Migrate(db, []byte("a"), upper, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, n)
+	for i := 0; i < 25; i++ {
+		value, err := db.Get(fmt.Appendf(nil, "B%02d", i))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{byte(i)}, value)
+	}
+}
@@ -0,0 +1,77 @@
+// Package servelimit bounds the number of requests from peers that a reactor
+// serves at the same time.
+package servelimit
+
+import (
+	cmtsync "github.com/cometbft/cometbft/v2/libs/sync"
+	"github.com/cometbft/cometbft/v2/p2p"
+)
+
+// Limiter tracks the requests being served, globally and per peer. A limit
+// of 0 means unlimited. It is safe for concurrent use.
+type Limiter struct {
+	maxTotal   int
+	maxPerPeer int
+
+	mtx    cmtsync.Mutex
+	total  int
+	byPeer map[p2p.ID]int
+}
+
+// New returns a Limiter allowing at most maxTotal requests in flight overall
+// and at most maxPerPeer requests in flight per peer.
+func New(maxTotal, maxPerPeer int) *Limiter {
+	return &Limiter{
+		maxTotal:   maxTotal,
+		maxPerPeer: maxPerPeer,
+		byPeer:     make(map[p2p.ID]int),
+	}
+}
+
+// Unlimited returns true if l does not enforce any limit.
+func (l *Limiter) Unlimited() bool {
+	return l.maxTotal <= 0 && l.maxPerPeer <= 0
+}
+
+// Acquire reserves a slot to serve a request from peerID. It returns false,
+// without blocking, if either limit has been reached. Every successful call
+// must be paired with a call to Release.
+func (l *Limiter) Acquire(peerID p2p.ID) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.maxTotal > 0 && l.total >= l.maxTotal {
+		return false
+	}
+	if l.maxPerPeer > 0 && l.byPeer[peerID] >= l.maxPerPeer {
+		return false
+	}
+	l.total++
+	l.byPeer[peerID]++
+	return true
+}
+
+// Release frees a slot previously reserved with Acquire.
+func (l *Limiter) Release(peerID p2p.ID) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	n, ok := l.byPeer[peerID]
+	if !ok {
+		return
+	}
+	if n <= 1 {
+		delete(l.byPeer, peerID)
+	} else {
+		l.byPeer[peerID] = n - 1
+	}
+	l.total--
+}
+
+// InFlight returns the number of requests being served, overall and for
+// peerID.
+func (l *Limiter) InFlight(peerID p2p.ID) (total, peer int) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.total, l.byPeer[peerID]
+}
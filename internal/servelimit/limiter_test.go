@@ -0,0 +1,47 @@
+package servelimit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	l := New(3, 2)
+	require.False(t, l.Unlimited())
+
+	assert.True(t, l.Acquire("a"))
+	assert.True(t, l.Acquire("a"))
+	assert.False(t, l.Acquire("a"), "per-peer limit")
+	assert.True(t, l.Acquire("b"))
+	assert.False(t, l.Acquire("c"), "global limit")
+
+	total, peer := l.InFlight("a")
+	assert.Equal(t, 3, total)
+	assert.Equal(t, 2, peer)
+
+	l.Release("a")
+	assert.True(t, l.Acquire("c"))
+	assert.False(t, l.Acquire("a"))
+
+	l.Release("a")
+	l.Release("b")
+	l.Release("c")
+	total, peer = l.InFlight("a")
+	assert.Equal(t, 0, total)
+	assert.Equal(t, 0, peer)
+
+	// Releasing without acquiring is a no-op.
+	l.Release("d")
+	total, _ = l.InFlight("d")
+	assert.Equal(t, 0, total)
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	l := New(0, 0)
+	require.True(t, l.Unlimited())
+	for i := 0; i < 100; i++ {
+		require.True(t, l.Acquire("a"))
+	}
+}
@@ -2,6 +2,7 @@
 // subscriptions in CometBFT.
 //
 //	abci.invoice.number=22 AND abci.invoice.owner=Ivan
+//	(transfer.sender='a' OR transfer.recipient='a') AND NOT tx.height=3
 //
 // Query expressions can handle attribute values encoding numbers, strings,
 // dates, and timestamps.  The complete query grammar is described in the
@@ -24,17 +25,18 @@ var All *Query
 
 // A Query is the compiled form of a query.
 type Query struct {
-	ast   syntax.Query
-	conds []condition
+	expr *syntax.Expr
+	ast  syntax.Query // the conditions, if expr is a conjunction
+	root node
 }
 
 // New parses and compiles the query expression into an executable query.
 func New(query string) (*Query, error) {
-	ast, err := syntax.Parse(query)
+	expr, err := syntax.ParseExpr(query)
 	if err != nil {
 		return nil, err
 	}
-	return Compile(ast)
+	return CompileExpr(expr)
 }
 
 // MustCompile compiles the query expression into an executable query.
@@ -52,15 +54,22 @@ func MustCompile(query string) *Query {
 
 // Compile compiles the given query AST so it can be used to match events.
 func Compile(ast syntax.Query) (*Query, error) {
-	conds := make([]condition, len(ast))
-	for i, q := range ast {
-		cond, err := compileCondition(q)
-		if err != nil {
-			return nil, fmt.Errorf("compile %s: %w", q, err)
-		}
-		conds[i] = cond
+	args := make([]*syntax.Expr, len(ast))
+	for i, cond := range ast {
+		args[i] = syntax.NewConditionExpr(cond)
+	}
+	return CompileExpr(&syntax.Expr{Op: syntax.TAnd, Args: args})
+}
+
+// CompileExpr compiles the given query expression so it can be used to match
+// events.
+func CompileExpr(expr *syntax.Expr) (*Query, error) {
+	root, err := compileNode(expr)
+	if err != nil {
+		return nil, err
 	}
-	return &Query{ast: ast, conds: conds}, nil
+	ast, _ := expr.Conjunction()
+	return &Query{expr: expr, ast: ast, root: root}, nil
 }
 
 func ExpandEvents(flattenedEvents map[string][]string) []types.Event {
@@ -100,10 +109,11 @@ func (q *Query) String() string {
 	if q == nil {
 		return "<empty>"
 	}
-	return q.ast.String()
+	return q.expr.String()
 }
 
-// Syntax returns the syntax tree representation of q.
+// Syntax returns the conditions of q if q is a conjunction of conditions, or
+// nil otherwise: use Expr to get the syntax tree of any query.
 func (q *Query) Syntax() syntax.Query {
 	if q == nil {
 		return nil
@@ -111,14 +121,76 @@ func (q *Query) Syntax() syntax.Query {
 	return q.ast
 }
 
-// matchesEvents reports whether all the conditions match the given events.
+// Expr returns the syntax tree representation of q.
+func (q *Query) Expr() *syntax.Expr {
+	if q == nil {
+		return nil
+	}
+	return q.expr
+}
+
+// matchesEvents reports whether the query expression matches the given
+// events.
 func (q *Query) matchesEvents(events []types.Event) bool {
-	for _, cond := range q.conds {
-		if !cond.matchesAny(events) {
-			return false
+	return len(events) != 0 && q.root.matches(events)
+}
+
+// A node is a compiled query expression. A condition node matches events if
+// its condition matches any of them.
+type node struct {
+	op   syntax.Token // TAnd, TOr, TNot, or TInvalid for a condition
+	cond condition
+	args []node
+}
+
+func compileNode(expr *syntax.Expr) (node, error) {
+	if expr.Cond != nil {
+		cond, err := compileCondition(*expr.Cond)
+		if err != nil {
+			return node{}, fmt.Errorf("compile %s: %w", expr.Cond, err)
+		}
+		return node{cond: cond}, nil
+	}
+	switch expr.Op {
+	case syntax.TAnd, syntax.TOr, syntax.TNot:
+	default:
+		return node{}, fmt.Errorf("compile %s: unexpected operator %v", expr, expr.Op)
+	}
+	if expr.Op == syntax.TNot && len(expr.Args) != 1 {
+		return node{}, fmt.Errorf("compile %s: NOT takes one operand", expr)
+	}
+	n := node{op: expr.Op, args: make([]node, len(expr.Args))}
+	for i, arg := range expr.Args {
+		var err error
+		if n.args[i], err = compileNode(arg); err != nil {
+			return node{}, err
 		}
 	}
-	return len(events) != 0
+	return n, nil
+}
+
+// matches reports whether n matches the given events.
+func (n node) matches(events []types.Event) bool {
+	switch n.op {
+	case syntax.TAnd:
+		for _, arg := range n.args {
+			if !arg.matches(events) {
+				return false
+			}
+		}
+		return true
+	case syntax.TOr:
+		for _, arg := range n.args {
+			if arg.matches(events) {
+				return true
+			}
+		}
+		return false
+	case syntax.TNot:
+		return !n.args[0].matches(events)
+	default:
+		return n.cond.matchesAny(events)
+	}
 }
 
 // A condition is a compiled match condition.  A condition matches an event if
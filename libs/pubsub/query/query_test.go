@@ -478,6 +478,48 @@ func TestCompiledMatches(t *testing.T) {
 			`tm.event = 'Tx' AND rewards.withdraw.source = 'W'`,
 			apiEvents, false,
 		},
+
+		// OR, NOT and grouping
+		{
+			`transfer.sender = 'AddrZ' OR transfer.recipient = 'AddrD'`,
+			apiEvents, true,
+		},
+		{
+			`transfer.sender = 'AddrZ' OR transfer.recipient = 'AddrZ'`,
+			apiEvents, false,
+		},
+		{
+			`tm.event = 'Tx' AND (transfer.sender = 'AddrZ' OR rewards.withdraw.address = 'AddrB')`,
+			apiEvents, true,
+		},
+		{
+			`tm.event = 'NewBlock' AND (transfer.sender = 'AddrC' OR rewards.withdraw.address = 'AddrB')`,
+			apiEvents, false,
+		},
+		{
+			`tm.event = 'NewBlock' AND transfer.sender = 'AddrZ' OR transfer.amount > 100`,
+			apiEvents, true,
+		},
+		{
+			`NOT transfer.sender = 'AddrC'`,
+			apiEvents, false,
+		},
+		{
+			`tm.event = 'Tx' AND NOT slash.reason EXISTS`,
+			apiEvents, true,
+		},
+		{
+			`NOT (transfer.sender = 'AddrZ' OR tm.height > 5)`,
+			apiEvents, true,
+		},
+		{
+			`NOT NOT transfer.sender = 'AddrC'`,
+			apiEvents, true,
+		},
+		{
+			`NOT slash.reason EXISTS`,
+			map[string][]string{}, false,
+		},
 	}
 
 	// NOTE: The original implementation allowed arbitrary prefix matches on
@@ -525,6 +567,22 @@ func sortEvents(events []types.Event) []types.Event {
 	return events
 }
 
+func TestSyntaxAndExpr(t *testing.T) {
+	q := query.MustCompile(`a.x = 1 AND b.y > 2`)
+	require.Len(t, q.Syntax(), 2)
+	require.Equal(t, `a.x = 1 AND b.y > 2`, q.String())
+
+	q = query.MustCompile(`a.x = 1 AND (b.y > 2 OR NOT c.z EXISTS)`)
+	require.Nil(t, q.Syntax())
+	require.EqualValues(t, syntax.TAnd, q.Expr().Op)
+	require.Equal(t, `a.x = 1 AND (b.y > 2 OR NOT c.z EXISTS)`, q.String())
+
+	_, err := query.New(`a.x = 1 OR b.y = 'z'`)
+	require.NoError(t, err)
+	_, err = query.New(`a.x = 1 OR b.y CONTAINS 3`)
+	require.Error(t, err)
+}
+
 func TestExpandEvents(t *testing.T) {
 	expanded := query.ExpandEvents(apiEvents)
 	bz, err := json.Marshal(sortEvents(expanded))
@@ -5,8 +5,10 @@
 //
 // The grammar of the query language is defined by the following EBNF:
 //
-//	query      = conditions EOF
-//	conditions = condition {"AND" condition}
+//	query      = or EOF
+//	or         = and {"OR" and}
+//	and        = factor {"AND" factor}
+//	factor     = "NOT" factor / "(" or ")" / condition
 //	condition  = tag comparison
 //	comparison = equal / order / contains / "EXISTS"
 //	equal      = "=" (date / number / time / value)
@@ -14,6 +16,10 @@
 //	contains   = "CONTAINS" value
 //	cmp        = "<" / "<=" / ">" / ">="
 //
+// AND binds more tightly than OR, so "a AND b OR c" is "(a AND b) OR c".
+// Parse only accepts a conjunction of conditions, whereas ParseExpr accepts
+// any query.
+//
 // The lexical terms are defined here using RE2 regular expression notation:
 //
 //	// The name of an event attribute (type.value)
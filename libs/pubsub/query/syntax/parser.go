@@ -14,6 +14,12 @@ func Parse(s string) (Query, error) {
 	return NewParser(strings.NewReader(s)).Parse()
 }
 
+// ParseExpr parses the specified query expression. It is shorthand for
+// constructing a parser for s and calling its ParseExpr method.
+func ParseExpr(s string) (*Expr, error) {
+	return NewParser(strings.NewReader(s)).ParseExpr()
+}
+
 // Query is the root of the parse tree for a query.  A query is the conjunction
 // of one or more conditions.
 type Query []Condition
@@ -26,6 +32,93 @@ func (q Query) String() string {
 	return strings.Join(ss, " AND ")
 }
 
+// An Expr is a node of the parse tree of a query expression: either a single
+// condition, or the conjunction (AND), disjunction (OR) or negation (NOT) of
+// other expressions.
+type Expr struct {
+	// Op is TAnd, TOr or TNot for a compound expression, or TInvalid if the
+	// expression is a single condition.
+	Op Token
+	// Cond is the condition of a single condition expression.
+	Cond *Condition
+	// Args are the operands of a compound expression. A TNot expression has
+	// exactly one operand, TAnd and TOr expressions have at least two.
+	Args []*Expr
+}
+
+// NewConditionExpr returns the expression consisting of cond alone.
+func NewConditionExpr(cond Condition) *Expr {
+	return &Expr{Cond: &cond}
+}
+
+// NewExpr returns the compound expression applying op, one of TAnd, TOr and
+// TNot, to args. The operands of a TAnd or TOr expression which are
+// themselves TAnd or TOr expressions, respectively, are flattened.
+func NewExpr(op Token, args ...*Expr) *Expr {
+	if op == TNot {
+		return &Expr{Op: op, Args: args[:1]}
+	}
+	var flat []*Expr
+	for _, arg := range args {
+		if arg.Op == op {
+			flat = append(flat, arg.Args...)
+		} else {
+			flat = append(flat, arg)
+		}
+	}
+	if len(flat) == 1 {
+		return flat[0]
+	}
+	return &Expr{Op: op, Args: flat}
+}
+
+// Conjunction returns the conditions of e, and true, if e is a single
+// condition or a conjunction of conditions. Otherwise, it returns nil and
+// false.
+func (e *Expr) Conjunction() (Query, bool) {
+	if e.Cond != nil {
+		return Query{*e.Cond}, true
+	}
+	if e.Op != TAnd {
+		return nil, false
+	}
+	conds := make(Query, len(e.Args))
+	for i, arg := range e.Args {
+		if arg.Cond == nil {
+			return nil, false
+		}
+		conds[i] = *arg.Cond
+	}
+	return conds, true
+}
+
+func (e *Expr) String() string {
+	switch e.Op {
+	case TAnd, TOr:
+		sep := " AND "
+		if e.Op == TOr {
+			sep = " OR "
+		}
+		ss := make([]string, len(e.Args))
+		for i, arg := range e.Args {
+			ss[i] = arg.String()
+			// AND binds more tightly than OR.
+			if e.Op == TAnd && arg.Op == TOr {
+				ss[i] = "(" + ss[i] + ")"
+			}
+		}
+		return strings.Join(ss, sep)
+	case TNot:
+		arg := e.Args[0]
+		if arg.Op == TAnd || arg.Op == TOr {
+			return "NOT (" + arg.String() + ")"
+		}
+		return "NOT " + arg.String()
+	default:
+		return e.Cond.String()
+	}
+}
+
 // A Condition is a single conditional expression, consisting of a tag, a
 // comparison operator, and an optional argument. The type of the argument
 // depends on the operator.
@@ -129,6 +222,11 @@ func (a *Arg) Value() string {
 // defined in the syntax package documentation.
 type Parser struct {
 	scanner *Scanner
+
+	// unread reports whether the current token of the scanner, or the error
+	// err, must be returned again by the next call to next.
+	unread bool
+	err    error
 }
 
 // NewParser constructs a new parser that reads the input from r.
@@ -136,24 +234,96 @@ func NewParser(r io.Reader) *Parser {
 	return &Parser{scanner: NewScanner(r)}
 }
 
-// Parse parses the complete input and returns the resulting query.
+// Parse parses the complete input and returns the resulting query. It reports
+// an error if the input is not a conjunction of conditions: use ParseExpr to
+// parse any query expression.
 func (p *Parser) Parse() (Query, error) {
-	cond, err := p.parseCond()
+	expr, err := p.ParseExpr()
 	if err != nil {
 		return nil, err
 	}
-	conds := []Condition{cond}
-	for p.scanner.Next() != io.EOF {
-		if tok := p.scanner.Token(); tok != TAnd {
-			return nil, fmt.Errorf("offset %d: got %v, want %v", p.scanner.Pos(), tok, TAnd)
+	conds, ok := expr.Conjunction()
+	if !ok {
+		return nil, fmt.Errorf("query %q is not a conjunction of conditions", expr)
+	}
+	return conds, nil
+}
+
+// ParseExpr parses the complete input and returns the resulting expression.
+func (p *Parser) ParseExpr() (*Expr, error) {
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.next(); err != io.EOF {
+		if err != nil {
+			return nil, fmt.Errorf("offset %d: %w", p.scanner.Pos(), err)
 		}
+		return nil, fmt.Errorf("offset %d: got %v, want %v or %v", p.scanner.Pos(), p.scanner.Token(), TAnd, TOr)
+	}
+	return expr, nil
+}
+
+// parseOr parses a disjunction: and {OR and}.
+func (p *Parser) parseOr() (*Expr, error) {
+	return p.parseList(TOr, p.parseAnd)
+}
+
+// parseAnd parses a conjunction: factor {AND factor}.
+func (p *Parser) parseAnd() (*Expr, error) {
+	return p.parseList(TAnd, p.parseFactor)
+}
+
+// parseList parses a list of operands parsed by parseOperand, separated by op.
+func (p *Parser) parseList(op Token, parseOperand func() (*Expr, error)) (*Expr, error) {
+	operand, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*Expr{operand}
+	for {
+		if err := p.next(); err != nil || p.scanner.Token() != op {
+			p.backup()
+			break
+		}
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	return NewExpr(op, operands...), nil
+}
+
+// parseFactor parses a negation, a parenthesized expression or a condition.
+func (p *Parser) parseFactor() (*Expr, error) {
+	if err := p.require(TNot, TLParen, TTag); err != nil {
+		return nil, err
+	}
+	switch p.scanner.Token() {
+	case TNot:
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return NewExpr(TNot, operand), nil
+	case TLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.require(TRParen); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	default:
+		p.backup()
 		cond, err := p.parseCond()
 		if err != nil {
 			return nil, err
 		}
-		conds = append(conds, cond)
+		return NewConditionExpr(cond), nil
 	}
-	return conds, nil
 }
 
 // parseCond parses a conditional expression: tag OP value.
@@ -190,10 +360,23 @@ func (p *Parser) parseCond() (Condition, error) {
 	return cond, nil
 }
 
+// next advances the scanner, unless the current token was backed up.
+func (p *Parser) next() error {
+	if p.unread {
+		p.unread = false
+		return p.err
+	}
+	p.err = p.scanner.Next()
+	return p.err
+}
+
+// backup makes the next call to next return the current token again.
+func (p *Parser) backup() { p.unread = true }
+
 // require advances the scanner and requires that the resulting token is one of
 // the specified token types.
 func (p *Parser) require(tokens ...Token) error {
-	if err := p.scanner.Next(); err != nil {
+	if err := p.next(); err != nil {
 		return fmt.Errorf("offset %d: %w", p.scanner.Pos(), err)
 	}
 	got := p.scanner.Token()
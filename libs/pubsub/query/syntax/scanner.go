@@ -28,6 +28,10 @@ const (
 	TLeq             // operator: <=
 	TGt              // operator: >
 	TGeq             // operator: >=
+	TOr              // operator: OR
+	TNot             // operator: NOT
+	TLParen          // opening parenthesis: (
+	TRParen          // closing parenthesis: )
 
 	// Do not reorder these values without updating the scanner code.
 )
@@ -47,11 +51,15 @@ var tString = [...]string{
 	TLeq:      "<= operator",
 	TGt:       "> operator",
 	TGeq:      ">= operator",
+	TOr:       "OR operator",
+	TNot:      "NOT operator",
+	TLParen:   "(",
+	TRParen:   ")",
 }
 
 func (t Token) String() string {
 	v := int(t)
-	if v > len(tString) {
+	if v >= len(tString) {
 		return "unknown token type"
 	}
 	return tString[v]
@@ -107,6 +115,14 @@ func (s *Scanner) Next() error {
 			return s.scanString(ch)
 		case '<', '>', '=':
 			return s.scanCompare(ch)
+		case '(':
+			s.buf.WriteRune(ch)
+			s.tok = TLParen
+			return nil
+		case ')':
+			s.buf.WriteRune(ch)
+			s.tok = TRParen
+			return nil
 		default:
 			return s.invalid(ch)
 		}
@@ -224,6 +240,10 @@ func (s *Scanner) scanTagLike(first rune) error {
 		s.tok = TTag
 	case "AND":
 		s.tok = TAnd
+	case "OR":
+		s.tok = TOr
+	case "NOT":
+		s.tok = TNot
 	case "EXISTS":
 		s.tok = TExists
 	case "CONTAINS":
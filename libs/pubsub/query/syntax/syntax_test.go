@@ -42,6 +42,10 @@ func TestScanner(t *testing.T) {
 		{`x.y CONTAINS 'z'`, []syntax.Token{syntax.TTag, syntax.TContains, syntax.TString}},
 		{`foo EXISTS`, []syntax.Token{syntax.TTag, syntax.TExists}},
 		{`and AND`, []syntax.Token{syntax.TTag, syntax.TAnd}},
+		{`NOT (x OR y)`, []syntax.Token{
+			syntax.TNot, syntax.TLParen, syntax.TTag, syntax.TOr, syntax.TTag, syntax.TRParen,
+		}},
+		{`x=1)`, []syntax.Token{syntax.TTag, syntax.TEq, syntax.TNumber, syntax.TRParen}},
 
 		// Timestamp
 		{`TIME 2021-11-23T15:16:17Z`, []syntax.Token{syntax.TTime}},
@@ -192,3 +196,66 @@ func TestParseValid(t *testing.T) {
 		}
 	}
 }
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // the canonical form, or "" if the input is invalid
+		conj  bool   // whether the expression is a conjunction of conditions
+	}{
+		{"a.x = 1", "a.x = 1", true},
+		{"a.x = 1 AND b.y = 2", "a.x = 1 AND b.y = 2", true},
+		{"(a.x = 1 AND b.y = 2)", "a.x = 1 AND b.y = 2", true},
+		{"a.x = 1 AND (b.y = 2 AND c.z = 3)", "a.x = 1 AND b.y = 2 AND c.z = 3", true},
+		{"a.x = 1 OR b.y = 2", "a.x = 1 OR b.y = 2", false},
+		{"a.x = 1 AND b.y = 2 OR c.z = 3", "a.x = 1 AND b.y = 2 OR c.z = 3", false},
+		{"a.x = 1 AND (b.y = 2 OR c.z = 3)", "a.x = 1 AND (b.y = 2 OR c.z = 3)", false},
+		{"NOT a.x = 1", "NOT a.x = 1", false},
+		{"NOT (a.x = 1 OR b.y = 2)", "NOT (a.x = 1 OR b.y = 2)", false},
+		{"NOT NOT a.x EXISTS", "NOT NOT a.x EXISTS", false},
+		{"((a.x CONTAINS 'OR'))", "a.x CONTAINS 'OR'", true},
+		{"tx.date > DATE 2013-05-03 OR NOT(tx.date < TIME 2013-05-03T14:45:00Z)",
+			"tx.date > DATE 2013-05-03 OR NOT tx.date < TIME 2013-05-03T14:45:00Z", false},
+		{"OR.x = 1 OR NOT.y = 2", "OR.x = 1 OR NOT.y = 2", false},
+
+		{"", "", false},
+		{"()", "", false},
+		{"(a.x = 1", "", false},
+		{"a.x = 1)", "", false},
+		{"a.x = 1 OR", "", false},
+		{"OR a.x = 1", "", false},
+		{"a.x = 1 NOT b.y = 2", "", false},
+		{"NOT", "", false},
+		{"a.x NOT = 1", "", false},
+	}
+	for _, test := range tests {
+		expr, err := syntax.ParseExpr(test.input)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("ParseExpr %#q: got %#q, want error", test.input, expr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseExpr %#q: unexpected error: %v", test.input, err)
+			continue
+		}
+		if got := expr.String(); got != test.want {
+			t.Errorf("ParseExpr %#q: got %#q, want %#q", test.input, got, test.want)
+		}
+		if _, ok := expr.Conjunction(); ok != test.conj {
+			t.Errorf("ParseExpr %#q: conjunction %v, want %v", test.input, ok, test.conj)
+		}
+
+		// The canonical form round-trips.
+		reparsed, err := syntax.ParseExpr(test.want)
+		if err != nil || reparsed.String() != test.want {
+			t.Errorf("Reparse %#q: got %v, %v", test.want, reparsed, err)
+		}
+
+		// Parse only accepts conjunctions.
+		if _, err := syntax.Parse(test.input); (err == nil) != test.conj {
+			t.Errorf("Parse %#q: got err=%v, want conjunction %v", test.input, err, test.conj)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cometbft/cometbft/v2/light/provider"
+	grpcclient "github.com/cometbft/cometbft/v2/rpc/grpc/client"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// grpc provider uses the light block service of a CometBFT gRPC server to
+// obtain the necessary information.
+type grpc struct {
+	chainID string
+	remote  string
+	client  grpcclient.Client
+}
+
+// New creates a gRPC provider connecting, without transport security, to
+// the gRPC server at remote (host:port).
+func New(ctx context.Context, chainID, remote string) (provider.Provider, error) {
+	client, err := grpcclient.New(ctx, remote,
+		grpcclient.WithInsecure(),
+		grpcclient.WithVersionServiceEnabled(false),
+		grpcclient.WithBlockResultsServiceEnabled(false),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithClient(chainID, remote, client), nil
+}
+
+// NewWithClient allows you to provide a custom client.
+func NewWithClient(chainID, remote string, client grpcclient.Client) provider.Provider {
+	return &grpc{
+		chainID: chainID,
+		remote:  remote,
+		client:  client,
+	}
+}
+
+// ChainID returns a chainID this provider was configured with.
+func (p *grpc) ChainID() string {
+	return p.chainID
+}
+
+func (p *grpc) String() string {
+	return fmt.Sprintf("grpc{%s}", p.remote)
+}
+
+// LightBlock fetches a LightBlock at the given height and checks the
+// chainID matches.
+func (p *grpc) LightBlock(ctx context.Context, height int64) (*types.LightBlock, error) {
+	if height < 0 {
+		return nil, provider.ErrBadLightBlock{Reason: provider.ErrNegativeHeight{Height: height}}
+	}
+
+	lb, err := p.client.GetLightBlock(ctx, height)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	if height != 0 && lb.Height != height {
+		return nil, provider.ErrBadLightBlock{
+			Reason: fmt.Errorf("height %d responded doesn't match height %d requested", lb.Height, height),
+		}
+	}
+	if err := lb.ValidateBasic(p.chainID); err != nil {
+		return nil, provider.ErrBadLightBlock{Reason: err}
+	}
+	return lb, nil
+}
+
+// ConsensusParams fetches the consensus parameters at the given height. They
+// are not verified: callers must check them against the ConsensusHash of a
+// trusted header.
+func (p *grpc) ConsensusParams(ctx context.Context, height int64) (*types.ConsensusParams, error) {
+	params, err := p.client.GetConsensusParams(ctx, height)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	return params, nil
+}
+
+// Block fetches the full block at the given height from the block service of
+// the server. It is not verified: callers must check its hash against a
+// trusted header.
+func (p *grpc) Block(ctx context.Context, height int64) (*types.Block, error) {
+	block, err := p.client.GetBlockByHeight(ctx, height)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	return block.Block, nil
+}
+
+// ReportEvidence is not supported by the gRPC API.
+func (*grpc) ReportEvidence(context.Context, types.Evidence) error {
+	return errors.New("reporting evidence is not supported by gRPC providers")
+}
+
+// providerError maps the status of a failed gRPC call to the errors the
+// light client expects from providers.
+func providerError(err error) error {
+	switch status.Code(err) {
+	case codes.OutOfRange:
+		return provider.ErrHeightTooHigh
+	case codes.NotFound:
+		return provider.ErrLightBlockNotFound
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return provider.ErrNoResponse
+	default:
+		return err
+	}
+}
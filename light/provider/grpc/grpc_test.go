@@ -0,0 +1,70 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	lightblocksvc "github.com/cometbft/cometbft/api/cometbft/services/light_block/v1"
+	"github.com/cometbft/cometbft/v2/light/provider"
+	lightgrpc "github.com/cometbft/cometbft/v2/light/provider/grpc"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// fakeService answers light block requests with the status code of the
+// requested height, and consensus parameters requests with the defaults.
+type fakeService struct{}
+
+func (fakeService) GetLightBlock(_ context.Context, req *lightblocksvc.GetLightBlockRequest) (*lightblocksvc.GetLightBlockResponse, error) {
+	return nil, status.Error(codes.Code(req.Height), "fake error")
+}
+
+func (fakeService) GetConsensusParams(context.Context, *lightblocksvc.GetConsensusParamsRequest) (*lightblocksvc.GetConsensusParamsResponse, error) {
+	params := types.DefaultConsensusParams().ToProto()
+	return &lightblocksvc.GetConsensusParamsResponse{ConsensusParams: &params}, nil
+}
+
+func startServer(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := ggrpc.NewServer()
+	lightblocksvc.RegisterLightBlockServiceServer(srv, fakeService{})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+	return ln.Addr().String()
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	p, err := lightgrpc.New(ctx, "test-chain", startServer(t))
+	require.NoError(t, err)
+	assert.Equal(t, "test-chain", p.ChainID())
+
+	_, err = p.LightBlock(ctx, -1)
+	require.ErrorAs(t, err, &provider.ErrBadLightBlock{})
+
+	_, err = p.LightBlock(ctx, int64(codes.OutOfRange))
+	require.ErrorIs(t, err, provider.ErrHeightTooHigh)
+
+	_, err = p.LightBlock(ctx, int64(codes.NotFound))
+	require.ErrorIs(t, err, provider.ErrLightBlockNotFound)
+
+	_, err = p.LightBlock(ctx, int64(codes.Unavailable))
+	require.ErrorIs(t, err, provider.ErrNoResponse)
+
+	params, err := p.(interface {
+		ConsensusParams(ctx context.Context, height int64) (*types.ConsensusParams, error)
+	}).ConsensusParams(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, types.DefaultConsensusParams().Hash(), params.Hash())
+}
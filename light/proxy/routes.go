@@ -31,8 +31,10 @@ func RPCRoutes(c *lrpc.Client) map[string]*rpcserver.RPCFunc {
 		"block_results":        rpcserver.NewRPCFunc(makeBlockResultsFunc(c), "height", rpcserver.Cacheable("height")),
 		"commit":               rpcserver.NewRPCFunc(makeCommitFunc(c), "height", rpcserver.Cacheable("height")),
 		"tx":                   rpcserver.NewRPCFunc(makeTxFunc(c), "hash,prove", rpcserver.Cacheable()),
-		"tx_search":            rpcserver.NewRPCFunc(makeTxSearchFunc(c), "query,prove,page,per_page,order_by"),
-		"block_search":         rpcserver.NewRPCFunc(makeBlockSearchFunc(c), "query,page,per_page,order_by"),
+		"tx_search":            rpcserver.NewRPCFunc(makeTxSearchFunc(c), "query,prove,page,per_page,order_by,cursor,limit"),
+		"block_search":         rpcserver.NewRPCFunc(makeBlockSearchFunc(c), "query,page,per_page,order_by,cursor,limit"),
+		"tx_count":             rpcserver.NewRPCFunc(makeTxCountFunc(c), "query,bucket_size"),
+		"block_count":          rpcserver.NewRPCFunc(makeBlockCountFunc(c), "query,bucket_size"),
 		"validators":           rpcserver.NewRPCFunc(makeValidatorsFunc(c), "height,page,per_page", rpcserver.Cacheable("height")),
 		"dump_consensus_state": rpcserver.NewRPCFunc(makeDumpConsensusStateFunc(c), ""),
 		"consensus_state":      rpcserver.NewRPCFunc(makeConsensusStateFunc(c), ""),
@@ -165,6 +167,8 @@ type rpcTxSearchFunc func(
 	prove bool,
 	page, perPage *int,
 	orderBy string,
+	cursor string,
+	limit *int,
 ) (*ctypes.ResultTxSearch, error)
 
 func makeTxSearchFunc(c *lrpc.Client) rpcTxSearchFunc {
@@ -174,28 +178,46 @@ func makeTxSearchFunc(c *lrpc.Client) rpcTxSearchFunc {
 		prove bool,
 		page, perPage *int,
 		orderBy string,
+		cursor string,
+		limit *int,
 	) (*ctypes.ResultTxSearch, error) {
-		return c.TxSearch(ctx.Context(), query, prove, page, perPage, orderBy)
+		return c.TxSearch(ctx.Context(), query, prove, page, perPage, orderBy, cursor, limit)
 	}
 }
 
 type rpcBlockSearchFunc func(
 	ctx *rpctypes.Context,
 	query string,
-	prove bool,
 	page, perPage *int,
 	orderBy string,
+	cursor string,
+	limit *int,
 ) (*ctypes.ResultBlockSearch, error)
 
 func makeBlockSearchFunc(c *lrpc.Client) rpcBlockSearchFunc {
 	return func(
 		ctx *rpctypes.Context,
 		query string,
-		_ bool,
 		page, perPage *int,
 		orderBy string,
+		cursor string,
+		limit *int,
 	) (*ctypes.ResultBlockSearch, error) {
-		return c.BlockSearch(ctx.Context(), query, page, perPage, orderBy)
+		return c.BlockSearch(ctx.Context(), query, page, perPage, orderBy, cursor, limit)
+	}
+}
+
+type rpcCountFunc func(ctx *rpctypes.Context, query string, bucketSize *int64) (*ctypes.ResultCount, error)
+
+func makeTxCountFunc(c *lrpc.Client) rpcCountFunc {
+	return func(ctx *rpctypes.Context, query string, bucketSize *int64) (*ctypes.ResultCount, error) {
+		return c.TxCount(ctx.Context(), query, bucketSize)
+	}
+}
+
+func makeBlockCountFunc(c *lrpc.Client) rpcCountFunc {
+	return func(ctx *rpctypes.Context, query string, bucketSize *int64) (*ctypes.ResultCount, error) {
+		return c.BlockCount(ctx.Context(), query, bucketSize)
 	}
 }
 
@@ -493,8 +493,10 @@ func (c *Client) TxSearch(
 	prove bool,
 	page, perPage *int,
 	orderBy string,
+	cursor string,
+	limit *int,
 ) (*ctypes.ResultTxSearch, error) {
-	return c.next.TxSearch(ctx, query, prove, page, perPage, orderBy)
+	return c.next.TxSearch(ctx, query, prove, page, perPage, orderBy, cursor, limit)
 }
 
 func (c *Client) BlockSearch(
@@ -502,8 +504,20 @@ func (c *Client) BlockSearch(
 	query string,
 	page, perPage *int,
 	orderBy string,
+	cursor string,
+	limit *int,
 ) (*ctypes.ResultBlockSearch, error) {
-	return c.next.BlockSearch(ctx, query, page, perPage, orderBy)
+	return c.next.BlockSearch(ctx, query, page, perPage, orderBy, cursor, limit)
+}
+
+// TxCount calls rpcclient#TxCount. The counts are not verified.
+func (c *Client) TxCount(ctx context.Context, query string, bucketSize *int64) (*ctypes.ResultCount, error) {
+	return c.next.TxCount(ctx, query, bucketSize)
+}
+
+// BlockCount calls rpcclient#BlockCount. The counts are not verified.
+func (c *Client) BlockCount(ctx context.Context, query string, bucketSize *int64) (*ctypes.ResultCount, error) {
+	return c.next.BlockCount(ctx, query, bucketSize)
 }
 
 // Validators fetches and verifies validators.
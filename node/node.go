@@ -27,13 +27,13 @@ import (
 	"github.com/cometbft/cometbft/v2/libs/log"
 	cmtpubsub "github.com/cometbft/cometbft/v2/libs/pubsub"
 	"github.com/cometbft/cometbft/v2/libs/service"
-	"github.com/cometbft/cometbft/v2/light"
 	mempl "github.com/cometbft/cometbft/v2/mempool"
 	"github.com/cometbft/cometbft/v2/p2p"
 	na "github.com/cometbft/cometbft/v2/p2p/netaddr"
 	"github.com/cometbft/cometbft/v2/p2p/pex"
 	"github.com/cometbft/cometbft/v2/p2p/transport/tcp"
 	"github.com/cometbft/cometbft/v2/proxy"
+	"github.com/cometbft/cometbft/v2/rpc/auth"
 	rpccore "github.com/cometbft/cometbft/v2/rpc/core"
 	grpcserver "github.com/cometbft/cometbft/v2/rpc/grpc/server"
 	grpcprivserver "github.com/cometbft/cometbft/v2/rpc/grpc/server/privileged"
@@ -179,7 +179,7 @@ func BootstrapState(ctx context.Context, config *cfg.Config, dbProvider cfg.DBPr
 	}
 	blockStoreDB, stateDB, err := initDBs(config, dbProvider)
 
-	blockStore := store.NewBlockStore(blockStoreDB, store.WithMetrics(store.NopMetrics()), store.WithCompaction(config.Storage.Compact, config.Storage.CompactionInterval), store.WithDBKeyLayout(config.Storage.ExperimentalKeyLayout))
+	blockStore := store.NewBlockStore(blockStoreDB, store.WithMetrics(store.NopMetrics()), store.WithCompaction(config.Storage.Compact, config.Storage.CompactionInterval), store.WithDBKeyLayout(config.Storage.ExperimentalKeyLayout), store.WithCompression(config.Storage.Compression))
 	logger.Info("Blockstore version", "version", blockStore.GetVersion())
 
 	defer func() {
@@ -202,6 +202,7 @@ func BootstrapState(ctx context.Context, config *cfg.Config, dbProvider cfg.DBPr
 		DiscardABCIResponses: config.Storage.DiscardABCIResponses,
 		Logger:               logger,
 		DBKeyLayout:          config.Storage.ExperimentalKeyLayout,
+		Compression:          config.Storage.Compression,
 	})
 
 	defer func() {
@@ -226,14 +227,10 @@ func BootstrapState(ctx context.Context, config *cfg.Config, dbProvider cfg.DBPr
 		return err
 	}
 
-	stateProvider, err := statesync.NewLightClientStateProviderWithDBKeyVersion(
-		ctx,
+	stateProvider, err := statesync.NewStateProviderFromConfig(
+		ctx, config.StateSync,
 		genState.ChainID, genState.Version, genState.InitialHeight,
-		config.StateSync.RPCServers, light.TrustOptions{
-			Period: config.StateSync.TrustPeriod,
-			Height: config.StateSync.TrustHeight,
-			Hash:   config.StateSync.TrustHashBytes(),
-		}, logger.With("module", "light"),
+		logger.With("module", "light"),
 		config.Storage.ExperimentalKeyLayout)
 	if err != nil {
 		return ErrLightClientStateProvider{Err: err}
@@ -346,9 +343,14 @@ func NewNodeWithCliParams(ctx context.Context,
 		CompactionInterval:   config.Storage.CompactionInterval,
 		Logger:               logger,
 		DBKeyLayout:          config.Storage.ExperimentalKeyLayout,
+		Compression:          config.Storage.Compression,
 	})
 
-	blockStore := store.NewBlockStore(blockStoreDB, store.WithMetrics(bstMetrics), store.WithCompaction(config.Storage.Compact, config.Storage.CompactionInterval), store.WithDBKeyLayout(config.Storage.ExperimentalKeyLayout))
+	coldBlockStoreDB, err := cfg.ColdBlockStoreDB(config)
+	if err != nil {
+		return nil, err
+	}
+	blockStore := store.NewBlockStore(blockStoreDB, store.WithMetrics(bstMetrics), store.WithCompaction(config.Storage.Compact, config.Storage.CompactionInterval), store.WithDBKeyLayout(config.Storage.ExperimentalKeyLayout), store.WithColdStore(coldBlockStoreDB), store.WithCompression(config.Storage.Compression))
 	logger.Info("Blockstore version", "version", blockStore.GetVersion())
 
 	// The key will be deleted if it existed.
@@ -378,7 +380,7 @@ func NewNodeWithCliParams(ctx context.Context,
 	}
 
 	indexerService, txIndexer, blockIndexer, err := createAndStartIndexerService(config,
-		genDoc.ChainID, dbProvider, eventBus, logger)
+		genDoc.ChainID, dbProvider, eventBus, blockStore, stateStore, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -441,10 +443,18 @@ func NewNodeWithCliParams(ctx context.Context,
 		return nil, err
 	}
 
+	// The additional indexer sinks are pruned along with the indexers serving
+	// the RPC.
+	var indexerSinks []txindex.Sink
+	if indexerService != nil {
+		indexerSinks = indexerService.Sinks()[1:]
+	}
+
 	pruner, err := createPruner(
 		config,
 		txIndexer,
 		blockIndexer,
+		indexerSinks,
 		stateStore,
 		blockStore,
 		smMetrics,
@@ -857,6 +867,37 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 		config.WriteTimeout = n.config.RPC.TimeoutBroadcastTxCommit + 1*time.Second
 	}
 
+	var rpcAuthenticator, grpcAuthenticator *auth.Authenticator
+	if n.config.RPC.IsAuthEnabled() {
+		rpcAuthenticator, err = auth.NewAuthenticatorFromFile(n.config.RPC.AuthTokensFilePath())
+		if err != nil {
+			return nil, fmt.Errorf("loading RPC auth tokens: %w", err)
+		}
+	}
+	if n.config.GRPC.IsAuthEnabled() {
+		grpcAuthenticator, err = auth.NewAuthenticatorFromFile(n.config.GRPC.AuthTokensFilePath())
+		if err != nil {
+			return nil, fmt.Errorf("loading gRPC auth tokens: %w", err)
+		}
+	}
+
+	rpcMetrics := rpcserver.NopMetrics()
+	if n.config.Instrumentation.Prometheus {
+		state, err := n.stateStore.Load()
+		if err != nil {
+			return nil, fmt.Errorf("loading state: %w", err)
+		}
+		rpcMetrics = rpcserver.PrometheusMetrics(n.config.Instrumentation.Namespace, "chain_id", state.ChainID)
+	}
+	// The limiter is shared by the listeners, so that a client is limited
+	// over all of them.
+	limiter := rpcserver.NewLimiter(rpcserver.LimiterConfig{
+		RequestsPerSecond:  n.config.RPC.RateLimitPerClient,
+		Burst:              n.config.RPC.RateLimitBurst,
+		MaxConcurrentCalls: n.config.RPC.MaxConcurrentExpensiveCalls,
+		ExpensiveRoutes:    n.config.RPC.ExpensiveRoutes,
+	}, rpcMetrics)
+
 	// we may expose the rpc over both a unix and tcp socket
 	listeners := make([]net.Listener, 0, len(listenAddrs))
 	for _, listenAddr := range listenAddrs {
@@ -872,11 +913,12 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 			}),
 			rpcserver.ReadLimit(config.MaxBodyBytes),
 			rpcserver.WriteChanCapacity(n.config.RPC.WebSocketWriteBufferSize),
+			rpcserver.RequestLimiter(limiter),
 		)
 		wm.SetLogger(wmLogger)
 		mux.HandleFunc("/websocket", wm.WebsocketHandler)
 		mux.HandleFunc("/v1/websocket", wm.WebsocketHandler)
-		rpcserver.RegisterRPCFuncs(mux, routes, rpcLogger)
+		rpcserver.RegisterRPCFuncsWithLimiter(mux, routes, rpcLogger, limiter)
 		listener, err := rpcserver.Listen(
 			listenAddr,
 			config.MaxOpenConnections,
@@ -886,13 +928,18 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 		}
 
 		var rootHandler http.Handler = mux
+		if rpcAuthenticator != nil {
+			rootHandler = rpcserver.AuthHandler(rpcAuthenticator, rootHandler, rpcLogger)
+		}
+		// CORS preflight requests, which carry no credentials, are handled
+		// before authentication.
 		if n.config.RPC.IsCorsEnabled() {
 			corsMiddleware := cors.New(cors.Options{
 				AllowedOrigins: n.config.RPC.CORSAllowedOrigins,
 				AllowedMethods: n.config.RPC.CORSAllowedMethods,
 				AllowedHeaders: n.config.RPC.CORSAllowedHeaders,
 			})
-			rootHandler = corsMiddleware.Handler(mux)
+			rootHandler = corsMiddleware.Handler(rootHandler)
 		}
 		if n.config.RPC.IsTLSEnabled() {
 			go func() {
@@ -935,6 +982,9 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 		opts := []grpcserver.Option{
 			grpcserver.WithLogger(n.Logger),
 		}
+		if grpcAuthenticator != nil {
+			opts = append(opts, grpcserver.WithAuthenticator(grpcAuthenticator))
+		}
 		if n.config.GRPC.VersionService.Enabled {
 			opts = append(opts, grpcserver.WithVersionService())
 		}
@@ -944,6 +994,9 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 		if n.config.GRPC.BlockResultsService.Enabled {
 			opts = append(opts, grpcserver.WithBlockResultsService(n.blockStore, n.stateStore, n.Logger))
 		}
+		if n.config.GRPC.LightBlockService.Enabled {
+			opts = append(opts, grpcserver.WithLightBlockService(n.blockStore, n.stateStore, n.Logger))
+		}
 		go func() {
 			if err := grpcserver.Serve(listener, opts...); err != nil {
 				n.Logger.Error("Error starting gRPC server", "err", err)
@@ -960,6 +1013,9 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 		opts := []grpcprivserver.Option{
 			grpcprivserver.WithLogger(n.Logger),
 		}
+		if grpcAuthenticator != nil {
+			opts = append(opts, grpcprivserver.WithAuthenticator(grpcAuthenticator))
+		}
 		if n.config.GRPC.Privileged.PruningService.Enabled {
 			opts = append(opts, grpcprivserver.WithPruningService(n.pruner, n.Logger))
 		}
@@ -1167,6 +1223,7 @@ func createPruner(
 	config *cfg.Config,
 	txIndexer txindex.TxIndexer,
 	blockIndexer indexer.BlockIndexer,
+	indexerSinks []txindex.Sink,
 	stateStore sm.Store,
 	blockStore *store.BlockStore,
 	metrics *sm.Metrics,
@@ -1193,6 +1250,20 @@ func createPruner(
 		prunerOpts = append(prunerOpts, sm.WithPrunerCompanionEnabled())
 	}
 
+	if pruningCfg := config.Storage.Pruning; pruningCfg.BlocksRetainTime > 0 ||
+		pruningCfg.ABCIResultsRetainTime > 0 || pruningCfg.IndexerRetainTime > 0 {
+		prunerOpts = append(prunerOpts, sm.WithPrunerRetainTime(
+			pruningCfg.BlocksRetainTime, pruningCfg.ABCIResultsRetainTime, pruningCfg.IndexerRetainTime))
+	}
+
+	if coldCfg := config.Storage.ColdStorage; coldCfg.Enabled {
+		prunerOpts = append(prunerOpts, sm.WithPrunerColdStorage(blockStore, coldCfg.AfterBlocks, coldCfg.AfterTime))
+	}
+
+	if len(indexerSinks) > 0 {
+		prunerOpts = append(prunerOpts, sm.WithPrunerIndexerSinks(indexerSinks...))
+	}
+
 	return sm.NewPruner(stateStore, blockStore, blockIndexer, txIndexer, logger, prunerOpts...), nil
 }
 
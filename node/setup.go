@@ -23,7 +23,8 @@ import (
 	cs "github.com/cometbft/cometbft/v2/internal/consensus"
 	"github.com/cometbft/cometbft/v2/internal/evidence"
 	"github.com/cometbft/cometbft/v2/libs/log"
-	"github.com/cometbft/cometbft/v2/light"
+	lightprovider "github.com/cometbft/cometbft/v2/light/provider"
+	lighthttp "github.com/cometbft/cometbft/v2/light/provider/http"
 	mempl "github.com/cometbft/cometbft/v2/mempool"
 	"github.com/cometbft/cometbft/v2/p2p"
 	na "github.com/cometbft/cometbft/v2/p2p/netaddr"
@@ -36,7 +37,9 @@ import (
 	sm "github.com/cometbft/cometbft/v2/state"
 	"github.com/cometbft/cometbft/v2/state/indexer"
 	"github.com/cometbft/cometbft/v2/state/indexer/block"
+	blockidxnull "github.com/cometbft/cometbft/v2/state/indexer/block/null"
 	"github.com/cometbft/cometbft/v2/state/txindex"
+	"github.com/cometbft/cometbft/v2/state/txindex/null"
 	"github.com/cometbft/cometbft/v2/statesync"
 	"github.com/cometbft/cometbft/v2/store"
 	"github.com/cometbft/cometbft/v2/types"
@@ -199,25 +202,29 @@ func createAndStartIndexerService(
 	chainID string,
 	dbProvider cfg.DBProvider,
 	eventBus *types.EventBus,
+	blockStore sm.BlockStore,
+	stateStore sm.Store,
 	logger log.Logger,
 ) (*txindex.IndexerService, txindex.TxIndexer, indexer.BlockIndexer, error) {
-	var (
-		txIndexer    txindex.TxIndexer
-		blockIndexer indexer.BlockIndexer
-	)
-
-	txIndexer, blockIndexer, allIndexersDisabled, err := block.IndexerFromConfig(config, dbProvider, chainID)
+	sinks, err := block.SinksFromConfig(config, dbProvider, chainID)
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	if allIndexersDisabled {
-		return nil, txIndexer, blockIndexer, nil
+	if len(sinks) == 0 {
+		return nil, &null.TxIndex{}, &blockidxnull.BlockerIndexer{}, nil
 	}
 
-	txIndexer.SetLogger(logger.With("module", "txindex"))
-	blockIndexer.SetLogger(logger.With("module", "txindex"))
+	for _, s := range sinks {
+		s.TxIndexer.SetLogger(logger.With("module", "txindex", "sink", s.Name))
+		s.BlockIndexer.SetLogger(logger.With("module", "txindex", "sink", s.Name))
+	}
 
-	indexerService := txindex.NewIndexerService(txIndexer, blockIndexer, eventBus, false)
+	txIndexer, blockIndexer := sinks[0].TxIndexer, sinks[0].BlockIndexer
+	indexerService := txindex.NewIndexerService(txIndexer, blockIndexer, eventBus, false,
+		txindex.WithSinks(sinks[1:]...),
+		txindex.WithBlockEventsLoader(func(height int64) (types.EventDataNewBlockEvents, *txindex.Batch, error) {
+			return txindex.LoadBlockEvents(blockStore, stateStore, height)
+		}, blockStore.Height))
 	indexerService.SetLogger(logger.With("module", "txindex"))
 	if err := indexerService.Start(); err != nil {
 		return nil, nil, nil, err
@@ -360,9 +367,28 @@ func createBlocksyncReactor(config *cfg.Config,
 	metrics *blocksync.Metrics,
 	offlineStateSyncHeight int64,
 ) (bcReactor p2p.Reactor, err error) {
+	options := []blocksync.ReactorOption{
+		blocksync.WithVerifyWindow(config.BlockSync.VerifyWindow),
+		blocksync.WithServeLimits(config.BlockSync.ServeMaxConcurrent,
+			config.BlockSync.ServeMaxConcurrentPerPeer, config.BlockSync.ServeWindow),
+	}
+	if config.BlockSync.HeaderSync {
+		providers := make([]lightprovider.Provider, 0, len(config.BlockSync.HeaderSyncRPCServers))
+		for _, server := range config.BlockSync.HeaderSyncRPCServers {
+			provider, err := lighthttp.New(state.ChainID, server)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set up light block provider %s: %w", server, err)
+			}
+			providers = append(providers, provider)
+		}
+		options = append(options, blocksync.WithHeaderSync(providers,
+			config.BlockSync.HeaderSyncTrustPeriod, config.BlockSync.HeaderSyncPivotInterval))
+	}
+
 	switch config.BlockSync.Version {
 	case "v0":
-		bcReactor = blocksync.NewReactor(state.Copy(), blockExec, blockStore, blockSync, localAddr, metrics, offlineStateSyncHeight)
+		bcReactor = blocksync.NewReactor(state.Copy(), blockExec, blockStore, blockSync, localAddr, metrics, offlineStateSyncHeight,
+			options...)
 	case "v1", "v2":
 		return nil, fmt.Errorf("block sync version %s has been deprecated. Please use v0", config.BlockSync.Version)
 	default:
@@ -581,14 +607,10 @@ func startStateSync(
 		var err error
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-		stateProvider, err = statesync.NewLightClientStateProviderWithDBKeyVersion(
-			ctx,
+		stateProvider, err = statesync.NewStateProviderFromConfig(
+			ctx, config,
 			state.ChainID, state.Version, state.InitialHeight,
-			config.RPCServers, light.TrustOptions{
-				Period: config.TrustPeriod,
-				Height: config.TrustHeight,
-				Hash:   config.TrustHashBytes(),
-			}, ssR.Logger.With("module", "light"),
+			ssR.Logger.With("module", "light"),
 			dbKeyLayoutVersion)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to set up light client state provider: %w", err)
@@ -618,11 +640,52 @@ func startStateSync(
 		}
 
 		stateCh <- newState
+
+		// The node proceeds with block sync while the heights below the
+		// snapshot are backfilled.
+		if config.BackfillHeight > 0 {
+			backfill(ssR, stateProvider, config, stateStore, blockStore, newState, commit)
+		}
 	}()
 
 	return stateCh, errc, nil
 }
 
+// backfill fetches and verifies the headers and commits, and optionally the
+// blocks, below the state sync snapshot down to the configured height. A failed
+// backfill is logged but does not prevent the node from proceeding.
+func backfill(
+	ssR *statesync.Reactor,
+	stateProvider statesync.StateProvider,
+	config *cfg.StateSyncConfig,
+	stateStore sm.Store,
+	blockStore *store.BlockStore,
+	state sm.State,
+	commit *types.Commit,
+) {
+	provider, ok := stateProvider.(statesync.BackfillProvider)
+	if !ok {
+		ssR.Logger.Error("State provider does not support backfilling blocks")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-ssR.Quit():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	stopHeight := max(config.BackfillHeight, state.InitialHeight)
+	if err := statesync.Backfill(ctx, provider, blockStore, stateStore, state.ChainID, commit,
+		stopHeight, config.BackfillBlocks, ssR.Logger.With("module", "backfill")); err != nil {
+		ssR.Logger.Error("Failed to backfill blocks", "err", err)
+	}
+}
+
 // ------------------------------------------------------------------------------
 
 var (
@@ -0,0 +1,189 @@
+// Package auth implements the optional bearer token authentication of the
+// JSON-RPC and gRPC servers.
+//
+// Each token is allowed to call a set of routes, given as patterns in the
+// syntax of path.Match, e.g. "block*" or "broadcast_tx_*". As "*" does not
+// match the "/" separating the service and method of gRPC routes, a bare "*"
+// is handled separately and allows every route. The tokens are
+// either listed in the configuration, or JWTs signed with HMAC-SHA256 (HS256)
+// using the configured secret, which carry the patterns of the routes they
+// are allowed to call in their "routes" claim.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// Config is the authentication configuration, as read from the JSON file
+// referenced by the auth_tokens_file setting of the RPC and gRPC servers.
+type Config struct {
+	// The static bearer tokens.
+	Tokens []Token `json:"tokens"`
+	// Secret with which the accepted JWTs are signed. If empty, JWTs are not
+	// accepted.
+	JWTSecret string `json:"jwt_secret"`
+}
+
+// Token is a static bearer token, and the routes it is allowed to call.
+type Token struct {
+	// Name identifying the token in the logs and errors.
+	Name  string `json:"name"`
+	Token string `json:"token"`
+	// Patterns of the routes the token is allowed to call, "*" allowing all
+	// of them.
+	Routes []string `json:"routes"`
+}
+
+// LoadConfig reads the authentication configuration from the JSON file at
+// path.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("reading authentication config: %w", err)
+	}
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return cfg, ErrInvalidConfig{Source: err}
+	}
+	return cfg, nil
+}
+
+// Grant is the set of routes an authenticated token is allowed to call.
+type Grant struct {
+	// Name of the token.
+	Name   string
+	routes []string
+}
+
+// Allows returns whether the grant allows calling route.
+func (g *Grant) Allows(route string) bool {
+	for _, pattern := range g.routes {
+		if pattern == "*" {
+			return true
+		}
+		if ok, _ := path.Match(pattern, route); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize returns ErrRouteNotAllowed if the grant does not allow calling
+// route.
+func (g *Grant) Authorize(route string) error {
+	if !g.Allows(route) {
+		return ErrRouteNotAllowed{Name: g.Name, Route: route}
+	}
+	return nil
+}
+
+// Authenticator authenticates the bearer tokens of the requests.
+type Authenticator struct {
+	// The static tokens, indexed by the hash of their value, so that they are
+	// compared in constant time.
+	tokens    map[[sha256.Size]byte]*Grant
+	jwtSecret []byte
+}
+
+// NewAuthenticator returns an Authenticator accepting the tokens of cfg.
+func NewAuthenticator(cfg Config) (*Authenticator, error) {
+	a := &Authenticator{
+		tokens:    make(map[[sha256.Size]byte]*Grant, len(cfg.Tokens)),
+		jwtSecret: []byte(cfg.JWTSecret),
+	}
+	names := make(map[string]bool, len(cfg.Tokens))
+	for i, t := range cfg.Tokens {
+		switch {
+		case t.Name == "":
+			return nil, ErrInvalidConfig{Source: fmt.Errorf("token #%d has no name", i)}
+		case names[t.Name]:
+			return nil, ErrInvalidConfig{Source: fmt.Errorf("duplicate token name %q", t.Name)}
+		case t.Token == "":
+			return nil, ErrInvalidConfig{Source: fmt.Errorf("token %q is empty", t.Name)}
+		}
+		if err := validateRoutes(t.Routes); err != nil {
+			return nil, ErrInvalidConfig{Source: fmt.Errorf("token %q: %w", t.Name, err)}
+		}
+		hash := sha256.Sum256([]byte(t.Token))
+		if _, ok := a.tokens[hash]; ok {
+			return nil, ErrInvalidConfig{Source: fmt.Errorf("token %q is a duplicate", t.Name)}
+		}
+		names[t.Name] = true
+		a.tokens[hash] = &Grant{Name: t.Name, routes: t.Routes}
+	}
+	if len(a.tokens) == 0 && len(a.jwtSecret) == 0 {
+		return nil, ErrInvalidConfig{Source: errors.New("neither tokens nor a JWT secret are configured")}
+	}
+	return a, nil
+}
+
+// NewAuthenticatorFromFile returns an Authenticator accepting the tokens of the
+// configuration read from the JSON file at path.
+func NewAuthenticatorFromFile(path string) (*Authenticator, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewAuthenticator(cfg)
+}
+
+// Authenticate returns the grant of token, or ErrMissingToken or
+// ErrInvalidToken.
+func (a *Authenticator) Authenticate(token string) (*Grant, error) {
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+	hash := sha256.Sum256([]byte(token))
+	for h, g := range a.tokens {
+		if subtle.ConstantTimeCompare(h[:], hash[:]) == 1 {
+			return g, nil
+		}
+	}
+	if len(a.jwtSecret) > 0 && strings.Count(token, ".") == 2 {
+		return verifyJWT(token, a.jwtSecret)
+	}
+	return nil, ErrInvalidToken
+}
+
+// BearerToken returns the token of the value of an Authorization header, or
+// an empty string if it does not hold a bearer token.
+func BearerToken(authorization string) string {
+	const prefix = "Bearer "
+	if len(authorization) < len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(authorization[len(prefix):])
+}
+
+func validateRoutes(routes []string) error {
+	if len(routes) == 0 {
+		return errors.New("no routes allowed")
+	}
+	for _, pattern := range routes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid route pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+type grantKey struct{}
+
+// ContextWithGrant returns a copy of ctx carrying grant.
+func ContextWithGrant(ctx context.Context, grant *Grant) context.Context {
+	return context.WithValue(ctx, grantKey{}, grant)
+}
+
+// GrantFromContext returns the grant carried by ctx, if any.
+func GrantFromContext(ctx context.Context) (*Grant, bool) {
+	grant, ok := ctx.Value(grantKey{}).(*Grant)
+	return grant, ok
+}
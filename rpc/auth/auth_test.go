@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticatorTokens(t *testing.T) {
+	a, err := NewAuthenticator(Config{Tokens: []Token{
+		{Name: "explorer", Token: "secret1", Routes: []string{"status", "block*"}},
+		{Name: "wallet", Token: "secret2", Routes: []string{"broadcast_tx_*"}},
+	}})
+	require.NoError(t, err)
+
+	_, err = a.Authenticate("")
+	require.ErrorIs(t, err, ErrMissingToken)
+	_, err = a.Authenticate("secret3")
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	grant, err := a.Authenticate("secret1")
+	require.NoError(t, err)
+	assert.Equal(t, "explorer", grant.Name)
+	assert.True(t, grant.Allows("status"))
+	assert.True(t, grant.Allows("block_results"))
+	assert.False(t, grant.Allows("broadcast_tx_sync"))
+	require.ErrorAs(t, grant.Authorize("broadcast_tx_sync"), &ErrRouteNotAllowed{})
+
+	grant, err = a.Authenticate("secret2")
+	require.NoError(t, err)
+	assert.True(t, grant.Allows("broadcast_tx_async"))
+	assert.False(t, grant.Allows("unsafe_flush_mempool"))
+}
+
+func TestGrantAllowsGRPCRoutes(t *testing.T) {
+	grant := &Grant{Name: "a", routes: []string{"cometbft.services.block.v2.BlockService/*"}}
+	assert.True(t, grant.Allows("cometbft.services.block.v2.BlockService/GetByHeight"))
+	assert.False(t, grant.Allows("cometbft.services.version.v1.VersionService/GetVersion"))
+
+	// "*" does not match the "/" of gRPC routes, except as a bare pattern.
+	grant.routes = []string{"cometbft.services.*"}
+	assert.False(t, grant.Allows("cometbft.services.block.v2.BlockService/GetByHeight"))
+	grant.routes = []string{"*"}
+	assert.True(t, grant.Allows("cometbft.services.block.v2.BlockService/GetByHeight"))
+	assert.True(t, grant.Allows("status"))
+}
+
+func TestAuthenticatorInvalidConfig(t *testing.T) {
+	testCases := map[string]Config{
+		"empty":          {},
+		"no name":        {Tokens: []Token{{Token: "secret", Routes: []string{"*"}}}},
+		"no token":       {Tokens: []Token{{Name: "a", Routes: []string{"*"}}}},
+		"no routes":      {Tokens: []Token{{Name: "a", Token: "secret"}}},
+		"bad pattern":    {Tokens: []Token{{Name: "a", Token: "secret", Routes: []string{"["}}}},
+		"duplicate name": {Tokens: []Token{{Name: "a", Token: "s1", Routes: []string{"*"}}, {Name: "a", Token: "s2", Routes: []string{"*"}}}},
+		"duplicate token": {Tokens: []Token{
+			{Name: "a", Token: "secret", Routes: []string{"*"}},
+			{Name: "b", Token: "secret", Routes: []string{"*"}},
+		}},
+	}
+	for name, cfg := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewAuthenticator(cfg)
+			require.ErrorAs(t, err, &ErrInvalidConfig{})
+		})
+	}
+}
+
+func TestAuthenticatorFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"tokens": [{"name": "explorer", "token": "secret", "routes": ["*"]}]
+	}`), 0o600))
+
+	a, err := NewAuthenticatorFromFile(path)
+	require.NoError(t, err)
+	grant, err := a.Authenticate("secret")
+	require.NoError(t, err)
+	assert.True(t, grant.Allows("dial_seeds"))
+
+	_, err = NewAuthenticatorFromFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestAuthenticatorJWT(t *testing.T) {
+	secret := []byte("jwt-secret")
+	a, err := NewAuthenticator(Config{JWTSecret: string(secret)})
+	require.NoError(t, err)
+
+	t0 := time.Unix(1700000000, 0)
+	now = func() time.Time { return t0 }
+	t.Cleanup(func() { now = time.Now })
+
+	exp, nbf := t0.Add(time.Hour).Unix(), t0.Add(-time.Hour).Unix()
+	claims := jwtClaims{Subject: "indexer", ExpiresAt: &exp, NotBefore: &nbf, Routes: []string{"tx_search"}}
+
+	grant, err := a.Authenticate(makeJWT(t, "HS256", claims, secret))
+	require.NoError(t, err)
+	assert.Equal(t, "indexer", grant.Name)
+	assert.True(t, grant.Allows("tx_search"))
+	assert.False(t, grant.Allows("block_search"))
+
+	// Wrong secret.
+	_, err = a.Authenticate(makeJWT(t, "HS256", claims, []byte("other")))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// Unsupported algorithm.
+	_, err = a.Authenticate(makeJWT(t, "none", claims, secret))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// Expired.
+	now = func() time.Time { return t0.Add(2 * time.Hour) }
+	_, err = a.Authenticate(makeJWT(t, "HS256", claims, secret))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// Not valid yet.
+	now = func() time.Time { return t0.Add(-2 * time.Hour) }
+	_, err = a.Authenticate(makeJWT(t, "HS256", claims, secret))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// Without routes.
+	now = func() time.Time { return t0 }
+	claims.Routes = nil
+	_, err = a.Authenticate(makeJWT(t, "HS256", claims, secret))
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestBearerToken(t *testing.T) {
+	assert.Equal(t, "abc", BearerToken("Bearer abc"))
+	assert.Equal(t, "abc", BearerToken("bearer abc"))
+	assert.Equal(t, "", BearerToken("Basic abc"))
+	assert.Equal(t, "", BearerToken(""))
+}
+
+func makeJWT(t *testing.T, alg string, claims jwtClaims, secret []byte) string {
+	t.Helper()
+	header, err := json.Marshal(jwtHeader{Alg: alg, Typ: "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMissingToken is returned when a request carries no bearer token.
+	ErrMissingToken = errors.New("missing bearer token")
+
+	// ErrInvalidToken is returned when a bearer token is neither one of the
+	// configured tokens nor a valid JWT.
+	ErrInvalidToken = errors.New("invalid bearer token")
+)
+
+// ErrRouteNotAllowed is returned when a token is not allowed to call a route.
+type ErrRouteNotAllowed struct {
+	Name  string
+	Route string
+}
+
+func (e ErrRouteNotAllowed) Error() string {
+	return fmt.Sprintf("token %q is not allowed to call %s", e.Name, e.Route)
+}
+
+// ErrInvalidConfig is returned when the authentication configuration is
+// invalid.
+type ErrInvalidConfig struct {
+	Source error
+}
+
+func (e ErrInvalidConfig) Error() string {
+	return fmt.Sprintf("invalid authentication config: %v", e.Source)
+}
+
+func (e ErrInvalidConfig) Unwrap() error {
+	return e.Source
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// jwtHeader is the header of a JWT.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+// jwtClaims are the claims of a JWT the Authenticator uses.
+type jwtClaims struct {
+	Subject   string   `json:"sub"`
+	ExpiresAt *int64   `json:"exp"`
+	NotBefore *int64   `json:"nbf"`
+	Routes    []string `json:"routes"`
+}
+
+// now is time.Now, overridden in tests.
+var now = time.Now
+
+// verifyJWT verifies the HS256 signature of token with secret, and returns the
+// grant of its claims. The name of the grant is the subject of the token.
+func verifyJWT(token string, secret []byte) (*Grant, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, err
+	}
+	// Only HS256 is supported, which in particular rules out unsigned tokens.
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("%w: unsupported JWT algorithm %q", ErrInvalidToken, header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, ErrInvalidToken
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	t := now().Unix()
+	if claims.ExpiresAt != nil && t >= *claims.ExpiresAt {
+		return nil, fmt.Errorf("%w: JWT expired", ErrInvalidToken)
+	}
+	if claims.NotBefore != nil && t < *claims.NotBefore {
+		return nil, fmt.Errorf("%w: JWT not valid yet", ErrInvalidToken)
+	}
+	if err := validateRoutes(claims.Routes); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	return &Grant{Name: claims.Subject, routes: claims.Routes}, nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	bz, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	if err := json.Unmarshal(bz, v); err != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
@@ -517,6 +517,8 @@ func (c *baseRPCClient) TxSearch(
 	page,
 	perPage *int,
 	orderBy string,
+	cursor string,
+	limit *int,
 ) (*ctypes.ResultTxSearch, error) {
 	result := new(ctypes.ResultTxSearch)
 	params := map[string]any{
@@ -531,6 +533,12 @@ func (c *baseRPCClient) TxSearch(
 	if perPage != nil {
 		params["per_page"] = perPage
 	}
+	if cursor != "" {
+		params["cursor"] = cursor
+	}
+	if limit != nil {
+		params["limit"] = limit
+	}
 
 	_, err := c.caller.Call(ctx, "tx_search", params, result)
 	if err != nil {
@@ -545,6 +553,8 @@ func (c *baseRPCClient) BlockSearch(
 	query string,
 	page, perPage *int,
 	orderBy string,
+	cursor string,
+	limit *int,
 ) (*ctypes.ResultBlockSearch, error) {
 	result := new(ctypes.ResultBlockSearch)
 	params := map[string]any{
@@ -558,6 +568,12 @@ func (c *baseRPCClient) BlockSearch(
 	if perPage != nil {
 		params["per_page"] = perPage
 	}
+	if cursor != "" {
+		params["cursor"] = cursor
+	}
+	if limit != nil {
+		params["limit"] = limit
+	}
 
 	_, err := c.caller.Call(ctx, "block_search", params, result)
 	if err != nil {
@@ -567,6 +583,31 @@ func (c *baseRPCClient) BlockSearch(
 	return result, nil
 }
 
+func (c *baseRPCClient) TxCount(ctx context.Context, query string, bucketSize *int64) (*ctypes.ResultCount, error) {
+	return c.count(ctx, "tx_count", query, bucketSize)
+}
+
+func (c *baseRPCClient) BlockCount(ctx context.Context, query string, bucketSize *int64) (*ctypes.ResultCount, error) {
+	return c.count(ctx, "block_count", query, bucketSize)
+}
+
+func (c *baseRPCClient) count(ctx context.Context, method, query string, bucketSize *int64) (*ctypes.ResultCount, error) {
+	result := new(ctypes.ResultCount)
+	params := map[string]any{
+		"query": query,
+	}
+	if bucketSize != nil {
+		params["bucket_size"] = bucketSize
+	}
+
+	_, err := c.caller.Call(ctx, method, params, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func (c *baseRPCClient) Validators(
 	ctx context.Context,
 	height *int64,
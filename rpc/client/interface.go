@@ -74,23 +74,39 @@ type SignClient interface {
 	Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.ResultTx, error)
 
 	// TxSearch defines a method to search for a paginated set of transactions by
-	// transaction event search criteria.
+	// transaction event search criteria. If cursor is not empty or limit is not
+	// nil, at most limit transactions following cursor are returned instead of
+	// a page, along with the cursor of the next ones.
 	TxSearch(
 		ctx context.Context,
 		query string,
 		prove bool,
 		page, perPage *int,
 		orderBy string,
+		cursor string,
+		limit *int,
 	) (*ctypes.ResultTxSearch, error)
 
 	// BlockSearch defines a method to search for a paginated set of blocks based
-	// from FinalizeBlock event search criteria.
+	// from FinalizeBlock event search criteria. If cursor is not empty or limit
+	// is not nil, at most limit blocks following cursor are returned instead of
+	// a page, along with the cursor of the next ones.
 	BlockSearch(
 		ctx context.Context,
 		query string,
 		page, perPage *int,
 		orderBy string,
+		cursor string,
+		limit *int,
 	) (*ctypes.ResultBlockSearch, error)
+
+	// TxCount defines a method to count the transactions matching transaction
+	// event search criteria, optionally per range of bucketSize heights.
+	TxCount(ctx context.Context, query string, bucketSize *int64) (*ctypes.ResultCount, error)
+
+	// BlockCount defines a method to count the blocks matching FinalizeBlock
+	// event search criteria, optionally per range of bucketSize heights.
+	BlockCount(ctx context.Context, query string, bucketSize *int64) (*ctypes.ResultCount, error)
 }
 
 // HistoryClient provides access to data from genesis to now in large chunks.
@@ -211,8 +211,10 @@ func (c *Local) TxSearch(
 	page,
 	perPage *int,
 	orderBy string,
+	cursor string,
+	limit *int,
 ) (*ctypes.ResultTxSearch, error) {
-	return c.env.TxSearch(c.ctx, query, prove, page, perPage, orderBy)
+	return c.env.TxSearch(c.ctx, query, prove, page, perPage, orderBy, cursor, limit)
 }
 
 func (c *Local) BlockSearch(
@@ -220,8 +222,18 @@ func (c *Local) BlockSearch(
 	query string,
 	page, perPage *int,
 	orderBy string,
+	cursor string,
+	limit *int,
 ) (*ctypes.ResultBlockSearch, error) {
-	return c.env.BlockSearch(c.ctx, query, page, perPage, orderBy)
+	return c.env.BlockSearch(c.ctx, query, page, perPage, orderBy, cursor, limit)
+}
+
+func (c *Local) TxCount(_ context.Context, query string, bucketSize *int64) (*ctypes.ResultCount, error) {
+	return c.env.TxCount(c.ctx, query, bucketSize)
+}
+
+func (c *Local) BlockCount(_ context.Context, query string, bucketSize *int64) (*ctypes.ResultCount, error) {
+	return c.env.BlockCount(c.ctx, query, bucketSize)
 }
 
 func (c *Local) BroadcastEvidence(_ context.Context, ev types.Evidence) (*ctypes.ResultBroadcastEvidence, error) {
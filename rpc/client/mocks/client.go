@@ -137,6 +137,29 @@ func (_m *Client) BlockByHash(ctx context.Context, hash []byte) (*coretypes.Resu
 	return r0, r1
 }
 
+// BlockCount provides a mock function with given fields: ctx, query, bucketSize
+func (_m *Client) BlockCount(ctx context.Context, query string, bucketSize *int64) (*coretypes.ResultCount, error) {
+	ret := _m.Called(ctx, query, bucketSize)
+
+	var r0 *coretypes.ResultCount
+	if rf, ok := ret.Get(0).(func(context.Context, string, *int64) *coretypes.ResultCount); ok {
+		r0 = rf(ctx, query, bucketSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.ResultCount)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *int64) error); ok {
+		r1 = rf(ctx, query, bucketSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BlockResults provides a mock function with given fields: ctx, height
 func (_m *Client) BlockResults(ctx context.Context, height *int64) (*coretypes.ResultBlockResults, error) {
 	ret := _m.Called(ctx, height)
@@ -160,13 +183,13 @@ func (_m *Client) BlockResults(ctx context.Context, height *int64) (*coretypes.R
 	return r0, r1
 }
 
-// BlockSearch provides a mock function with given fields: ctx, query, page, perPage, orderBy
-func (_m *Client) BlockSearch(ctx context.Context, query string, page *int, perPage *int, orderBy string) (*coretypes.ResultBlockSearch, error) {
-	ret := _m.Called(ctx, query, page, perPage, orderBy)
+// BlockSearch provides a mock function with given fields: ctx, query, page, perPage, orderBy, cursor, limit
+func (_m *Client) BlockSearch(ctx context.Context, query string, page *int, perPage *int, orderBy string, cursor string, limit *int) (*coretypes.ResultBlockSearch, error) {
+	ret := _m.Called(ctx, query, page, perPage, orderBy, cursor, limit)
 
 	var r0 *coretypes.ResultBlockSearch
-	if rf, ok := ret.Get(0).(func(context.Context, string, *int, *int, string) *coretypes.ResultBlockSearch); ok {
-		r0 = rf(ctx, query, page, perPage, orderBy)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *int, *int, string, string, *int) *coretypes.ResultBlockSearch); ok {
+		r0 = rf(ctx, query, page, perPage, orderBy, cursor, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*coretypes.ResultBlockSearch)
@@ -174,8 +197,8 @@ func (_m *Client) BlockSearch(ctx context.Context, query string, page *int, perP
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, *int, *int, string) error); ok {
-		r1 = rf(ctx, query, page, perPage, orderBy)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *int, *int, string, string, *int) error); ok {
+		r1 = rf(ctx, query, page, perPage, orderBy, cursor, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -774,13 +797,36 @@ func (_m *Client) Tx(ctx context.Context, hash []byte, prove bool) (*coretypes.R
 	return r0, r1
 }
 
-// TxSearch provides a mock function with given fields: ctx, query, prove, page, perPage, orderBy
-func (_m *Client) TxSearch(ctx context.Context, query string, prove bool, page *int, perPage *int, orderBy string) (*coretypes.ResultTxSearch, error) {
-	ret := _m.Called(ctx, query, prove, page, perPage, orderBy)
+// TxCount provides a mock function with given fields: ctx, query, bucketSize
+func (_m *Client) TxCount(ctx context.Context, query string, bucketSize *int64) (*coretypes.ResultCount, error) {
+	ret := _m.Called(ctx, query, bucketSize)
+
+	var r0 *coretypes.ResultCount
+	if rf, ok := ret.Get(0).(func(context.Context, string, *int64) *coretypes.ResultCount); ok {
+		r0 = rf(ctx, query, bucketSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.ResultCount)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *int64) error); ok {
+		r1 = rf(ctx, query, bucketSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TxSearch provides a mock function with given fields: ctx, query, prove, page, perPage, orderBy, cursor, limit
+func (_m *Client) TxSearch(ctx context.Context, query string, prove bool, page *int, perPage *int, orderBy string, cursor string, limit *int) (*coretypes.ResultTxSearch, error) {
+	ret := _m.Called(ctx, query, prove, page, perPage, orderBy, cursor, limit)
 
 	var r0 *coretypes.ResultTxSearch
-	if rf, ok := ret.Get(0).(func(context.Context, string, bool, *int, *int, string) *coretypes.ResultTxSearch); ok {
-		r0 = rf(ctx, query, prove, page, perPage, orderBy)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, *int, *int, string, string, *int) *coretypes.ResultTxSearch); ok {
+		r0 = rf(ctx, query, prove, page, perPage, orderBy, cursor, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*coretypes.ResultTxSearch)
@@ -788,8 +834,8 @@ func (_m *Client) TxSearch(ctx context.Context, query string, prove bool, page *
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, bool, *int, *int, string) error); ok {
-		r1 = rf(ctx, query, prove, page, perPage, orderBy)
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, *int, *int, string, string, *int) error); ok {
+		r1 = rf(ctx, query, prove, page, perPage, orderBy, cursor, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
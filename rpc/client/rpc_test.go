@@ -533,7 +533,7 @@ func TestTxSearchWithTimeout(t *testing.T) {
 	require.NoError(t, err)
 
 	// query using a compositeKey (see kvstore application)
-	result, err := timeoutClient.TxSearch(context.Background(), "app.creator='Cosmoshi Netowoko'", false, nil, nil, "asc")
+	result, err := timeoutClient.TxSearch(context.Background(), "app.creator='Cosmoshi Netowoko'", false, nil, nil, "asc", "", nil)
 	require.NoError(t, err)
 	require.NotEmpty(t, result.Txs, "expected a lot of transactions")
 }
@@ -551,7 +551,7 @@ func TestBlockSearch(t *testing.T) {
 		require.NoError(t, err)
 	}
 	require.NoError(t, client.WaitForHeight(c, 5, nil))
-	result, err := c.BlockSearch(context.Background(), "begin_event.foo = 100", nil, nil, "asc")
+	result, err := c.BlockSearch(context.Background(), "begin_event.foo = 100", nil, nil, "asc", "", nil)
 	require.NoError(t, err)
 	blockCount := len(result.Blocks)
 	// if we generate block events within the test (by uncommenting
@@ -575,9 +575,9 @@ func TestTxSearch(t *testing.T) {
 
 	// since we're not using an isolated test server, we'll have lingering transactions
 	// from other tests as well
-	result, err := c.TxSearch(context.Background(), "tx.height >= 0", true, nil, nil, "asc")
+	result, err := c.TxSearch(context.Background(), "tx.height >= 0", true, nil, nil, "asc", "", nil)
 	require.NoError(t, err)
-	txCount := len(result.Txs)
+	txCount := result.TotalCount
 
 	// pick out the last tx to have something to search for in tests
 	find := result.Txs[len(result.Txs)-1]
@@ -585,7 +585,7 @@ func TestTxSearch(t *testing.T) {
 
 	for _, c := range GetClients() {
 		// now we query for the tx.
-		result, err := c.TxSearch(context.Background(), fmt.Sprintf("tx.hash='%v'", find.Hash), true, nil, nil, "asc")
+		result, err := c.TxSearch(context.Background(), fmt.Sprintf("tx.hash='%v'", find.Hash), true, nil, nil, "asc", "", nil)
 		require.NoError(t, err)
 		require.Len(t, result.Txs, 1)
 		require.Equal(t, find.Hash, result.Txs[0].Hash)
@@ -603,51 +603,51 @@ func TestTxSearch(t *testing.T) {
 		}
 
 		// query by height
-		result, err = c.TxSearch(context.Background(), fmt.Sprintf("tx.height=%d", find.Height), true, nil, nil, "asc")
+		result, err = c.TxSearch(context.Background(), fmt.Sprintf("tx.height=%d", find.Height), true, nil, nil, "asc", "", nil)
 		require.NoError(t, err)
 		require.Len(t, result.Txs, 1)
 
 		// query for non existing tx
-		result, err = c.TxSearch(context.Background(), fmt.Sprintf("tx.hash='%X'", anotherTxHash), false, nil, nil, "asc")
+		result, err = c.TxSearch(context.Background(), fmt.Sprintf("tx.hash='%X'", anotherTxHash), false, nil, nil, "asc", "", nil)
 		require.NoError(t, err)
 		require.Empty(t, result.Txs)
 
 		// query using a compositeKey (see kvstore application)
-		result, err = c.TxSearch(context.Background(), "app.creator='Cosmoshi Netowoko'", false, nil, nil, "asc")
+		result, err = c.TxSearch(context.Background(), "app.creator='Cosmoshi Netowoko'", false, nil, nil, "asc", "", nil)
 		require.NoError(t, err)
 		require.NotEmpty(t, result.Txs, "expected a lot of transactions")
 
 		// query using an index key
-		result, err = c.TxSearch(context.Background(), "app.index_key='index is working'", false, nil, nil, "asc")
+		result, err = c.TxSearch(context.Background(), "app.index_key='index is working'", false, nil, nil, "asc", "", nil)
 		require.NoError(t, err)
 		require.NotEmpty(t, len(result.Txs), "expected a lot of transactions")
 
 		// query using an noindex key
-		result, err = c.TxSearch(context.Background(), "app.noindex_key='index is working'", false, nil, nil, "asc")
+		result, err = c.TxSearch(context.Background(), "app.noindex_key='index is working'", false, nil, nil, "asc", "", nil)
 		require.NoError(t, err)
 		require.Empty(t, result.Txs)
 
 		// query using a compositeKey (see kvstore application) and height
 		result, err = c.TxSearch(context.Background(),
-			"app.creator='Cosmoshi Netowoko' AND tx.height<10000", true, nil, nil, "asc")
+			"app.creator='Cosmoshi Netowoko' AND tx.height<10000", true, nil, nil, "asc", "", nil)
 		require.NoError(t, err)
 		require.NotEmpty(t, result.Txs, "expected a lot of transactions")
 
 		// query a non existing tx with page 1 and txsPerPage 1
 		perPage := 1
-		result, err = c.TxSearch(context.Background(), "app.creator='Cosmoshi Neetowoko'", true, nil, &perPage, "asc")
+		result, err = c.TxSearch(context.Background(), "app.creator='Cosmoshi Neetowoko'", true, nil, &perPage, "asc", "", nil)
 		require.NoError(t, err)
 		require.Empty(t, result.Txs)
 
 		// check sorting
-		result, err = c.TxSearch(context.Background(), "tx.height >= 1", false, nil, nil, "asc")
+		result, err = c.TxSearch(context.Background(), "tx.height >= 1", false, nil, nil, "asc", "", nil)
 		require.NoError(t, err)
 		for k := 0; k < len(result.Txs)-1; k++ {
 			require.LessOrEqual(t, result.Txs[k].Height, result.Txs[k+1].Height)
 			require.LessOrEqual(t, result.Txs[k].Index, result.Txs[k+1].Index)
 		}
 
-		result, err = c.TxSearch(context.Background(), "tx.height >= 1", false, nil, nil, "desc")
+		result, err = c.TxSearch(context.Background(), "tx.height >= 1", false, nil, nil, "desc", "", nil)
 		require.NoError(t, err)
 		for k := 0; k < len(result.Txs)-1; k++ {
 			require.GreaterOrEqual(t, result.Txs[k].Height, result.Txs[k+1].Height)
@@ -663,7 +663,7 @@ func TestTxSearch(t *testing.T) {
 
 		totalTx := 0
 		for page := 1; page <= pages; page++ {
-			result, err := c.TxSearch(context.Background(), "tx.height >= 1", true, &page, &perPage, "asc")
+			result, err := c.TxSearch(context.Background(), "tx.height >= 1", true, &page, &perPage, "asc", "", nil)
 			require.NoError(t, err)
 			if page < pages {
 				require.Len(t, result.Txs, perPage)
@@ -682,6 +682,60 @@ func TestTxSearch(t *testing.T) {
 		}
 		require.Equal(t, txCount, totalTx)
 		require.Len(t, seen, txCount)
+
+		// check cursor-based pagination
+		var (
+			cursor  string
+			limit   = 3
+			resumed = 0
+		)
+		for {
+			result, err := c.TxSearch(context.Background(), "tx.height >= 1", false, nil, nil, "asc", cursor, &limit)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(result.Txs), limit)
+			resumed += len(result.Txs)
+			if result.NextCursor == "" {
+				break
+			}
+			cursor = result.NextCursor
+		}
+		require.Equal(t, txCount, resumed)
+	}
+}
+
+func TestTxCount(t *testing.T) {
+	c := getHTTPClient()
+
+	// first we broadcast a few txs
+	for i := 0; i < 3; i++ {
+		_, _, tx := MakeTxKV()
+		_, err := c.BroadcastTxCommit(context.Background(), tx)
+		require.NoError(t, err)
+	}
+
+	search, err := c.TxSearch(context.Background(), "tx.height >= 1", false, nil, nil, "asc", "", nil)
+	require.NoError(t, err)
+
+	bucketSize := int64(2)
+	for _, c := range GetClients() {
+		result, err := c.TxCount(context.Background(), "tx.height >= 1", nil)
+		require.NoError(t, err)
+		require.Equal(t, search.TotalCount, result.TotalCount)
+		require.Empty(t, result.Buckets)
+
+		result, err = c.TxCount(context.Background(), "tx.height >= 1", &bucketSize)
+		require.NoError(t, err)
+		require.Equal(t, search.TotalCount, result.TotalCount)
+		total := 0
+		for _, b := range result.Buckets {
+			require.Equal(t, b.FromHeight+bucketSize-1, b.ToHeight)
+			total += b.Count
+		}
+		require.Equal(t, result.TotalCount, total)
+
+		result, err = c.BlockCount(context.Background(), "begin_event.foo = 100", nil)
+		require.NoError(t, err)
+		require.Zero(t, result.TotalCount)
 	}
 }
 
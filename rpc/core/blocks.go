@@ -8,7 +8,9 @@ import (
 	cmtquery "github.com/cometbft/cometbft/v2/libs/pubsub/query"
 	ctypes "github.com/cometbft/cometbft/v2/rpc/core/types"
 	rpctypes "github.com/cometbft/cometbft/v2/rpc/jsonrpc/types"
+	"github.com/cometbft/cometbft/v2/state/indexer"
 	blockidxnull "github.com/cometbft/cometbft/v2/state/indexer/block/null"
+	"github.com/cometbft/cometbft/v2/state/txindex"
 	"github.com/cometbft/cometbft/v2/types"
 )
 
@@ -190,12 +192,16 @@ func (env *Environment) BlockResults(_ *rpctypes.Context, heightPtr *int64) (*ct
 }
 
 // BlockSearch searches for a paginated set of blocks matching
-// FinalizeBlock event search criteria.
+// FinalizeBlock event search criteria. If a cursor or a limit is given, at
+// most ?limit blocks following the cursor are returned instead of a page,
+// with the cursor of the next ones.
 func (env *Environment) BlockSearch(
 	ctx *rpctypes.Context,
 	query string,
 	pagePtr, perPagePtr *int,
 	orderBy string,
+	cursor string,
+	limitPtr *int,
 ) (*ctypes.ResultBlockSearch, error) {
 	// skip if block indexing is disabled
 	if _, ok := env.BlockIndexer.(*blockidxnull.BlockerIndexer); ok {
@@ -207,34 +213,64 @@ func (env *Environment) BlockSearch(
 		return nil, err
 	}
 
-	results, err := env.BlockIndexer.Search(ctx.Context(), q)
-	if err != nil {
-		return nil, err
-	}
-
-	// sort results (must be done before pagination)
+	var desc bool
 	switch orderBy {
 	case Descending, "":
-		sort.Slice(results, func(i, j int) bool { return results[i] > results[j] })
-
+		desc = true
 	case Ascending:
-		sort.Slice(results, func(i, j int) bool { return results[i] < results[j] })
-
 	default:
 		return nil, ErrInvalidOrderBy{orderBy}
 	}
 
-	// paginate results
-	totalCount := len(results)
-	perPage := env.validatePerPage(perPagePtr)
+	// when resuming from a cursor, only the heights from the cursor on are
+	// searched
+	var from *txindex.Cursor
+	if cursor != "" {
+		from, err = decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		q, err = indexer.QueryFromHeight(q, types.BlockHeightKey, from.Height, desc)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	page, err := validatePage(pagePtr, perPage, totalCount)
+	results, err := env.BlockIndexer.Search(ctx.Context(), q)
 	if err != nil {
 		return nil, err
 	}
 
-	skipCount := validateSkipCount(page, perPage)
-	pageSize := cmtmath.MinInt(perPage, totalCount-skipCount)
+	// sort results (must be done before pagination)
+	if desc {
+		sort.Slice(results, func(i, j int) bool { return results[i] > results[j] })
+	} else {
+		sort.Slice(results, func(i, j int) bool { return results[i] < results[j] })
+	}
+
+	var skipCount, pageSize int
+	if cursor != "" || limitPtr != nil {
+		// skip the results up to the cursor
+		if from != nil {
+			first := sort.Search(len(results), func(i int) bool {
+				return from.After(results[i], 0, desc)
+			})
+			results = results[first:]
+		}
+		pageSize = cmtmath.MinInt(env.validatePerPage(limitPtr), len(results))
+	} else {
+		// paginate results
+		perPage := env.validatePerPage(perPagePtr)
+
+		page, err := validatePage(pagePtr, perPage, len(results))
+		if err != nil {
+			return nil, err
+		}
+
+		skipCount = validateSkipCount(page, perPage)
+		pageSize = cmtmath.MinInt(perPage, len(results)-skipCount)
+	}
+	totalCount := len(results)
 
 	apiResults := make([]*ctypes.ResultBlock, 0, pageSize)
 	for i := skipCount; i < skipCount+pageSize; i++ {
@@ -247,5 +283,43 @@ func (env *Environment) BlockSearch(
 		}
 	}
 
-	return &ctypes.ResultBlockSearch{Blocks: apiResults, TotalCount: totalCount}, nil
+	result := &ctypes.ResultBlockSearch{Blocks: apiResults, TotalCount: totalCount}
+	if (cursor != "" || limitPtr != nil) && pageSize > 0 && pageSize < totalCount {
+		result.NextCursor = encodeCursor(results[pageSize-1], 0)
+	}
+	return result, nil
+}
+
+// BlockCount returns the number of blocks matching FinalizeBlock event search
+// criteria and, if ?bucket_size is given, their number in each range of
+// bucket_size heights, without loading the blocks. It fails if the blocks
+// fall into more than maxCountBuckets ranges.
+// More: https://docs.cometbft.com/main/rpc/#/Info/block_count
+func (env *Environment) BlockCount(
+	ctx *rpctypes.Context,
+	query string,
+	bucketSizePtr *int64,
+) (*ctypes.ResultCount, error) {
+	// skip if block indexing is disabled
+	if _, ok := env.BlockIndexer.(*blockidxnull.BlockerIndexer); ok {
+		return nil, ErrBlockIndexing
+	} else if len(query) > maxQueryLength {
+		return nil, ErrQueryLength{len(query), maxQueryLength}
+	}
+
+	bucketSize, err := validateBucketSize(bucketSizePtr)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := cmtquery.New(query)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount, buckets, err := env.BlockIndexer.Count(ctx.Context(), q, bucketSize)
+	if err != nil {
+		return nil, err
+	}
+	return newResultCount(totalCount, buckets)
 }
@@ -30,8 +30,9 @@ Endpoints that require arguments:
 /abci_query?path=_&data=_&height=_&prove=_
 /block?height=_
 /block_by_hash?hash=_
+/block_count?query=_&bucket_size=_
 /block_results?height=_
-/block_search?query=_&page=_&per_page=_&order_by=_
+/block_search?query=_&page=_&per_page=_&order_by=_&cursor=_&limit=_
 /blockchain?minHeight=_&maxHeight=_
 /broadcast_evidence?evidence=_
 /broadcast_tx_async?tx=_
@@ -46,7 +47,8 @@ Endpoints that require arguments:
 /header_by_hash?hash=_
 /subscribe?query=_
 /tx?hash=_&prove=_
-/tx_search?query=_&prove=_&page=_&per_page=_&order_by=_
+/tx_count?query=_&bucket_size=_
+/tx_search?query=_&prove=_&page=_&per_page=_&order_by=_&cursor=_&limit=_
 /unconfirmed_txs?limit=_
 /unsubscribe?query=_
 /validators?height=_&page=_&per_page=_
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -8,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	abcicli "github.com/cometbft/cometbft/v2/abci/client"
@@ -17,6 +19,7 @@ import (
 	mempl "github.com/cometbft/cometbft/v2/mempool"
 	"github.com/cometbft/cometbft/v2/p2p"
 	"github.com/cometbft/cometbft/v2/proxy"
+	ctypes "github.com/cometbft/cometbft/v2/rpc/core/types"
 	sm "github.com/cometbft/cometbft/v2/state"
 	"github.com/cometbft/cometbft/v2/state/indexer"
 	"github.com/cometbft/cometbft/v2/state/txindex"
@@ -28,6 +31,9 @@ const (
 	defaultPerPage = 30
 	maxPerPage     = 100
 
+	// maxCountBuckets is the maximum number of buckets returned by a count.
+	maxCountBuckets = 1000
+
 	// SubscribeTimeout is the maximum time we wait to subscribe for an event.
 	// must be less than the server's write timeout (see rpcserver.DefaultConfig).
 	SubscribeTimeout = 5 * time.Second
@@ -232,6 +238,61 @@ func validateSkipCount(page, perPage int) int {
 	return skipCount
 }
 
+func validateBucketSize(bucketSizePtr *int64) (int64, error) {
+	if bucketSizePtr == nil { // no bucket_size parameter
+		return 0, nil
+	}
+	if *bucketSizePtr < 0 {
+		return 0, fmt.Errorf("bucket_size must not be negative, but got %d", *bucketSizePtr)
+	}
+	return *bucketSizePtr, nil
+}
+
+// newResultCount returns the RPC response of a count, or an error if it has
+// more than maxCountBuckets buckets.
+func newResultCount(totalCount int, buckets []indexer.HeightBucket) (*ctypes.ResultCount, error) {
+	if len(buckets) > maxCountBuckets {
+		return nil, ErrTooManyBuckets{len(buckets), maxCountBuckets}
+	}
+	result := &ctypes.ResultCount{TotalCount: totalCount}
+	for _, b := range buckets {
+		result.Buckets = append(result.Buckets, ctypes.HeightBucket{
+			FromHeight: b.FromHeight,
+			ToHeight:   b.ToHeight,
+			Count:      b.Count,
+		})
+	}
+	return result, nil
+}
+
+// encodeCursor returns the opaque cursor of the search results following the
+// result at the given height and index.
+func encodeCursor(height int64, index uint32) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d/%d", height, index)))
+}
+
+// decodeCursor returns the position encoded by a cursor returned by
+// encodeCursor.
+func decodeCursor(cursor string) (*txindex.Cursor, error) {
+	bz, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor{cursor}
+	}
+	heightStr, indexStr, ok := strings.Cut(string(bz), "/")
+	if !ok {
+		return nil, ErrInvalidCursor{cursor}
+	}
+	height, err := strconv.ParseInt(heightStr, 10, 64)
+	if err != nil || height < 0 {
+		return nil, ErrInvalidCursor{cursor}
+	}
+	index, err := strconv.ParseUint(indexStr, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidCursor{cursor}
+	}
+	return &txindex.Cursor{Height: height, Index: uint32(index)}, nil
+}
+
 // latestHeight can be either latest committed or uncommitted (+1) height.
 func (env *Environment) getHeight(latestHeight int64, heightPtr *int64) (int64, error) {
 	if heightPtr != nil {
@@ -18,6 +18,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	cmtjson "github.com/cometbft/cometbft/v2/libs/json"
+	"github.com/cometbft/cometbft/v2/state/indexer"
+	"github.com/cometbft/cometbft/v2/state/txindex"
 	"github.com/cometbft/cometbft/v2/types"
 )
 
@@ -231,6 +233,32 @@ func TestPaginationPerPage(t *testing.T) {
 	assert.Equal(t, defaultPerPage, p)
 }
 
+func TestCursor(t *testing.T) {
+	cursor, err := decodeCursor(encodeCursor(12, 3))
+	require.NoError(t, err)
+	assert.Equal(t, &txindex.Cursor{Height: 12, Index: 3}, cursor)
+
+	for _, c := range []string{"", "12/3", encodeCursor(12, 3) + "=", "MTI", "LTEvMw", "MTIvLTE"} {
+		_, err := decodeCursor(c)
+		require.ErrorAs(t, err, &ErrInvalidCursor{}, c)
+	}
+}
+
+func TestCountMaxBuckets(t *testing.T) {
+	heights := make([]int64, 2*maxCountBuckets)
+	for i := range heights {
+		heights[i] = int64(i + 1)
+	}
+
+	_, err := newResultCount(len(heights), indexer.CountByHeight(heights, 1))
+	require.ErrorAs(t, err, &ErrTooManyBuckets{})
+
+	result, err := newResultCount(len(heights), indexer.CountByHeight(heights, 2))
+	require.NoError(t, err)
+	assert.Len(t, result.Buckets, maxCountBuckets)
+	assert.Equal(t, len(heights), result.TotalCount)
+}
+
 func TestCleanup(t *testing.T) {
 	t.Run("NoErrDirNotExist", func(t *testing.T) {
 		env := &Environment{GenesisFilePath: "/nonexistent/path/to/genesis.json"}
@@ -150,6 +150,23 @@ func (e ErrInvalidOrderBy) Error() string {
 	return "invalid order_by: maxLength either `asc` or `desc` or an empty value but got " + e.OrderBy
 }
 
+type ErrTooManyBuckets struct {
+	Buckets    int
+	MaxBuckets int
+}
+
+func (e ErrTooManyBuckets) Error() string {
+	return fmt.Sprintf("too many buckets: %d, max %d; use a larger bucket_size", e.Buckets, e.MaxBuckets)
+}
+
+type ErrInvalidCursor struct {
+	Cursor string
+}
+
+func (e ErrInvalidCursor) Error() string {
+	return "invalid cursor: " + e.Cursor
+}
+
 type ErrInvalidNodeType struct {
 	PeerID   string
 	Expected string
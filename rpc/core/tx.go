@@ -53,6 +53,8 @@ func (env *Environment) Tx(_ *rpctypes.Context, hash []byte, prove bool) (*ctype
 
 // TxSearch allows you to query for multiple transactions results. It returns a
 // list of transactions (maximum ?per_page entries) and the total count.
+// If a cursor or a limit is given, at most ?limit transactions following the
+// cursor are returned instead of a page, with the cursor of the next ones.
 // More: https://docs.cometbft.com/main/rpc/#/Info/tx_search
 func (env *Environment) TxSearch(
 	ctx *rpctypes.Context,
@@ -60,6 +62,8 @@ func (env *Environment) TxSearch(
 	prove bool,
 	pagePtr, perPagePtr *int,
 	orderBy string,
+	cursor string,
+	limitPtr *int,
 ) (*ctypes.ResultTxSearch, error) {
 	// if index is disabled, return error
 	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
@@ -92,6 +96,16 @@ func (env *Environment) TxSearch(
 		Page:        *pagePtr,
 		PerPage:     perPage,
 	}
+	if cursor != "" || limitPtr != nil {
+		pagSettings.IsPaginated = false
+		pagSettings.Limit = env.validatePerPage(limitPtr)
+		if cursor != "" {
+			pagSettings.Cursor, err = decodeCursor(cursor)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	results, totalCount, err := env.TxIndexer.Search(ctx.Context(), q, pagSettings)
 	if err != nil {
@@ -118,5 +132,44 @@ func (env *Environment) TxSearch(
 		})
 	}
 
-	return &ctypes.ResultTxSearch{Txs: apiResults, TotalCount: totalCount}, nil
+	result := &ctypes.ResultTxSearch{Txs: apiResults, TotalCount: totalCount}
+	if pagSettings.Limit > 0 && len(results) > 0 && len(results) < totalCount {
+		last := results[len(results)-1]
+		result.NextCursor = encodeCursor(last.Height, last.Index)
+	}
+	return result, nil
+}
+
+// TxCount returns the number of transactions matching the query and, if
+// ?bucket_size is given, their number in each range of bucket_size heights,
+// without loading the transactions. It fails if the transactions fall into
+// more than maxCountBuckets ranges.
+// More: https://docs.cometbft.com/main/rpc/#/Info/tx_count
+func (env *Environment) TxCount(
+	ctx *rpctypes.Context,
+	query string,
+	bucketSizePtr *int64,
+) (*ctypes.ResultCount, error) {
+	// if index is disabled, return error
+	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
+		return nil, ErrTxIndexingDisabled
+	} else if len(query) > maxQueryLength {
+		return nil, ErrQueryLength{len(query), maxQueryLength}
+	}
+
+	bucketSize, err := validateBucketSize(bucketSizePtr)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := cmtquery.New(query)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount, buckets, err := env.TxIndexer.Count(ctx.Context(), q, bucketSize)
+	if err != nil {
+		return nil, err
+	}
+	return newResultCount(totalCount, buckets)
 }
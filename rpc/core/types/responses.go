@@ -208,12 +208,34 @@ type ResultTx struct {
 type ResultTxSearch struct {
 	Txs        []*ResultTx `json:"txs"`
 	TotalCount int         `json:"total_count"`
+	// NextCursor is the cursor from which to resume the search, if it was
+	// given a cursor or a limit and more transactions match the query.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // ResultBlockSearch defines the RPC response type for a block search by events.
 type ResultBlockSearch struct {
 	Blocks     []*ResultBlock `json:"blocks"`
 	TotalCount int            `json:"total_count"`
+	// NextCursor is the cursor from which to resume the search, if it was
+	// given a cursor or a limit and more blocks match the query.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ResultCount defines the RPC response type for counting the transactions or
+// blocks matching a query.
+type ResultCount struct {
+	TotalCount int `json:"total_count"`
+	// Buckets are the numbers of matches in each range of heights, if a
+	// bucket size was given. Empty ranges are omitted.
+	Buckets []HeightBucket `json:"buckets,omitempty"`
+}
+
+// HeightBucket is the number of matches in the heights [FromHeight, ToHeight].
+type HeightBucket struct {
+	FromHeight int64 `json:"from_height"`
+	ToHeight   int64 `json:"to_height"`
+	Count      int   `json:"count"`
 }
 
 // Single mempool tx.
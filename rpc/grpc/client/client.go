@@ -22,6 +22,7 @@ type Client interface {
 	VersionServiceClient
 	BlockServiceClient
 	BlockResultsServiceClient
+	LightBlockServiceClient
 
 	// Close the connection to the server. Any subsequent requests will fail.
 	Close() error
@@ -34,6 +35,7 @@ type clientBuilder struct {
 	versionServiceEnabled      bool
 	blockServiceEnabled        bool
 	blockResultsServiceEnabled bool
+	lightBlockServiceEnabled   bool
 }
 
 func newClientBuilder() *clientBuilder {
@@ -43,6 +45,7 @@ func newClientBuilder() *clientBuilder {
 		versionServiceEnabled:      true,
 		blockServiceEnabled:        true,
 		blockResultsServiceEnabled: true,
+		lightBlockServiceEnabled:   true,
 	}
 }
 
@@ -56,6 +59,7 @@ type client struct {
 	VersionServiceClient
 	BlockServiceClient
 	BlockResultsServiceClient
+	LightBlockServiceClient
 }
 
 // Close implements Client.
@@ -105,6 +109,17 @@ func WithBlockResultsServiceEnabled(enabled bool) Option {
 	}
 }
 
+// WithLightBlockServiceEnabled allows control of whether or not to create a
+// client for interacting with the light block service of a CometBFT node.
+//
+// If disabled and the client attempts to access the light block service API,
+// the client will panic.
+func WithLightBlockServiceEnabled(enabled bool) Option {
+	return func(b *clientBuilder) {
+		b.lightBlockServiceEnabled = enabled
+	}
+}
+
 // WithGRPCDialOption allows passing lower-level gRPC dial options through to
 // the gRPC dialer when creating the client.
 func WithGRPCDialOption(opt ggrpc.DialOption) Option {
@@ -144,10 +159,15 @@ func New(_ context.Context, addr string, opts ...Option) (Client, error) {
 	if builder.blockResultsServiceEnabled {
 		blockResultServiceClient = newBlockResultsServiceClient(conn)
 	}
+	lightBlockServiceClient := newDisabledLightBlockServiceClient()
+	if builder.lightBlockServiceEnabled {
+		lightBlockServiceClient = newLightBlockServiceClient(conn)
+	}
 	return &client{
 		conn:                      conn,
 		VersionServiceClient:      versionServiceClient,
 		BlockServiceClient:        blockServiceClient,
 		BlockResultsServiceClient: blockResultServiceClient,
+		LightBlockServiceClient:   lightBlockServiceClient,
 	}, nil
 }
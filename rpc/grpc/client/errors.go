@@ -47,3 +47,29 @@ func (e ErrDial) Error() string {
 func (e ErrDial) Unwrap() error {
 	return e.Source
 }
+
+type ErrLightBlock struct {
+	Height int64
+	Source error
+}
+
+func (e ErrLightBlock) Error() string {
+	return fmt.Sprintf("error fetching LightBlock for height %d: %s", e.Height, e.Source.Error())
+}
+
+func (e ErrLightBlock) Unwrap() error {
+	return e.Source
+}
+
+type ErrConsensusParams struct {
+	Height int64
+	Source error
+}
+
+func (e ErrConsensusParams) Error() string {
+	return fmt.Sprintf("error fetching ConsensusParams for height %d: %s", e.Height, e.Source.Error())
+}
+
+func (e ErrConsensusParams) Unwrap() error {
+	return e.Source
+}
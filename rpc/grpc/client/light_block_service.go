@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	ggrpc "google.golang.org/grpc"
+
+	lightblocksvc "github.com/cometbft/cometbft/api/cometbft/services/light_block/v1"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// LightBlockServiceClient provides the data light clients need to verify
+// headers.
+type LightBlockServiceClient interface {
+	// GetLightBlock returns the light block at the given height, or at the
+	// latest height if height is 0.
+	GetLightBlock(ctx context.Context, height int64) (*types.LightBlock, error)
+
+	// GetConsensusParams returns the consensus parameters at the given
+	// height, or at the latest height if height is 0.
+	GetConsensusParams(ctx context.Context, height int64) (*types.ConsensusParams, error)
+}
+
+type lightBlockServiceClient struct {
+	client lightblocksvc.LightBlockServiceClient
+}
+
+func newLightBlockServiceClient(conn ggrpc.ClientConnInterface) LightBlockServiceClient {
+	return &lightBlockServiceClient{
+		client: lightblocksvc.NewLightBlockServiceClient(conn),
+	}
+}
+
+// GetLightBlock implements LightBlockServiceClient.
+func (c *lightBlockServiceClient) GetLightBlock(ctx context.Context, height int64) (*types.LightBlock, error) {
+	res, err := c.client.GetLightBlock(ctx, &lightblocksvc.GetLightBlockRequest{Height: height})
+	if err != nil {
+		return nil, ErrLightBlock{Height: height, Source: err}
+	}
+	return types.LightBlockFromProto(res.LightBlock)
+}
+
+// GetConsensusParams implements LightBlockServiceClient.
+func (c *lightBlockServiceClient) GetConsensusParams(ctx context.Context, height int64) (*types.ConsensusParams, error) {
+	res, err := c.client.GetConsensusParams(ctx, &lightblocksvc.GetConsensusParamsRequest{Height: height})
+	if err != nil {
+		return nil, ErrConsensusParams{Height: height, Source: err}
+	}
+	if res.ConsensusParams == nil {
+		return nil, ErrConsensusParams{Height: height, Source: errors.New("missing consensus params")}
+	}
+	params := types.ConsensusParamsFromProto(*res.ConsensusParams)
+	return &params, nil
+}
+
+type disabledLightBlockServiceClient struct{}
+
+func newDisabledLightBlockServiceClient() LightBlockServiceClient {
+	return &disabledLightBlockServiceClient{}
+}
+
+// GetLightBlock implements LightBlockServiceClient.
+func (*disabledLightBlockServiceClient) GetLightBlock(context.Context, int64) (*types.LightBlock, error) {
+	panic("light block service client is disabled")
+}
+
+// GetConsensusParams implements LightBlockServiceClient.
+func (*disabledLightBlockServiceClient) GetConsensusParams(context.Context, int64) (*types.ConsensusParams, error) {
+	panic("light block service client is disabled")
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/cometbft/cometbft/v2/rpc/auth"
+)
+
+// WithAuthenticator requires the clients of the server to authenticate with a
+// bearer token accepted by authenticator, in the "authorization" metadata,
+// and only lets them call the methods allowed by their token. See
+// AuthUnaryInterceptor.
+func WithAuthenticator(authenticator *auth.Authenticator) Option {
+	return func(b *serverBuilder) {
+		b.grpcOpts = append(b.grpcOpts,
+			grpc.ChainUnaryInterceptor(AuthUnaryInterceptor(authenticator)),
+			grpc.ChainStreamInterceptor(AuthStreamInterceptor(authenticator)),
+		)
+	}
+}
+
+// AuthUnaryInterceptor returns a gRPC interceptor rejecting the unary calls
+// without a bearer token accepted by authenticator with the code
+// Unauthenticated, and the calls to methods the token does not allow with the
+// code PermissionDenied.
+//
+// The route of a method, matched against the patterns of the token, is its
+// full name without the leading slash, e.g.
+// "cometbft.services.block.v2.BlockService/GetByHeight".
+func AuthUnaryInterceptor(authenticator *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		grant, err := authenticate(ctx, authenticator, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(auth.ContextWithGrant(ctx, grant), req)
+	}
+}
+
+// AuthStreamInterceptor is like AuthUnaryInterceptor, for the streaming
+// calls.
+func AuthStreamInterceptor(authenticator *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, err := authenticate(ss.Context(), authenticator, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authenticate returns the grant of the bearer token in the metadata of ctx,
+// if it allows calling fullMethod, or a gRPC status error.
+func authenticate(ctx context.Context, authenticator *auth.Authenticator, fullMethod string) (*auth.Grant, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = auth.BearerToken(values[0])
+		}
+	}
+	grant, err := authenticator.Authenticate(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err := grant.Authorize(strings.TrimPrefix(fullMethod, "/")); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return grant, nil
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/cometbft/cometbft/v2/rpc/auth"
+)
+
+const (
+	getByHeightMethod = "/cometbft.services.block.v2.BlockService/GetByHeight"
+	getLatestMethod   = "/cometbft.services.block.v2.BlockService/GetLatestHeight"
+	getVersionMethod  = "/cometbft.services.version.v1.VersionService/GetVersion"
+)
+
+func newTestAuthenticator(t *testing.T) *auth.Authenticator {
+	t.Helper()
+	authenticator, err := auth.NewAuthenticator(auth.Config{Tokens: []auth.Token{
+		{Name: "block", Token: "secret1", Routes: []string{"cometbft.services.block.v2.BlockService/*"}},
+		{Name: "admin", Token: "secret2", Routes: []string{"*"}},
+	}})
+	require.NoError(t, err)
+	return authenticator
+}
+
+// incomingContext returns the context of a call with the given authorization
+// metadata, if not empty.
+func incomingContext(authorization string) context.Context {
+	if authorization == "" {
+		return context.Background()
+	}
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", authorization))
+}
+
+func TestAuthUnaryInterceptor(t *testing.T) {
+	interceptor := AuthUnaryInterceptor(newTestAuthenticator(t))
+
+	testCases := []struct {
+		name          string
+		authorization string
+		method        string
+		code          codes.Code
+		grant         string
+	}{
+		{"missing token", "", getByHeightMethod, codes.Unauthenticated, ""},
+		{"invalid token", "Bearer secret3", getByHeightMethod, codes.Unauthenticated, ""},
+		{"not bearer", "secret1", getByHeightMethod, codes.Unauthenticated, ""},
+		{"route not allowed", "Bearer secret1", getVersionMethod, codes.PermissionDenied, ""},
+		{"allowed", "Bearer secret1", getByHeightMethod, codes.OK, "block"},
+		{"allowed by bare wildcard", "Bearer secret2", getVersionMethod, codes.OK, "admin"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, _ any) (any, error) {
+				called = true
+				grant, ok := auth.GrantFromContext(ctx)
+				require.True(t, ok)
+				assert.Equal(t, tc.grant, grant.Name)
+				return "response", nil
+			}
+
+			resp, err := interceptor(incomingContext(tc.authorization), "request",
+				&grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			assert.Equal(t, tc.code, status.Code(err), err)
+			assert.Equal(t, tc.code == codes.OK, called)
+			if tc.code == codes.OK {
+				assert.Equal(t, "response", resp)
+			}
+		})
+	}
+}
+
+// testServerStream is a server stream of a call with the given context.
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s testServerStream) Context() context.Context { return s.ctx }
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	interceptor := AuthStreamInterceptor(newTestAuthenticator(t))
+
+	testCases := []struct {
+		name          string
+		authorization string
+		method        string
+		code          codes.Code
+	}{
+		{"missing token", "", getLatestMethod, codes.Unauthenticated},
+		{"invalid token", "Bearer secret3", getLatestMethod, codes.Unauthenticated},
+		{"route not allowed", "Bearer secret1", getVersionMethod, codes.PermissionDenied},
+		{"allowed", "Bearer secret1", getLatestMethod, codes.OK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := func(any, grpc.ServerStream) error {
+				called = true
+				return nil
+			}
+
+			stream := testServerStream{ctx: incomingContext(tc.authorization)}
+			err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: tc.method, IsServerStream: true}, handler)
+			assert.Equal(t, tc.code, status.Code(err), err)
+			assert.Equal(t, tc.code == codes.OK, called)
+		})
+	}
+}
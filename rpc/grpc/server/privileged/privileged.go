@@ -8,6 +8,8 @@ import (
 
 	pbpruningsvc "github.com/cometbft/cometbft/api/cometbft/services/pruning/v1"
 	"github.com/cometbft/cometbft/v2/libs/log"
+	"github.com/cometbft/cometbft/v2/rpc/auth"
+	"github.com/cometbft/cometbft/v2/rpc/grpc/server"
 	"github.com/cometbft/cometbft/v2/rpc/grpc/server/services/pruningservice"
 	sm "github.com/cometbft/cometbft/v2/state"
 )
@@ -46,6 +48,18 @@ func WithLogger(logger log.Logger) Option {
 	}
 }
 
+// WithAuthenticator requires the clients of the server to authenticate with a
+// bearer token accepted by authenticator, and only lets them call the methods
+// allowed by their token. See server.AuthUnaryInterceptor.
+func WithAuthenticator(authenticator *auth.Authenticator) Option {
+	return func(b *serverBuilder) {
+		b.grpcOpts = append(b.grpcOpts,
+			grpc.ChainUnaryInterceptor(server.AuthUnaryInterceptor(authenticator)),
+			grpc.ChainStreamInterceptor(server.AuthStreamInterceptor(authenticator)),
+		)
+	}
+}
+
 // WithGRPCOption allows one to specify Google gRPC server options during the
 // construction of the CometBFT gRPC server.
 func WithGRPCOption(opt grpc.ServerOption) Option {
@@ -9,11 +9,13 @@ import (
 
 	pbblocksvc "github.com/cometbft/cometbft/api/cometbft/services/block/v2"
 	brs "github.com/cometbft/cometbft/api/cometbft/services/block_results/v2"
+	pblightblocksvc "github.com/cometbft/cometbft/api/cometbft/services/light_block/v1"
 	pbversionsvc "github.com/cometbft/cometbft/api/cometbft/services/version/v1"
 	"github.com/cometbft/cometbft/v2/libs/log"
 	grpcerr "github.com/cometbft/cometbft/v2/rpc/grpc/errors"
 	"github.com/cometbft/cometbft/v2/rpc/grpc/server/services/blockresultservice"
 	"github.com/cometbft/cometbft/v2/rpc/grpc/server/services/blockservice"
+	"github.com/cometbft/cometbft/v2/rpc/grpc/server/services/lightblockservice"
 	"github.com/cometbft/cometbft/v2/rpc/grpc/server/services/versionservice"
 	sm "github.com/cometbft/cometbft/v2/state"
 	"github.com/cometbft/cometbft/v2/store"
@@ -29,6 +31,7 @@ type serverBuilder struct {
 	versionService      pbversionsvc.VersionServiceServer
 	blockService        pbblocksvc.BlockServiceServer
 	blockResultsService brs.BlockResultsServiceServer
+	lightBlockService   pblightblocksvc.LightBlockServiceServer
 	logger              log.Logger
 	grpcOpts            []grpc.ServerOption
 }
@@ -74,6 +77,13 @@ func WithBlockResultsService(bs *store.BlockStore, ss sm.Store, logger log.Logge
 	}
 }
 
+// WithLightBlockService enables the light block service on the CometBFT server.
+func WithLightBlockService(bs *store.BlockStore, ss sm.Store, logger log.Logger) Option {
+	return func(b *serverBuilder) {
+		b.lightBlockService = lightblockservice.New(bs, ss, logger)
+	}
+}
+
 // WithLogger enables logging using the given logger. If not specified, the
 // gRPC server does not log anything.
 func WithLogger(logger log.Logger) Option {
@@ -114,6 +124,10 @@ func Serve(listener net.Listener, opts ...Option) error {
 		brs.RegisterBlockResultsServiceServer(server, b.blockResultsService)
 		b.logger.Debug("Registered block results service")
 	}
+	if b.lightBlockService != nil {
+		pblightblocksvc.RegisterLightBlockServiceServer(server, b.lightBlockService)
+		b.logger.Debug("Registered light block service")
+	}
 	b.logger.Info("serve", "msg", fmt.Sprintf("Starting gRPC server on %s", listener.Addr()))
 	return server.Serve(b.listener)
 }
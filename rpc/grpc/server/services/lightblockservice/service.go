@@ -0,0 +1,123 @@
+// Package lightblockservice implements the gRPC light block service, which
+// serves the light blocks and consensus parameters needed by light clients,
+// for instance to state sync a node over gRPC.
+package lightblockservice
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	lightblocksvc "github.com/cometbft/cometbft/api/cometbft/services/light_block/v1"
+	"github.com/cometbft/cometbft/v2/internal/rpctrace"
+	"github.com/cometbft/cometbft/v2/libs/log"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/store"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+type lightBlockServiceServer struct {
+	blockStore *store.BlockStore
+	stateStore sm.Store
+	logger     log.Logger
+}
+
+// New creates a new CometBFT light block service server.
+func New(bs *store.BlockStore, ss sm.Store, logger log.Logger) lightblocksvc.LightBlockServiceServer {
+	return &lightBlockServiceServer{
+		blockStore: bs,
+		stateStore: ss,
+		logger:     logger.With("service", "LightBlockService"),
+	}
+}
+
+// GetLightBlock implements v1.LightBlockServiceServer GetLightBlock method.
+func (s *lightBlockServiceServer) GetLightBlock(
+	_ context.Context,
+	req *lightblocksvc.GetLightBlockRequest,
+) (*lightblocksvc.GetLightBlockResponse, error) {
+	logger := s.logger.With("endpoint", "GetLightBlock")
+	height, err := s.height(req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := s.blockStore.LoadBlockMeta(height)
+	if meta == nil {
+		return nil, status.Errorf(codes.NotFound, "Block not found for height %d", height)
+	}
+	// The canonical commit of the latest block is only known once the next
+	// block is committed: serve the seen commit meanwhile.
+	commit := s.blockStore.LoadBlockCommit(height)
+	if commit == nil && height == s.blockStore.Height() {
+		commit = s.blockStore.LoadSeenCommit(height)
+	}
+	if commit == nil {
+		return nil, status.Errorf(codes.NotFound, "Commit not found for height %d", height)
+	}
+	vals, err := s.stateStore.LoadValidators(height)
+	if err != nil {
+		return nil, s.internalError(logger, "Failed to load validators", height, err)
+	}
+
+	lb := &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: &meta.Header, Commit: commit},
+		ValidatorSet: vals,
+	}
+	pb, err := lb.ToProto()
+	if err != nil {
+		return nil, s.internalError(logger, "Failed to convert light block to Protobuf", height, err)
+	}
+	return &lightblocksvc.GetLightBlockResponse{LightBlock: pb}, nil
+}
+
+// GetConsensusParams implements v1.LightBlockServiceServer GetConsensusParams
+// method.
+func (s *lightBlockServiceServer) GetConsensusParams(
+	_ context.Context,
+	req *lightblocksvc.GetConsensusParamsRequest,
+) (*lightblocksvc.GetConsensusParamsResponse, error) {
+	logger := s.logger.With("endpoint", "GetConsensusParams")
+	height, err := s.height(req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := s.stateStore.LoadConsensusParams(height)
+	if err != nil {
+		return nil, s.internalError(logger, "Failed to load consensus params", height, err)
+	}
+	pb := params.ToProto()
+	return &lightblocksvc.GetConsensusParamsResponse{ConsensusParams: &pb}, nil
+}
+
+// height validates the requested height, mapping 0 to the latest height.
+func (s *lightBlockServiceServer) height(height int64) (int64, error) {
+	base, latest := s.blockStore.Base(), s.blockStore.Height()
+	if height == 0 {
+		height = latest
+	}
+	if height < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "Height cannot be less than 0")
+	}
+	if height > latest {
+		return 0, status.Errorf(codes.OutOfRange,
+			"Height %d must be less than or equal to the latest height %d", height, latest)
+	}
+	if height < base {
+		return 0, status.Errorf(codes.NotFound,
+			"Height %d is not available, lowest height is %d", height, base)
+	}
+	return height, nil
+}
+
+func (*lightBlockServiceServer) internalError(logger log.Logger, msg string, height int64, err error) error {
+	traceID, terr := rpctrace.New()
+	if terr != nil {
+		logger.Error("Error generating RPC trace ID", "err", terr)
+		return status.Error(codes.Internal, "Internal server error - see logs for details")
+	}
+	logger.Error(msg, "height", height, "err", err, "traceID", traceID)
+	return status.Errorf(codes.Internal, "%s (see logs for trace ID: %s)", msg, traceID)
+}
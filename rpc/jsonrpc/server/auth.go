@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cometbft/cometbft/v2/libs/log"
+	"github.com/cometbft/cometbft/v2/rpc/auth"
+	"github.com/cometbft/cometbft/v2/rpc/jsonrpc/types"
+)
+
+// AuthHandler wraps next with a handler rejecting the requests without a
+// bearer token accepted by authenticator, with the HTTP status 401. The grant
+// of the token is passed down in the context of the request, so that the
+// JSON-RPC, URI and websocket handlers only call the routes it allows.
+func AuthHandler(authenticator *auth.Authenticator, next http.Handler, logger log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		grant, err := authenticator.Authenticate(auth.BearerToken(r.Header.Get("Authorization")))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			res := types.RPCUnauthorizedError(nil, err)
+			if wErr := WriteRPCResponseHTTPError(w, http.StatusUnauthorized, res); wErr != nil {
+				logger.Error("failed to write response", "err", wErr)
+			}
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.ContextWithGrant(r.Context(), grant)))
+	})
+}
+
+// authorize returns an error if the grant in ctx, if any, does not allow
+// calling method.
+func authorize(ctx context.Context, method string) error {
+	grant, ok := auth.GrantFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return grant.Authorize(method)
+}
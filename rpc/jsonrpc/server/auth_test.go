@@ -0,0 +1,89 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/v2/libs/log"
+	"github.com/cometbft/cometbft/v2/rpc/auth"
+	"github.com/cometbft/cometbft/v2/rpc/jsonrpc/types"
+)
+
+func TestAuthHandler(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(auth.Config{Tokens: []auth.Token{
+		{Name: "reader", Token: "secret", Routes: []string{"block"}},
+	}})
+	require.NoError(t, err)
+	logger := log.NewLogger(new(bytes.Buffer))
+	handler := AuthHandler(authenticator, testMux(), logger)
+
+	do := func(req *http.Request, token string) (int, []byte) {
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		res := rec.Result()
+		defer res.Body.Close()
+		blob, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, blob
+	}
+	post := func(payload, token string) (int, []byte) {
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(payload))
+		return do(req, token)
+	}
+
+	// Without a valid token, the requests are rejected.
+	for _, token := range []string{"", "wrong"} {
+		code, blob := post(`{"jsonrpc": "2.0", "method": "block", "id": 1, "params": ["1"]}`, token)
+		require.Equal(t, http.StatusUnauthorized, code)
+		var response types.RPCResponse
+		require.NoError(t, json.Unmarshal(blob, &response))
+		require.NotNil(t, response.Error)
+		assert.Equal(t, -32001, response.Error.Code)
+	}
+
+	// The allowed routes may be called.
+	code, blob := post(`{"jsonrpc": "2.0", "method": "block", "id": 1, "params": ["1"]}`, "secret")
+	require.Equal(t, http.StatusOK, code)
+	var response types.RPCResponse
+	require.NoError(t, json.Unmarshal(blob, &response))
+	assert.Nil(t, response.Error)
+
+	// The others may not.
+	code, blob = post(`{"jsonrpc": "2.0", "method": "c", "id": 2, "params": ["a", "10"]}`, "secret")
+	require.Equal(t, http.StatusForbidden, code)
+	require.NoError(t, json.Unmarshal(blob, &response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, -32003, response.Error.Code)
+
+	// Within a batch, only the requests to forbidden routes get an error.
+	code, blob = post(`[
+		{"jsonrpc": "2.0", "method": "block", "id": 1, "params": ["1"]},
+		{"jsonrpc": "2.0", "method": "c", "id": 2, "params": ["a", "10"]}
+	]`, "secret")
+	require.Equal(t, http.StatusOK, code)
+	var responses []types.RPCResponse
+	require.NoError(t, json.Unmarshal(blob, &responses))
+	require.Len(t, responses, 2)
+	assert.Nil(t, responses[0].Error)
+	require.NotNil(t, responses[1].Error)
+	assert.Equal(t, -32003, responses[1].Error.Code)
+
+	// So do the URI requests.
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/block?height=1", nil)
+	code, _ = do(req, "secret")
+	assert.Equal(t, http.StatusOK, code)
+	req, _ = http.NewRequest(http.MethodGet, "http://localhost/c?s=\"a\"&i=10", nil)
+	code, _ = do(req, "secret")
+	assert.Equal(t, http.StatusForbidden, code)
+}
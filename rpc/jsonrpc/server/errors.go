@@ -31,3 +31,24 @@ func (e ErrListening) Error() string {
 func (e ErrListening) Unwrap() error {
 	return e.Source
 }
+
+// ErrRateLimited is returned when a client sends requests faster than the rate
+// allowed by the Limiter.
+type ErrRateLimited struct {
+	Client string
+}
+
+func (e ErrRateLimited) Error() string {
+	return fmt.Sprintf("too many requests from %s, please retry later", e.Client)
+}
+
+// ErrTooManyConcurrentCalls is returned when the number of concurrent calls to
+// an expensive route allowed by the Limiter is reached.
+type ErrTooManyConcurrentCalls struct {
+	Method string
+	Max    int
+}
+
+func (e ErrTooManyConcurrentCalls) Error() string {
+	return fmt.Sprintf("too many concurrent %s calls (max: %d), please retry later", e.Method, e.Max)
+}
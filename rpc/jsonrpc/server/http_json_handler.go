@@ -18,7 +18,7 @@ import (
 // HTTP + JSON handler
 
 // jsonrpc calls grab the given method's function info and runs reflect.Call.
-func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.HandlerFunc {
+func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger, limiter *Limiter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		b, err := io.ReadAll(r.Body)
 		if err != nil {
@@ -42,6 +42,7 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.Han
 		var (
 			requests  []types.RPCRequest
 			responses []types.RPCResponse
+			batch     = true
 		)
 		if err := json.Unmarshal(b, &requests); err != nil {
 			// next, try to unmarshal as a single request
@@ -54,6 +55,7 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.Han
 				return
 			}
 			requests = []types.RPCRequest{request}
+			batch = false
 		}
 
 		// Set the default response cache to true unless
@@ -61,6 +63,9 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.Han
 		// 2. Any RPC request doesn't allow to be cached.
 		// 3. Any RPC request has the height argument and the value is 0 (the default).
 		cache := true
+		// errStatus is the HTTP status of the response when a single request
+		// is rejected, for lack of permission or because a limit is exceeded.
+		errStatus := 0
 		for _, req := range requests {
 			request := req
 			// A Notification is a Request object without an "id" member.
@@ -87,6 +92,12 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.Han
 				cache = false
 				continue
 			}
+			if err := authorize(r.Context(), request.Method); err != nil {
+				responses = append(responses, types.RPCForbiddenError(request.ID, err))
+				cache = false
+				errStatus = http.StatusForbidden
+				continue
+			}
 			ctx := &types.Context{JSONReq: &request, HTTPReq: r}
 			args := []reflect.Value{reflect.ValueOf(ctx)}
 			if len(request.Params) > 0 {
@@ -106,7 +117,14 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.Han
 				cache = false
 			}
 
-			returns := rpcFunc.f.Call(args)
+			release, err := limiter.Acquire(r.RemoteAddr, request.Method)
+			if err != nil {
+				responses = append(responses, types.RPCLimitExceededError(request.ID, err))
+				cache = false
+				errStatus = http.StatusTooManyRequests
+				continue
+			}
+			returns := rpcFunc.call(args, release)
 			result, err := unreflectResult(returns)
 			if err != nil {
 				responses = append(responses, types.RPCInternalError(request.ID, err))
@@ -115,6 +133,15 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.Han
 			responses = append(responses, types.NewRPCSuccessResponse(request.ID, result))
 		}
 
+		// When a single request is rejected, reflect it in the HTTP status.
+		// Within a batch, the rejected requests only get an error response.
+		if !batch && errStatus != 0 {
+			if wErr := WriteRPCResponseHTTPError(w, errStatus, responses[0]); wErr != nil {
+				logger.Error("failed to write response", "err", wErr)
+			}
+			return
+		}
+
 		if len(responses) > 0 {
 			var wErr error
 			if cache {
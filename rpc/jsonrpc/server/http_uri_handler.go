@@ -18,7 +18,12 @@ import (
 var reInt = regexp.MustCompile(`^-?[0-9]+$`)
 
 // convert from a function name to the http handler.
-func makeHTTPHandler(rpcFunc *RPCFunc, logger log.Logger) func(http.ResponseWriter, *http.Request) {
+func makeHTTPHandler(
+	funcName string,
+	rpcFunc *RPCFunc,
+	logger log.Logger,
+	limiter *Limiter,
+) func(http.ResponseWriter, *http.Request) {
 	// Always return -1 as there's no ID here.
 	dummyID := types.JSONRPCIntID(-1) // URIClientRequestID
 
@@ -42,6 +47,14 @@ func makeHTTPHandler(rpcFunc *RPCFunc, logger log.Logger) func(http.ResponseWrit
 			"postForm": r.PostForm,
 		})
 
+		if err := authorize(r.Context(), funcName); err != nil {
+			res := types.RPCForbiddenError(dummyID, err)
+			if wErr := WriteRPCResponseHTTPError(w, http.StatusForbidden, res); wErr != nil {
+				logger.Error("failed to write response", "err", wErr)
+			}
+			return
+		}
+
 		ctx := &types.Context{HTTPReq: r}
 		args := []reflect.Value{reflect.ValueOf(ctx)}
 
@@ -57,7 +70,15 @@ func makeHTTPHandler(rpcFunc *RPCFunc, logger log.Logger) func(http.ResponseWrit
 		}
 		args = append(args, fnArgs...)
 
-		returns := rpcFunc.f.Call(args)
+		release, err := limiter.Acquire(r.RemoteAddr, funcName)
+		if err != nil {
+			res := types.RPCLimitExceededError(dummyID, err)
+			if wErr := WriteRPCResponseHTTPError(w, http.StatusTooManyRequests, res); wErr != nil {
+				logger.Error("failed to write response", "err", wErr)
+			}
+			return
+		}
+		returns := rpcFunc.call(args, release)
 
 		logArgs := make([]any, 0, len(fnArgs))
 		for _, arg := range fnArgs {
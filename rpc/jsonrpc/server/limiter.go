@@ -0,0 +1,174 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	cmtsync "github.com/cometbft/cometbft/v2/libs/sync"
+)
+
+// Reasons for which the Limiter rejects a request, used as metric label.
+const (
+	rejectReasonRateLimit        = "rate_limit"
+	rejectReasonConcurrencyLimit = "concurrency_limit"
+)
+
+// bucketSweepInterval is how often the token buckets of the clients which
+// have been idle long enough to be full again are removed.
+const bucketSweepInterval = time.Minute
+
+// LimiterConfig is the configuration of a Limiter. The zero value imposes no
+// limit.
+type LimiterConfig struct {
+	// Number of requests per second a client, identified by its IP address,
+	// may send on average. 0 means unlimited.
+	RequestsPerSecond float64
+	// Number of requests a client may send at once above RequestsPerSecond.
+	// If 0, it defaults to RequestsPerSecond rounded up.
+	Burst int
+	// Maximum number of concurrent calls per route in ExpensiveRoutes.
+	// 0 means unlimited.
+	MaxConcurrentCalls int
+	// Names of the routes to which MaxConcurrentCalls applies.
+	ExpensiveRoutes []string
+}
+
+// Limiter throttles the requests of each client to a rate, using a token
+// bucket per client IP address, and bounds the number of concurrent calls to
+// each expensive route. It also records the metrics of the requests, per
+// route.
+//
+// A nil *Limiter imposes no limit and records no metrics.
+type Limiter struct {
+	config  LimiterConfig
+	metrics *Metrics
+
+	mtx       cmtsync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+
+	// The semaphores of the expensive routes, when their calls are limited.
+	calls map[string]chan struct{}
+
+	now func() time.Time // time.Now, overridden in tests
+}
+
+// NewLimiter returns a Limiter enforcing the limits of config, recording its
+// metrics to metrics.
+func NewLimiter(config LimiterConfig, metrics *Metrics) *Limiter {
+	if config.RequestsPerSecond > 0 && config.Burst <= 0 {
+		config.Burst = int(config.RequestsPerSecond)
+		if float64(config.Burst) < config.RequestsPerSecond {
+			config.Burst++
+		}
+	}
+	l := &Limiter{
+		config:  config,
+		metrics: metrics,
+		buckets: make(map[string]*tokenBucket),
+		calls:   make(map[string]chan struct{}),
+		now:     time.Now,
+	}
+	if config.MaxConcurrentCalls > 0 {
+		for _, route := range config.ExpensiveRoutes {
+			l.calls[route] = make(chan struct{}, config.MaxConcurrentCalls)
+		}
+	}
+	l.lastSweep = l.now()
+	return l
+}
+
+// Acquire checks whether the client at remoteAddr may call the route method.
+// If so, the returned function must be called once the call is done.
+// Otherwise, it returns ErrRateLimited or ErrTooManyConcurrentCalls.
+func (l *Limiter) Acquire(remoteAddr, method string) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	l.metrics.Requests.With("method", method).Add(1)
+
+	if l.config.RequestsPerSecond > 0 {
+		client := clientIP(remoteAddr)
+		if !l.allow(client) {
+			l.metrics.RejectedRequests.With("method", method, "reason", rejectReasonRateLimit).Add(1)
+			return nil, ErrRateLimited{Client: client}
+		}
+	}
+
+	sem, ok := l.calls[method]
+	if ok {
+		select {
+		case sem <- struct{}{}:
+		default:
+			l.metrics.RejectedRequests.With("method", method, "reason", rejectReasonConcurrencyLimit).Add(1)
+			return nil, ErrTooManyConcurrentCalls{Method: method, Max: l.config.MaxConcurrentCalls}
+		}
+	}
+
+	start := time.Now()
+	l.metrics.InFlightCalls.With("method", method).Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.metrics.InFlightCalls.With("method", method).Add(-1)
+			l.metrics.RequestDurationSeconds.With("method", method).Observe(time.Since(start).Seconds())
+			if ok {
+				<-sem
+			}
+		})
+	}, nil
+}
+
+// allow takes a token from the bucket of client, if there is one left.
+func (l *Limiter) allow(client string) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= bucketSweepInterval {
+		for c, b := range l.buckets {
+			if b.refill(now, l.config.RequestsPerSecond, l.config.Burst) >= float64(l.config.Burst) {
+				delete(l.buckets, c)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &tokenBucket{tokens: float64(l.config.Burst), last: now}
+		l.buckets[client] = b
+	}
+	if b.refill(now, l.config.RequestsPerSecond, l.config.Burst) < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// tokenBucket holds the tokens left to a client, as of last.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens earned since the last refill, at rate tokens per
+// second up to burst, and returns the tokens in the bucket.
+func (b *tokenBucket) refill(now time.Time, rate float64, burst int) float64 {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(float64(burst), b.tokens+elapsed.Seconds()*rate)
+		b.last = now
+	}
+	return b.tokens
+}
+
+// clientIP returns the IP address of remoteAddr, so that all the connections
+// of a client share the same token bucket.
+func clientIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
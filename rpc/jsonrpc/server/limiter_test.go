@@ -0,0 +1,137 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/v2/libs/log"
+	"github.com/cometbft/cometbft/v2/rpc/jsonrpc/types"
+)
+
+func TestLimiterRateLimit(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(LimiterConfig{RequestsPerSecond: 2, Burst: 3}, NopMetrics())
+	l.now = func() time.Time { return now }
+
+	// The burst is allowed at once, then the client has to wait.
+	for i := 0; i < 3; i++ {
+		release, err := l.Acquire("1.2.3.4:1000", "status")
+		require.NoError(t, err)
+		release()
+	}
+	_, err := l.Acquire("1.2.3.4:1001", "status")
+	require.ErrorAs(t, err, &ErrRateLimited{})
+
+	// Other clients are not affected.
+	_, err = l.Acquire("5.6.7.8:1000", "status")
+	require.NoError(t, err)
+
+	// A token is earned every half second.
+	now = now.Add(500 * time.Millisecond)
+	_, err = l.Acquire("1.2.3.4:1000", "status")
+	require.NoError(t, err)
+	_, err = l.Acquire("1.2.3.4:1000", "status")
+	require.ErrorAs(t, err, &ErrRateLimited{})
+
+	// The buckets of the idle clients are removed.
+	now = now.Add(bucketSweepInterval)
+	_, err = l.Acquire("5.6.7.8:1000", "status")
+	require.NoError(t, err)
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestLimiterConcurrentCalls(t *testing.T) {
+	l := NewLimiter(LimiterConfig{MaxConcurrentCalls: 2, ExpensiveRoutes: []string{"tx_search"}}, NopMetrics())
+
+	release1, err := l.Acquire("1.2.3.4:1000", "tx_search")
+	require.NoError(t, err)
+	release2, err := l.Acquire("5.6.7.8:1000", "tx_search")
+	require.NoError(t, err)
+	_, err = l.Acquire("1.2.3.4:1000", "tx_search")
+	require.ErrorAs(t, err, &ErrTooManyConcurrentCalls{})
+
+	// The other routes are not limited.
+	for i := 0; i < 3; i++ {
+		_, err := l.Acquire("1.2.3.4:1000", "status")
+		require.NoError(t, err)
+	}
+
+	// Releasing twice frees a single call.
+	release1()
+	release1()
+	_, err = l.Acquire("1.2.3.4:1000", "tx_search")
+	require.NoError(t, err)
+	_, err = l.Acquire("1.2.3.4:1000", "tx_search")
+	require.ErrorAs(t, err, &ErrTooManyConcurrentCalls{})
+	release2()
+}
+
+func TestLimiterNil(t *testing.T) {
+	var l *Limiter
+	release, err := l.Acquire("1.2.3.4:1000", "tx_search")
+	require.NoError(t, err)
+	release()
+}
+
+func TestJSONRPCHandlerLimits(t *testing.T) {
+	funcMap := map[string]*RPCFunc{
+		"c": NewRPCFunc(func(_ *types.Context, _ string, _ int) (string, error) { return "foo", nil }, "s,i"),
+	}
+	limiter := NewLimiter(LimiterConfig{RequestsPerSecond: 1, Burst: 2}, NopMetrics())
+	mux := http.NewServeMux()
+	RegisterRPCFuncsWithLimiter(mux, funcMap, log.NewLogger(new(bytes.Buffer)), limiter)
+
+	post := func(payload string) (int, []byte) {
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(payload))
+		req.RemoteAddr = "1.2.3.4:1000"
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		res := rec.Result()
+		defer res.Body.Close()
+		blob, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, blob
+	}
+
+	// Within a batch, the requests above the limit get an error response.
+	code, blob := post(`[
+		{"jsonrpc": "2.0", "method": "c", "id": 1, "params": ["a", "10"]},
+		{"jsonrpc": "2.0", "method": "c", "id": 2, "params": ["a", "10"]},
+		{"jsonrpc": "2.0", "method": "c", "id": 3, "params": ["a", "10"]}
+	]`)
+	require.Equal(t, http.StatusOK, code)
+	var responses []types.RPCResponse
+	require.NoError(t, json.Unmarshal(blob, &responses))
+	require.Len(t, responses, 3)
+	assert.Nil(t, responses[0].Error)
+	assert.Nil(t, responses[1].Error)
+	require.NotNil(t, responses[2].Error)
+	assert.Equal(t, -32005, responses[2].Error.Code)
+
+	// A single request above the limit gets the HTTP status 429.
+	code, blob = post(`{"jsonrpc": "2.0", "method": "c", "id": 4, "params": ["a", "10"]}`)
+	require.Equal(t, http.StatusTooManyRequests, code)
+	var response types.RPCResponse
+	require.NoError(t, json.Unmarshal(blob, &response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, -32005, response.Error.Code)
+	assert.Equal(t, types.JSONRPCIntID(4), response.ID)
+
+	// So does a URI request.
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/c?s=\"a\"&i=10", nil)
+	req.RemoteAddr = "1.2.3.4:1001"
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+}
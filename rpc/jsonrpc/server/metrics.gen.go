@@ -0,0 +1,53 @@
+// Code generated by metricsgen. DO NOT EDIT.
+
+package server
+
+import (
+	"github.com/cometbft/cometbft/v2/libs/metrics/discard"
+	prometheus "github.com/cometbft/cometbft/v2/libs/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		Requests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "requests",
+			Help:      "Number of requests received, per route.",
+		}, append(labels, "method")).With(labelsAndValues...),
+		RejectedRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rejected_requests",
+			Help:      "Number of requests rejected because a limit was exceeded, per route and reason (rate_limit or concurrency_limit).",
+		}, append(labels, "method", "reason")).With(labelsAndValues...),
+		InFlightCalls: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "in_flight_calls",
+			Help:      "Number of calls being served, per route.",
+		}, append(labels, "method")).With(labelsAndValues...),
+		RequestDurationSeconds: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "request_duration_seconds",
+			Help:      "Time taken to serve a request, per route.",
+
+			Buckets: stdprometheus.ExponentialBuckets(0.0002, 10, 5),
+		}, append(labels, "method")).With(labelsAndValues...),
+	}
+}
+
+func NopMetrics() *Metrics {
+	return &Metrics{
+		Requests:               discard.NewCounter(),
+		RejectedRequests:       discard.NewCounter(),
+		InFlightCalls:          discard.NewGauge(),
+		RequestDurationSeconds: discard.NewHistogram(),
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"github.com/cometbft/cometbft/v2/libs/metrics"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "rpc"
+)
+
+//go:generate go run ../../../scripts/metricsgen -struct=Metrics
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of requests received, per route.
+	Requests metrics.Counter `metrics_labels:"method"`
+
+	// Number of requests rejected because a limit was exceeded, per route and
+	// reason (rate_limit or concurrency_limit).
+	RejectedRequests metrics.Counter `metrics_labels:"method, reason"`
+
+	// Number of calls being served, per route.
+	InFlightCalls metrics.Gauge `metrics_labels:"method"`
+
+	// Time taken to serve a request, per route.
+	RequestDurationSeconds metrics.Histogram `metrics_bucketsizes:"0.0002, 10, 5" metrics_buckettype:"exp" metrics_labels:"method"`
+}
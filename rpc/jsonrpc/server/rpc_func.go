@@ -14,16 +14,23 @@ import (
 // interface on which the result objects are registered, and is popualted with
 // every RPCResponse.
 func RegisterRPCFuncs(mux *http.ServeMux, funcMap map[string]*RPCFunc, logger log.Logger) {
+	RegisterRPCFuncsWithLimiter(mux, funcMap, logger, nil)
+}
+
+// RegisterRPCFuncsWithLimiter is like RegisterRPCFuncs, but every call goes
+// through limiter, which rejects the requests exceeding its limits with a
+// RPCLimitExceededError. A nil limiter imposes no limit.
+func RegisterRPCFuncsWithLimiter(mux *http.ServeMux, funcMap map[string]*RPCFunc, logger log.Logger, limiter *Limiter) {
 	// HTTP endpoints
 	for funcName, rpcFunc := range funcMap {
-		mux.HandleFunc("/"+funcName, makeHTTPHandler(rpcFunc, logger))
-		mux.HandleFunc("/v1/"+funcName, makeHTTPHandler(rpcFunc, logger))
+		mux.HandleFunc("/"+funcName, makeHTTPHandler(funcName, rpcFunc, logger, limiter))
+		mux.HandleFunc("/v1/"+funcName, makeHTTPHandler(funcName, rpcFunc, logger, limiter))
 	}
 
 	// JSONRPC endpoints
-	mux.HandleFunc("/", handleInvalidJSONRPCPaths(makeJSONRPCHandler(funcMap, logger)))
-	mux.HandleFunc("/v1", handleInvalidJSONRPCPaths(makeJSONRPCHandler(funcMap, logger)))
-	mux.HandleFunc("/v1/", handleInvalidJSONRPCPaths(makeJSONRPCHandler(funcMap, logger)))
+	mux.HandleFunc("/", handleInvalidJSONRPCPaths(makeJSONRPCHandler(funcMap, logger, limiter)))
+	mux.HandleFunc("/v1", handleInvalidJSONRPCPaths(makeJSONRPCHandler(funcMap, logger, limiter)))
+	mux.HandleFunc("/v1/", handleInvalidJSONRPCPaths(makeJSONRPCHandler(funcMap, logger, limiter)))
 }
 
 type Option func(*RPCFunc)
@@ -98,6 +105,13 @@ func (f *RPCFunc) cacheableWithArgs(args []reflect.Value) bool {
 	return true
 }
 
+// call calls the function with args, then release, even if the function
+// panics.
+func (f *RPCFunc) call(args []reflect.Value, release func()) []reflect.Value {
+	defer release()
+	return f.f.Call(args)
+}
+
 func newRPCFunc(f any, args string, options ...Option) *RPCFunc {
 	var argNames []string
 	if args != "" {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/cometbft/cometbft/v2/libs/log"
 	"github.com/cometbft/cometbft/v2/libs/service"
+	"github.com/cometbft/cometbft/v2/rpc/auth"
 	"github.com/cometbft/cometbft/v2/rpc/jsonrpc/types"
 )
 
@@ -85,6 +86,8 @@ func (wm *WebsocketManager) WebsocketHandler(w http.ResponseWriter, r *http.Requ
 
 	// register connection
 	con := newWSConnection(wsConn, wm.funcMap, wm.wsConnOptions...)
+	// The upgrade request was authenticated by AuthHandler, if enabled.
+	con.grant, _ = auth.GrantFromContext(r.Context())
 	con.SetLogger(wm.logger.With("remote", wsConn.RemoteAddr()))
 	wm.logger.Info("New websocket connection", "remote", con.remoteAddr)
 	err = con.Start() // BLOCKING
@@ -135,6 +138,12 @@ type wsConnection struct {
 	// callback which is called upon disconnect
 	onDisconnect func(remoteAddr string)
 
+	// limits the calls of the client, if set
+	limiter *Limiter
+
+	// routes the client is allowed to call, if authenticated
+	grant *auth.Grant
+
 	ctx    context.Context
 	cancel context.CancelFunc
 }
@@ -216,6 +225,15 @@ func ReadLimit(readLimit int64) func(*wsConnection) {
 	}
 }
 
+// RequestLimiter sets the limiter through which every call goes, rejecting the
+// requests exceeding its limits. It should only be used in the constructor -
+// not Goroutine-safe.
+func RequestLimiter(limiter *Limiter) func(*wsConnection) {
+	return func(wsc *wsConnection) {
+		wsc.limiter = limiter
+	}
+}
+
 // OnStart implements service.Service by starting the read and write routines. It
 // blocks until there's some error.
 func (wsc *wsConnection) OnStart() error {
@@ -362,6 +380,15 @@ func (wsc *wsConnection) readRoutine() {
 				continue
 			}
 
+			if wsc.grant != nil {
+				if err := wsc.grant.Authorize(request.Method); err != nil {
+					if err := wsc.WriteRPCResponse(writeCtx, types.RPCForbiddenError(request.ID, err)); err != nil {
+						wsc.Logger.Error("Error writing RPC response", "err", err)
+					}
+					continue
+				}
+			}
+
 			ctx := &types.Context{JSONReq: &request, WSConn: wsc}
 			args := []reflect.Value{reflect.ValueOf(ctx)}
 			if len(request.Params) > 0 {
@@ -377,7 +404,14 @@ func (wsc *wsConnection) readRoutine() {
 				args = append(args, fnArgs...)
 			}
 
-			returns := rpcFunc.f.Call(args)
+			release, err := wsc.limiter.Acquire(wsc.remoteAddr, request.Method)
+			if err != nil {
+				if err := wsc.WriteRPCResponse(writeCtx, types.RPCLimitExceededError(request.ID, err)); err != nil {
+					wsc.Logger.Error("Error writing RPC response", "err", err)
+				}
+				continue
+			}
+			returns := rpcFunc.call(args, release)
 
 			// TODO: Need to encode args/returns to string if we want to log them
 			wsc.Logger.Debug("WSJSONRPC", "method", request.Method)
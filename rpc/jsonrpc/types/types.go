@@ -246,6 +246,25 @@ func RPCServerError(id jsonrpcid, err error) RPCResponse {
 	return NewRPCErrorResponse(id, -32000, "Server error", err.Error())
 }
 
+// RPCUnauthorizedError is returned when a request does not carry valid
+// credentials.
+func RPCUnauthorizedError(id jsonrpcid, err error) RPCResponse {
+	return NewRPCErrorResponse(id, -32001, "Unauthorized", err.Error())
+}
+
+// RPCForbiddenError is returned when the credentials of a request do not
+// allow calling the method.
+func RPCForbiddenError(id jsonrpcid, err error) RPCResponse {
+	return NewRPCErrorResponse(id, -32003, "Forbidden", err.Error())
+}
+
+// RPCLimitExceededError is returned when a request is rejected because the
+// client exceeded its rate limit, or too many calls to the route are being
+// served. The request may be retried later.
+func RPCLimitExceededError(id jsonrpcid, err error) RPCResponse {
+	return NewRPCErrorResponse(id, -32005, "Limit exceeded", err.Error())
+}
+
 // ----------------------------------------
 
 // WSRPCConnection represents a websocket connection.
@@ -0,0 +1,81 @@
+package state
+
+import (
+	"errors"
+
+	"github.com/cometbft/cometbft/v2/internal/compression"
+)
+
+// RecompressABCIResponses rewrites the ABCI responses stored in s which are
+// not compressed with the algorithm of s, see StoreOptions.Compression, in
+// batches of at most batchSize responses. Each batch is written atomically and
+// the responses already rewritten are skipped, so an interrupted run is resumed
+// by calling it again. It returns the number of rewritten responses.
+//
+// RecompressABCIResponses must not run concurrently with the pruning of the
+// ABCI responses.
+func RecompressABCIResponses(s Store, batchSize int) (int64, error) {
+	store, ok := s.(dbStore)
+	if !ok {
+		return 0, errors.New("recompressing ABCI responses requires a database store")
+	}
+	if batchSize <= 0 {
+		return 0, errors.New("batch size must be positive")
+	}
+
+	state, err := store.Load()
+	if err != nil {
+		return 0, err
+	}
+	if state.IsEmpty() {
+		return 0, nil
+	}
+	from, err := store.getLastABCIResponsesRetainHeight()
+	if err != nil {
+		return 0, err
+	}
+	from = max(from, state.InitialHeight)
+
+	var rewritten int64
+	batch := store.db.NewBatch()
+	defer func() {
+		_ = batch.Close()
+	}()
+	pending := 0
+	// The responses of the height after the state one are saved before the
+	// state is.
+	for h := from; h <= state.LastBlockHeight+1; h++ {
+		key := store.DBKeyLayout.CalcABCIResponsesKey(h)
+		bz, err := store.db.Get(key)
+		if err != nil {
+			return rewritten, err
+		}
+		if len(bz) == 0 {
+			continue
+		}
+		bz, changed, err := compression.Recode(store.Compression, bz)
+		if err != nil {
+			return rewritten, ErrABCIResponseCorruptedOrSpecChangeForHeight{Height: h, Err: err}
+		}
+		if !changed {
+			continue
+		}
+		if err := batch.Set(key, bz); err != nil {
+			return rewritten, err
+		}
+		pending++
+		if pending == batchSize {
+			if err := batch.WriteSync(); err != nil {
+				return rewritten, err
+			}
+			_ = batch.Close()
+			batch = store.db.NewBatch()
+			rewritten += int64(pending)
+			pending = 0
+		}
+	}
+	if err := batch.WriteSync(); err != nil {
+		return rewritten, err
+	}
+	return rewritten + int64(pending), nil
+}
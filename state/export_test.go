@@ -1,6 +1,8 @@
 package state
 
 import (
+	"time"
+
 	dbm "github.com/cometbft/cometbft-db"
 	abci "github.com/cometbft/cometbft/v2/abci/types"
 	"github.com/cometbft/cometbft/v2/types"
@@ -90,3 +92,7 @@ func Int64ToBytes(val int64) []byte {
 func Int64FromBytes(val []byte) int64 {
 	return int64FromBytes(val)
 }
+
+func (p *Pruner) FindTimeRetainHeight(retainTime time.Duration) int64 {
+	return p.findTimeRetainHeight(retainTime)
+}
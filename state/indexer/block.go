@@ -23,6 +23,11 @@ type BlockIndexer interface {
 	// event search criteria.
 	Search(ctx context.Context, q *query.Query) ([]int64, error)
 
+	// Count returns the number of block heights that match a given
+	// FinalizeBlock event search criteria and, if bucketSize is positive, their
+	// number in each range of bucketSize heights, without loading the blocks.
+	Count(ctx context.Context, q *query.Query, bucketSize int64) (int, []HeightBucket, error)
+
 	SetLogger(l log.Logger)
 
 	Prune(retainHeight int64) (int64, int64, error)
@@ -9,27 +9,63 @@ import (
 	"github.com/cometbft/cometbft/v2/state/indexer"
 	blockidxkv "github.com/cometbft/cometbft/v2/state/indexer/block/kv"
 	blockidxnull "github.com/cometbft/cometbft/v2/state/indexer/block/null"
+	"github.com/cometbft/cometbft/v2/state/indexer/sink/jsonl"
 	"github.com/cometbft/cometbft/v2/state/indexer/sink/psql"
+	"github.com/cometbft/cometbft/v2/state/indexer/sink/sqlite"
 	"github.com/cometbft/cometbft/v2/state/txindex"
 	"github.com/cometbft/cometbft/v2/state/txindex/kv"
 	"github.com/cometbft/cometbft/v2/state/txindex/null"
 )
 
 // IndexerFromConfig constructs a slice of indexer.EventSink using the provided
-// configuration.
+// configuration. When several indexers are configured, it constructs the
+// first one, which serves the RPC searches.
 func IndexerFromConfig(cfg *config.Config, dbProvider config.DBProvider, chainID string) (
 	txIdx txindex.TxIndexer, blockIdx indexer.BlockIndexer, allIndexersDisabled bool, err error,
 ) {
-	switch cfg.TxIndex.Indexer {
+	var name string
+	if indexers := cfg.TxIndex.Indexers(); len(indexers) > 0 {
+		name = indexers[0]
+	}
+	return indexerFromConfig(name, cfg, dbProvider, chainID)
+}
+
+// SinksFromConfig constructs the sinks of all the indexers of the
+// configuration, in order. The first one serves the RPC searches. It returns
+// no sinks if indexing is disabled.
+func SinksFromConfig(cfg *config.Config, dbProvider config.DBProvider, chainID string) ([]txindex.Sink, error) {
+	var sinks []txindex.Sink
+	for _, name := range cfg.TxIndex.Indexers() {
+		txIdx, blockIdx, disabled, err := indexerFromConfig(name, cfg, dbProvider, chainID)
+		if err != nil {
+			for _, s := range sinks {
+				s.TxIndexer.Close()
+			}
+			return nil, err
+		}
+		if disabled {
+			continue
+		}
+		sinks = append(sinks, txindex.Sink{Name: name, TxIndexer: txIdx, BlockIndexer: blockIdx})
+	}
+	return sinks, nil
+}
+
+func indexerFromConfig(name string, cfg *config.Config, dbProvider config.DBProvider, chainID string) (
+	txIdx txindex.TxIndexer, blockIdx indexer.BlockIndexer, allIndexersDisabled bool, err error,
+) {
+	switch name {
 	case "kv":
 		store, err := dbProvider(&config.DBContext{ID: "tx_index", Config: cfg})
 		if err != nil {
 			return nil, nil, false, err
 		}
 
-		return kv.NewTxIndex(store),
+		eventFilter := indexer.NewEventFilter(cfg.TxIndex.IndexEvents, cfg.TxIndex.ExcludeEvents)
+		return kv.NewTxIndex(store, kv.WithEventFilter(eventFilter)),
 			blockidxkv.New(dbm.NewPrefixDB(store, []byte("block_events")),
-				blockidxkv.WithCompaction(cfg.Storage.Compact, cfg.Storage.CompactionInterval)),
+				blockidxkv.WithCompaction(cfg.Storage.Compact, cfg.Storage.CompactionInterval),
+				blockidxkv.WithEventFilter(eventFilter)),
 			false,
 			nil
 
@@ -63,6 +99,20 @@ func IndexerFromConfig(cfg *config.Config, dbProvider config.DBProvider, chainID
 		}
 		return es.TxIndexer(), es.BlockIndexer(), false, nil
 
+	case "sqlite":
+		es, err := sqlite.NewEventSink(cfg.TxIndex.SqliteFile(), chainID)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("creating sqlite indexer: %w", err)
+		}
+		return es.TxIndexer(), es.BlockIndexer(), false, nil
+
+	case "jsonl":
+		es, err := jsonl.NewEventSink(cfg.TxIndex.JSONLDirPath(), chainID, cfg.TxIndex.JSONLFileHeights)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("creating jsonl indexer: %w", err)
+		}
+		return es.TxIndexer(), es.BlockIndexer(), false, nil
+
 	default:
 		return &null.TxIndex{}, &blockidxnull.BlockerIndexer{}, true, nil
 	}
@@ -1,5 +1,5 @@
 package kv
 
-func GetKeys(indexer BlockerIndexer) [][]byte {
+func GetKeys(indexer *BlockerIndexer) [][]byte {
 	return getKeys(indexer)
 }
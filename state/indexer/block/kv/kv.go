@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/google/orderedcode"
 
@@ -37,15 +38,26 @@ type BlockerIndexer struct {
 
 	// Add unique event identifier to use when querying
 	// Matching will be done both on height AND eventSeq
-	eventSeq int64
+	eventSeq atomic.Int64
 	log      log.Logger
 
 	compact            bool
 	compactionInterval int64
 	lastPruned         int64
+
+	// Selects the types of the events indexed, all if nil
+	eventFilter *indexer.EventFilter
 }
 type IndexerOption func(*BlockerIndexer)
 
+// WithEventFilter makes the indexer index only the events selected by f,
+// besides the height of the blocks, which are always indexed.
+func WithEventFilter(f *indexer.EventFilter) IndexerOption {
+	return func(idx *BlockerIndexer) {
+		idx.eventFilter = f
+	}
+}
+
 // WithCompaction sets the compaction parameters.
 func WithCompaction(compact bool, compactionInterval int64) IndexerOption {
 	return func(idx *BlockerIndexer) {
@@ -108,7 +120,7 @@ func (idx *BlockerIndexer) Index(bh types.EventDataNewBlockEvents) error {
 	return batch.WriteSync()
 }
 
-func getKeys(indexer BlockerIndexer) [][]byte {
+func getKeys(indexer *BlockerIndexer) [][]byte {
 	var keys [][]byte
 
 	itr, err := indexer.store.Iterator(nil, nil)
@@ -257,8 +269,64 @@ func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64,
 	default:
 	}
 
-	conditions := q.Syntax()
+	var (
+		filteredHeights map[string][]byte
+		err             error
+	)
+	if conditions := q.Syntax(); conditions != nil {
+		filteredHeights, err = idx.searchConditions(ctx, conditions)
+	} else {
+		filteredHeights, err = idx.searchDisjunction(ctx, q)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// fetch matching heights
+	results = make([]int64, 0, len(filteredHeights))
+	resultMap := make(map[int64]struct{})
+FOR_LOOP:
+	for _, hBz := range filteredHeights {
+		h := int64FromBytes(hBz)
+
+		ok, err := idx.Has(h)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if _, ok := resultMap[h]; !ok {
+				resultMap[h] = struct{}{}
+				results = append(results, h)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			break FOR_LOOP
+		default:
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i] < results[j] })
+
+	return results, nil
+}
+
+// Count returns the number of block heights that match a given FinalizeBlock
+// event search criteria and, if bucketSize is positive, their number in each
+// range of bucketSize heights. As the index only stores heights, it amounts
+// to a Search.
+func (idx *BlockerIndexer) Count(ctx context.Context, q *query.Query, bucketSize int64) (int, []indexer.HeightBucket, error) {
+	heights, err := idx.Search(ctx, q)
+	if err != nil {
+		return 0, nil, err
+	}
+	return len(heights), indexer.CountByHeight(heights, bucketSize), nil
+}
 
+// searchConditions returns the heights matching all the given conditions,
+// keyed by a string unique for each of them.
+func (idx *BlockerIndexer) searchConditions(ctx context.Context, conditions []syntax.Condition) (map[string][]byte, error) {
 	// conditions to skip because they're handled before "everything else"
 	skipIndexes := make([]int, 0)
 
@@ -287,10 +355,10 @@ func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64,
 		}
 
 		if ok {
-			return []int64{heightInfo.height}, nil
+			return map[string][]byte{string(int64ToBytes(heightInfo.height)): int64ToBytes(heightInfo.height)}, nil
 		}
 
-		return results, nil
+		return nil, nil
 	}
 
 	var heightsInitialized bool
@@ -374,33 +442,45 @@ func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64,
 		}
 	}
 
-	// fetch matching heights
-	results = make([]int64, 0, len(filteredHeights))
-	resultMap := make(map[int64]struct{})
-FOR_LOOP:
-	for _, hBz := range filteredHeights {
-		h := int64FromBytes(hBz)
+	return filteredHeights, nil
+}
 
-		ok, err := idx.Has(h)
+// searchDisjunction returns the heights matching q, which is not a conjunction
+// of conditions, as the union of the heights matching each of the conjunctions
+// of its disjunctive normal form. The heights matching the negated conditions
+// of a conjunction are looked up in the index and excluded.
+func (idx *BlockerIndexer) searchDisjunction(ctx context.Context, q *query.Query) (map[string][]byte, error) {
+	conjunctions, err := indexer.Disjunction(q)
+	if err != nil {
+		return nil, err
+	}
+	// The results are keyed by height, as each height may match several
+	// conjunctions.
+	results := make(map[string][]byte)
+	for _, conj := range conjunctions {
+		matches, err := idx.searchConditions(ctx, conj.Conditions)
 		if err != nil {
 			return nil, err
 		}
-		if ok {
-			if _, ok := resultMap[h]; !ok {
-				resultMap[h] = struct{}{}
-				results = append(results, h)
+		if len(matches) == 0 {
+			continue
+		}
+		excluded := make(map[string]struct{})
+		for _, c := range conj.Negated {
+			negMatches, err := idx.searchConditions(ctx, []syntax.Condition{c})
+			if err != nil {
+				return nil, err
+			}
+			for _, hBz := range negMatches {
+				excluded[string(hBz)] = struct{}{}
 			}
 		}
-
-		select {
-		case <-ctx.Done():
-			break FOR_LOOP
-		default:
+		for _, hBz := range matches {
+			if _, ok := excluded[string(hBz)]; !ok {
+				results[string(hBz)] = hBz
+			}
 		}
 	}
-
-	sort.Slice(results, func(i, j int) bool { return results[i] < results[j] })
-
 	return results, nil
 }
 
@@ -735,9 +815,11 @@ func (idx *BlockerIndexer) indexEvents(batch dbm.Batch, events []abci.Event, hei
 	heightBz := int64ToBytes(height)
 
 	for _, event := range events {
-		idx.eventSeq++
-		// only index events with a non-empty type
-		if len(event.Type) == 0 {
+		// The sequence number is read once, as the events of several heights
+		// may be indexed concurrently, e.g. by reindex-event.
+		eventSeq := idx.eventSeq.Add(1)
+		// only index events with a non-empty type, selected by the filter
+		if len(event.Type) == 0 || !idx.eventFilter.Indexed(event.Type) {
 			continue
 		}
 
@@ -753,7 +835,7 @@ func (idx *BlockerIndexer) indexEvents(batch dbm.Batch, events []abci.Event, hei
 			}
 
 			if attr.GetIndex() {
-				key, err := eventKey(compositeKey, attr.Value, height, idx.eventSeq)
+				key, err := eventKey(compositeKey, attr.Value, height, eventSeq)
 				if err != nil {
 					return fmt.Errorf("failed to create block index key: %w", err)
 				}
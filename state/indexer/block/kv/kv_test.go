@@ -16,6 +16,7 @@ import (
 	abci "github.com/cometbft/cometbft/v2/abci/types"
 	"github.com/cometbft/cometbft/v2/internal/test"
 	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	"github.com/cometbft/cometbft/v2/state/indexer"
 	blockidxkv "github.com/cometbft/cometbft/v2/state/indexer/block/kv"
 	"github.com/cometbft/cometbft/v2/state/txindex/kv"
 	"github.com/cometbft/cometbft/v2/types"
@@ -38,12 +39,12 @@ func TestBlockerIndexer_Prune(t *testing.T) {
 	err := indexer.Index(events1)
 	require.NoError(t, err)
 
-	keys1 := blockidxkv.GetKeys(*indexer)
+	keys1 := blockidxkv.GetKeys(indexer)
 
 	err = indexer.Index(events2)
 	require.NoError(t, err)
 
-	keys2 := blockidxkv.GetKeys(*indexer)
+	keys2 := blockidxkv.GetKeys(indexer)
 
 	require.True(t, isSubset(keys1, keys2))
 
@@ -52,7 +53,7 @@ func TestBlockerIndexer_Prune(t *testing.T) {
 	require.Equal(t, int64(1), numPruned)
 	require.Equal(t, int64(2), retainedHeight)
 
-	keys3 := blockidxkv.GetKeys(*indexer)
+	keys3 := blockidxkv.GetKeys(indexer)
 	require.True(t, isEqualSets(setDiff(keys2, keys1), setDiff(keys3, metaKeys)))
 	require.True(t, emptyIntersection(keys1, keys3))
 }
@@ -204,6 +205,22 @@ func TestBlockIndexer(t *testing.T) {
 			q:       query.MustCompile("end_event.foo CONTAINS '1'"),
 			results: []int64{1, 10},
 		},
+		"end_event.foo = 2 OR end_event.foo = 100": {
+			q:       query.MustCompile("end_event.foo = 2 OR end_event.foo = 100"),
+			results: []int64{1, 2},
+		},
+		"block.height = 3 OR block.height = 5": {
+			q:       query.MustCompile("block.height = 3 OR block.height = 5"),
+			results: []int64{3, 5},
+		},
+		"begin_event.proposer = 'FCAA001' AND NOT end_event.foo <= 5": {
+			q:       query.MustCompile("begin_event.proposer = 'FCAA001' AND NOT end_event.foo <= 5"),
+			results: []int64{1, 3, 5, 6, 7, 8, 9, 10, 11},
+		},
+		"(block.height < 4 OR end_event.foo >= 10) AND NOT block.height = 2": {
+			q:       query.MustCompile("(block.height < 4 OR end_event.foo >= 10) AND NOT block.height = 2"),
+			results: []int64{1, 3, 10},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -213,6 +230,49 @@ func TestBlockIndexer(t *testing.T) {
 			require.Equal(t, tc.results, results)
 		})
 	}
+
+	// A query that can only be answered by scanning all the blocks is
+	// rejected.
+	_, err := indexer.Search(context.Background(), query.MustCompile("NOT end_event.foo = 2"))
+	require.Error(t, err)
+
+	total, buckets, err := indexer.Count(context.Background(),
+		query.MustCompile("begin_event.proposer = 'FCAA001' AND NOT end_event.foo <= 5"), 4)
+	require.NoError(t, err)
+	require.Equal(t, 9, total)
+	counts := make([]string, 0, len(buckets))
+	for _, b := range buckets {
+		counts = append(counts, fmt.Sprintf("%d-%d:%d", b.FromHeight, b.ToHeight, b.Count))
+	}
+	require.Equal(t, []string{"1-4:2", "5-8:4", "9-12:3"}, counts)
+
+	total, buckets, err = indexer.Count(context.Background(), query.MustCompile("end_event.foo = 2 OR end_event.foo = 100"), 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Nil(t, buckets)
+}
+
+func TestBlockIndexerEventFilter(t *testing.T) {
+	store := db.NewPrefixDB(db.NewMemDB(), []byte("block_events"))
+	blockIndexer := blockidxkv.New(store, blockidxkv.WithEventFilter(indexer.NewEventFilter([]string{"begin_event"}, nil)))
+
+	require.NoError(t, blockIndexer.Index(types.EventDataNewBlockEvents{
+		Height: 1,
+		Events: []abci.Event{
+			{Type: "begin_event", Attributes: []abci.EventAttribute{{Key: "proposer", Value: "FCAA001", Index: true}}},
+			{Type: "end_event", Attributes: []abci.EventAttribute{{Key: "foo", Value: "100", Index: true}}},
+		},
+	}))
+
+	for q, heights := range map[string][]int64{
+		"begin_event.proposer = 'FCAA001'": {1},
+		"end_event.foo = 100":              {},
+		"block.height = 1":                 {1},
+	} {
+		results, err := blockIndexer.Search(context.Background(), query.MustCompile(q))
+		require.NoError(t, err)
+		require.ElementsMatch(t, heights, results, q)
+	}
 }
 
 func TestBlockIndexerMulti(t *testing.T) {
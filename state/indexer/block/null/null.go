@@ -39,5 +39,9 @@ func (*BlockerIndexer) Search(context.Context, *query.Query) ([]int64, error) {
 	return []int64{}, nil
 }
 
+func (*BlockerIndexer) Count(context.Context, *query.Query, int64) (int, []indexer.HeightBucket, error) {
+	return 0, nil, nil
+}
+
 func (*BlockerIndexer) SetLogger(log.Logger) {
 }
@@ -0,0 +1,38 @@
+package indexer
+
+import "sort"
+
+// HeightBucket is the number of results of a search in the range of heights
+// [FromHeight, ToHeight].
+type HeightBucket struct {
+	FromHeight int64
+	ToHeight   int64
+	Count      int
+}
+
+// BucketOf returns the first and last heights of the bucket of bucketSize
+// heights containing height. The buckets start at height 1.
+func BucketOf(height, bucketSize int64) (fromHeight, toHeight int64) {
+	fromHeight = (height-1)/bucketSize*bucketSize + 1
+	return fromHeight, fromHeight + bucketSize - 1
+}
+
+// CountByHeight returns the number of the given heights in each bucket of
+// bucketSize heights, in ascending order of heights. Empty buckets are
+// omitted. It returns nil if bucketSize is not positive.
+func CountByHeight(heights []int64, bucketSize int64) []HeightBucket {
+	if bucketSize <= 0 {
+		return nil
+	}
+	counts := make(map[int64]int)
+	for _, h := range heights {
+		from, _ := BucketOf(h, bucketSize)
+		counts[from]++
+	}
+	buckets := make([]HeightBucket, 0, len(counts))
+	for from, count := range counts {
+		buckets = append(buckets, HeightBucket{FromHeight: from, ToHeight: from + bucketSize - 1, Count: count})
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].FromHeight < buckets[j].FromHeight })
+	return buckets
+}
@@ -0,0 +1,106 @@
+package indexer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	"github.com/cometbft/cometbft/v2/libs/pubsub/query/syntax"
+)
+
+// MaxConjunctions is the maximum number of conjunctions of the disjunctive
+// normal form of a query which the indexers evaluate.
+const MaxConjunctions = 64
+
+// ErrNegatedConjunction is returned for a query which is not evaluated without
+// scanning the whole index, because one of its alternatives only has negated
+// conditions, e.g. "NOT a.b = 1".
+var ErrNegatedConjunction = errors.New("each alternative of the query must have a condition which is not negated")
+
+// A Conjunction is one of the alternatives of the disjunctive normal form of a
+// query: it matches the results which match all its Conditions and none of
+// its Negated conditions.
+type Conjunction struct {
+	Conditions []syntax.Condition
+	Negated    []syntax.Condition
+}
+
+// Disjunction returns the disjunctive normal form of q, that is the list of
+// conjunctions of which the results of q are the union. It returns an error
+// if the form has more than MaxConjunctions conjunctions, or a conjunction
+// with only negated conditions.
+func Disjunction(q *query.Query) ([]Conjunction, error) {
+	conjs, err := DisjunctiveNormalForm(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, conj := range conjs {
+		if len(conj.Conditions) == 0 {
+			return nil, ErrNegatedConjunction
+		}
+	}
+	return conjs, nil
+}
+
+// DisjunctiveNormalForm is like Disjunction, but accepts conjunctions with
+// only negated conditions, for the indexers able to evaluate them.
+func DisjunctiveNormalForm(q *query.Query) ([]Conjunction, error) {
+	if conds := q.Syntax(); conds != nil {
+		return []Conjunction{{Conditions: conds}}, nil
+	}
+	return disjunction(q.Expr(), false)
+}
+
+// disjunction returns the disjunctive normal form of expr, or of its negation
+// if negate is true.
+func disjunction(expr *syntax.Expr, negate bool) ([]Conjunction, error) {
+	if expr.Cond != nil {
+		if negate {
+			return []Conjunction{{Negated: []syntax.Condition{*expr.Cond}}}, nil
+		}
+		return []Conjunction{{Conditions: []syntax.Condition{*expr.Cond}}}, nil
+	}
+	op := expr.Op
+	if op == syntax.TNot {
+		return disjunction(expr.Args[0], !negate)
+	}
+	// By De Morgan's laws, the negation of a conjunction is the disjunction of
+	// the negations, and vice versa.
+	if negate {
+		if op == syntax.TAnd {
+			op = syntax.TOr
+		} else {
+			op = syntax.TAnd
+		}
+	}
+
+	var conjs []Conjunction
+	for i, arg := range expr.Args {
+		argConjs, err := disjunction(arg, negate)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case i == 0:
+			conjs = argConjs
+		case op == syntax.TOr:
+			conjs = append(conjs, argConjs...)
+		default:
+			// Distribute the conjunction over the disjunctions.
+			product := make([]Conjunction, 0, len(conjs)*len(argConjs))
+			for _, c := range conjs {
+				for _, a := range argConjs {
+					product = append(product, Conjunction{
+						Conditions: append(append([]syntax.Condition(nil), c.Conditions...), a.Conditions...),
+						Negated:    append(append([]syntax.Condition(nil), c.Negated...), a.Negated...),
+					})
+				}
+			}
+			conjs = product
+		}
+		if len(conjs) > MaxConjunctions {
+			return nil, fmt.Errorf("query has more than %d alternatives", MaxConjunctions)
+		}
+	}
+	return conjs, nil
+}
@@ -0,0 +1,36 @@
+package indexer
+
+// EventFilter selects, by their type, the events an indexer indexes. A nil
+// filter indexes all the events.
+type EventFilter struct {
+	include map[string]bool
+	exclude map[string]bool
+}
+
+// NewEventFilter returns a filter indexing the events of the types in include,
+// or of any type if include is empty, except the types in exclude. It returns
+// nil if both are empty.
+func NewEventFilter(include, exclude []string) *EventFilter {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil
+	}
+	f := &EventFilter{include: make(map[string]bool), exclude: make(map[string]bool)}
+	for _, t := range include {
+		f.include[t] = true
+	}
+	for _, t := range exclude {
+		f.exclude[t] = true
+	}
+	return f
+}
+
+// Indexed reports whether the events of type eventType are indexed.
+func (f *EventFilter) Indexed(eventType string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.include) > 0 && !f.include[eventType] {
+		return false
+	}
+	return !f.exclude[eventType]
+}
@@ -0,0 +1,28 @@
+package indexer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/v2/state/indexer"
+)
+
+func TestEventFilter(t *testing.T) {
+	testCases := []struct {
+		include, exclude []string
+		indexed          map[string]bool
+	}{
+		{nil, nil, map[string]bool{"transfer": true, "message": true}},
+		{[]string{"transfer"}, nil, map[string]bool{"transfer": true, "message": false}},
+		{nil, []string{"message"}, map[string]bool{"transfer": true, "message": false}},
+		{[]string{"transfer", "message"}, []string{"message"}, map[string]bool{"transfer": true, "message": false, "coin": false}},
+	}
+
+	for _, tc := range testCases {
+		f := indexer.NewEventFilter(tc.include, tc.exclude)
+		for eventType, indexed := range tc.indexed {
+			require.Equal(t, indexed, f.Indexed(eventType), "include %v, exclude %v, type %s", tc.include, tc.exclude, eventType)
+		}
+	}
+}
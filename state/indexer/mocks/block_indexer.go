@@ -9,6 +9,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	indexer "github.com/cometbft/cometbft/v2/state/indexer"
+
 	query "github.com/cometbft/cometbft/v2/libs/pubsub/query"
 
 	types "github.com/cometbft/cometbft/v2/types"
@@ -19,6 +21,43 @@ type BlockIndexer struct {
 	mock.Mock
 }
 
+// Count provides a mock function with given fields: ctx, q, bucketSize
+func (_m *BlockIndexer) Count(ctx context.Context, q *query.Query, bucketSize int64) (int, []indexer.HeightBucket, error) {
+	ret := _m.Called(ctx, q, bucketSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int
+	var r1 []indexer.HeightBucket
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *query.Query, int64) (int, []indexer.HeightBucket, error)); ok {
+		return rf(ctx, q, bucketSize)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *query.Query, int64) int); ok {
+		r0 = rf(ctx, q, bucketSize)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *query.Query, int64) []indexer.HeightBucket); ok {
+		r1 = rf(ctx, q, bucketSize)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]indexer.HeightBucket)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *query.Query, int64) error); ok {
+		r2 = rf(ctx, q, bucketSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetRetainHeight provides a mock function with no fields
 func (_m *BlockIndexer) GetRetainHeight() (int64, error) {
 	ret := _m.Called()
@@ -1,9 +1,11 @@
 package indexer
 
 import (
+	"fmt"
 	"math/big"
 	"time"
 
+	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
 	"github.com/cometbft/cometbft/v2/libs/pubsub/query/syntax"
 	"github.com/cometbft/cometbft/v2/types"
 )
@@ -97,43 +99,13 @@ func LookForRangesWithHeight(conditions []syntax.Condition) (queryRange QueryRan
 	queryRange = make(QueryRanges)
 	for i, c := range conditions {
 		if IsRangeOperation(c.Op) {
-			heightKey := c.Tag == types.BlockHeightKey || c.Tag == types.TxHeightKey
 			r, ok := queryRange[c.Tag]
 			if !ok {
 				r = QueryRange{Key: c.Tag}
-				if c.Tag == types.BlockHeightKey || c.Tag == types.TxHeightKey {
-					heightRange = QueryRange{Key: c.Tag}
-				}
 			}
-
-			switch c.Op {
-			case syntax.TGt:
-				if heightKey {
-					heightRange.LowerBound = conditionArg(c)
-				}
-				r.LowerBound = conditionArg(c)
-
-			case syntax.TGeq:
-				r.IncludeLowerBound = true
-				r.LowerBound = conditionArg(c)
-				if heightKey {
-					heightRange.IncludeLowerBound = true
-					heightRange.LowerBound = conditionArg(c)
-				}
-
-			case syntax.TLt:
-				r.UpperBound = conditionArg(c)
-				if heightKey {
-					heightRange.UpperBound = conditionArg(c)
-				}
-
-			case syntax.TLeq:
-				r.IncludeUpperBound = true
-				r.UpperBound = conditionArg(c)
-				if heightKey {
-					heightRange.IncludeUpperBound = true
-					heightRange.UpperBound = conditionArg(c)
-				}
+			r.restrict(c)
+			if c.Tag == types.BlockHeightKey || c.Tag == types.TxHeightKey {
+				heightRange = r
 			}
 
 			queryRange[c.Tag] = r
@@ -178,6 +150,44 @@ func LookForRanges(conditions []syntax.Condition) (ranges QueryRanges, indexes [
 	return ranges, indexes
 }
 
+// restrict narrows the range to the values matching the range condition c.
+// A bound is only replaced by a tighter one, unless they cannot be compared,
+// so that several conditions on a key restrict it to their intersection.
+func (qr *QueryRange) restrict(c syntax.Condition) {
+	arg := conditionArg(c)
+	switch c.Op {
+	case syntax.TGt, syntax.TGeq:
+		incl := c.Op == syntax.TGeq
+		cmp, ok := compareBounds(arg, qr.LowerBound)
+		if !ok || cmp > 0 || (cmp == 0 && !incl) {
+			qr.LowerBound, qr.IncludeLowerBound = arg, incl
+		}
+
+	case syntax.TLt, syntax.TLeq:
+		incl := c.Op == syntax.TLeq
+		cmp, ok := compareBounds(arg, qr.UpperBound)
+		if !ok || cmp < 0 || (cmp == 0 && !incl) {
+			qr.UpperBound, qr.IncludeUpperBound = arg, incl
+		}
+	}
+}
+
+// compareBounds compares the bounds a and b of a range, and reports whether
+// they are comparable, which they are not if either is nil.
+func compareBounds(a, b any) (int, bool) {
+	switch a := a.(type) {
+	case *big.Float:
+		if b, ok := b.(*big.Float); ok && a != nil && b != nil {
+			return a.Cmp(b), true
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b), true
+		}
+	}
+	return 0, false
+}
+
 // IsRangeOperation returns a boolean signifying if a query Operator is a range
 // operation or not.
 func IsRangeOperation(op syntax.Token) bool {
@@ -203,3 +213,32 @@ func conditionArg(c syntax.Condition) any {
 		return c.Arg.Value() // string
 	}
 }
+
+// QueryFromHeight returns q restricted to the results at heights from height,
+// or up to height if desc is true, where heightKey is the key of heights, to
+// resume a search from the given height. As the indexers do not combine an
+// equality on the height with a range over it, q is returned unchanged if it
+// has such an equality.
+func QueryFromHeight(q *query.Query, heightKey string, height int64, desc bool) (*query.Query, error) {
+	conjs, err := DisjunctiveNormalForm(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, conj := range conjs {
+		for _, c := range conj.Conditions {
+			if c.Tag == heightKey && c.Op == syntax.TEq {
+				return q, nil
+			}
+		}
+	}
+
+	op := ">="
+	if desc {
+		op = "<="
+	}
+	conds, err := syntax.Parse(fmt.Sprintf("%s %s %d", heightKey, op, height))
+	if err != nil {
+		return nil, err
+	}
+	return query.CompileExpr(syntax.NewExpr(syntax.TAnd, q.Expr(), syntax.NewConditionExpr(conds[0])))
+}
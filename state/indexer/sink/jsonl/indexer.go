@@ -0,0 +1,139 @@
+package jsonl
+
+import (
+	"context"
+	"errors"
+
+	abci "github.com/cometbft/cometbft/v2/abci/types"
+	"github.com/cometbft/cometbft/v2/libs/log"
+	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	"github.com/cometbft/cometbft/v2/state/indexer"
+	"github.com/cometbft/cometbft/v2/state/txindex"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// TxIndexer returns the transaction indexer backed by es.
+func (es *EventSink) TxIndexer() TxIndexer {
+	return TxIndexer{jsonl: es}
+}
+
+// TxIndexer implements the txindex.TxIndexer interface by writing the
+// transaction results to an underlying JSON lines event sink.
+type TxIndexer struct{ jsonl *EventSink }
+
+var _ txindex.TxIndexer = TxIndexer{}
+
+// GetRetainHeight returns the retain height set by the pruning service, as
+// part of TxIndexer.
+func (b TxIndexer) GetRetainHeight() (int64, error) {
+	return b.jsonl.getRetainHeight(txIndexerRetainHeight)
+}
+
+// SetRetainHeight sets the retain height, as part of TxIndexer.
+func (b TxIndexer) SetRetainHeight(retainHeight int64) error {
+	return b.jsonl.setRetainHeight(txIndexerRetainHeight, retainHeight)
+}
+
+// Prune prunes the transactions below retainHeight, as part of TxIndexer. The
+// number pruned is the number of files removed.
+func (b TxIndexer) Prune(retainHeight int64) (numPruned, newRetainHeight int64, err error) {
+	numPruned, err = b.jsonl.PruneTxEvents(retainHeight)
+	if err != nil {
+		return 0, 0, err
+	}
+	return numPruned, retainHeight, nil
+}
+
+// AddBatch writes a batch of transactions, as part of TxIndexer.
+func (b TxIndexer) AddBatch(batch *txindex.Batch) error {
+	return b.jsonl.IndexTxEvents(batch.Ops)
+}
+
+// Index writes a single transaction result, as part of TxIndexer.
+func (b TxIndexer) Index(txr *abci.TxResult) error {
+	return b.jsonl.IndexTxEvents([]*abci.TxResult{txr})
+}
+
+// Get is implemented to satisfy the TxIndexer interface, but is not supported
+// by the jsonl event sink and reports an error for all inputs.
+func (TxIndexer) Get([]byte) (*abci.TxResult, error) {
+	return nil, errors.New("the TxIndexer.Get method is not supported")
+}
+
+// Search is implemented to satisfy the TxIndexer interface, but it is not
+// supported by the jsonl event sink and reports an error for all inputs.
+func (TxIndexer) Search(context.Context, *query.Query, txindex.Pagination) ([]*abci.TxResult, int, error) {
+	return nil, 0, errors.New("the TxIndexer.Search method is not supported")
+}
+
+// Count is implemented to satisfy the TxIndexer interface, but it is not
+// supported by the jsonl event sink and reports an error for all inputs.
+func (TxIndexer) Count(context.Context, *query.Query, int64) (int, []indexer.HeightBucket, error) {
+	return 0, nil, errors.New("the TxIndexer.Count method is not supported")
+}
+
+func (TxIndexer) SetLogger(log.Logger) {}
+
+// Close closes the file the sink writes to. The caller is responsible for
+// calling Close when done with the indexer.
+func (b TxIndexer) Close() error {
+	return b.jsonl.Stop()
+}
+
+// BlockIndexer returns the block indexer backed by es.
+func (es *EventSink) BlockIndexer() BlockIndexer {
+	return BlockIndexer{jsonl: es}
+}
+
+// BlockIndexer implements the indexer.BlockIndexer interface by writing the
+// block events to an underlying JSON lines event sink.
+type BlockIndexer struct{ jsonl *EventSink }
+
+var _ indexer.BlockIndexer = BlockIndexer{}
+
+// GetRetainHeight returns the retain height set by the pruning service, as
+// part of BlockIndexer.
+func (b BlockIndexer) GetRetainHeight() (int64, error) {
+	return b.jsonl.getRetainHeight(blockIndexerRetainHeight)
+}
+
+// SetRetainHeight sets the retain height, as part of BlockIndexer.
+func (b BlockIndexer) SetRetainHeight(retainHeight int64) error {
+	return b.jsonl.setRetainHeight(blockIndexerRetainHeight, retainHeight)
+}
+
+// Prune prunes the events of the blocks below retainHeight, as part of
+// BlockIndexer. The number pruned is the number of files removed.
+func (b BlockIndexer) Prune(retainHeight int64) (numPruned, newRetainHeight int64, err error) {
+	numPruned, err = b.jsonl.PruneBlockEvents(retainHeight)
+	if err != nil {
+		return 0, 0, err
+	}
+	return numPruned, retainHeight, nil
+}
+
+// Has is implemented to satisfy the BlockIndexer interface, but it is not
+// supported by the jsonl event sink and reports an error for all inputs.
+func (BlockIndexer) Has(int64) (bool, error) {
+	return false, errors.New("the BlockIndexer.Has method is not supported")
+}
+
+// Index writes the events of the specified block. It is part of the
+// BlockIndexer interface.
+func (b BlockIndexer) Index(block types.EventDataNewBlockEvents) error {
+	return b.jsonl.IndexBlockEvents(block)
+}
+
+// Search is implemented to satisfy the BlockIndexer interface, but it is not
+// supported by the jsonl event sink and reports an error for all inputs.
+func (BlockIndexer) Search(context.Context, *query.Query) ([]int64, error) {
+	return nil, errors.New("the BlockIndexer.Search method is not supported")
+}
+
+// Count is implemented to satisfy the BlockIndexer interface, but it is not
+// supported by the jsonl event sink and reports an error for all inputs.
+func (BlockIndexer) Count(context.Context, *query.Query, int64) (int, []indexer.HeightBucket, error) {
+	return 0, nil, errors.New("the BlockIndexer.Count method is not supported")
+}
+
+func (BlockIndexer) SetLogger(log.Logger) {}
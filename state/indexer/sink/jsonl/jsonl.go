@@ -0,0 +1,333 @@
+// Package jsonl implements an event sink writing the events of the blocks and
+// the transaction results as JSON lines into files rotated by height range,
+// for offline analytics.
+package jsonl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	abci "github.com/cometbft/cometbft/v2/abci/types"
+	"github.com/cometbft/cometbft/v2/internal/autofile"
+	"github.com/cometbft/cometbft/v2/internal/tempfile"
+	"github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/state/indexer"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// IndexFile is the name of the file, in the directory of the sink, listing the
+// files of the sink and the heights they hold.
+const IndexFile = "index.json"
+
+// Types of the lines written to the files.
+const (
+	LineTypeBlock = "block"
+	LineTypeTx    = "tx"
+)
+
+// BlockLine is the line written for the events of a block.
+type BlockLine struct {
+	Type    string       `json:"type"`
+	ChainID string       `json:"chain_id"`
+	Height  int64        `json:"height"`
+	NumTxs  int64        `json:"num_txs"`
+	Events  []abci.Event `json:"events"`
+}
+
+// TxLine is the line written for a transaction result.
+type TxLine struct {
+	Type    string            `json:"type"`
+	ChainID string            `json:"chain_id"`
+	Height  int64             `json:"height"`
+	Index   uint32            `json:"index"`
+	Hash    string            `json:"hash"`
+	Tx      []byte            `json:"tx"`
+	Result  abci.ExecTxResult `json:"result"`
+}
+
+// File is a file of the sink, holding the lines of the heights from
+// FromHeight to ToHeight.
+type File struct {
+	Name       string `json:"name"`
+	FromHeight int64  `json:"from_height"`
+	ToHeight   int64  `json:"to_height"`
+}
+
+// index is the content of the index file.
+type index struct {
+	Files []File `json:"files"`
+
+	// The retain heights set by the pruner, and the heights below which the
+	// lines were pruned. A file is removed once all its heights are pruned
+	// for both the transactions and the blocks.
+	TxRetainHeight    *int64 `json:"tx_retain_height,omitempty"`
+	BlockRetainHeight *int64 `json:"block_retain_height,omitempty"`
+	TxPrunedHeight    int64  `json:"tx_pruned_height"`
+	BlockPrunedHeight int64  `json:"block_pruned_height"`
+}
+
+// EventSink is an indexer backend writing the events to JSON lines files in a
+// directory. Each file holds the lines of fileHeights consecutive heights, in
+// the order they are indexed, and the index file of the directory lists them.
+type EventSink struct {
+	mtx         sync.Mutex
+	dir         string
+	fileHeights int64
+	chainID     string
+	index       index
+
+	// The file of the last height written, kept open.
+	head     *autofile.AutoFile
+	headFile File
+}
+
+// NewEventSink constructs an event sink writing to the files in dir, created if
+// it does not exist, each holding fileHeights heights. Events written to the
+// sink are attributed to the specified chainID.
+func NewEventSink(dir, chainID string, fileHeights int64) (*EventSink, error) {
+	if fileHeights <= 0 {
+		return nil, errors.New("the number of heights per file must be positive")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating directory of the jsonl files: %w", err)
+	}
+	es := &EventSink{dir: dir, fileHeights: fileHeights, chainID: chainID}
+	bz, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(bz, &es.index); err != nil {
+			return nil, fmt.Errorf("decoding the jsonl index: %w", err)
+		}
+	}
+	return es, nil
+}
+
+// Dir returns the directory of the files of the sink.
+func (es *EventSink) Dir() string { return es.dir }
+
+// Files returns the files of the sink, in ascending order of heights.
+func (es *EventSink) Files() []File {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	return append([]File(nil), es.index.Files...)
+}
+
+// IndexBlockEvents writes a line for the events of the block h.
+func (es *EventSink) IndexBlockEvents(h types.EventDataNewBlockEvents) error {
+	bz, err := json.Marshal(BlockLine{
+		Type:    LineTypeBlock,
+		ChainID: es.chainID,
+		Height:  h.Height,
+		NumTxs:  h.NumTxs,
+		Events:  h.Events,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding block events at height %d: %w", h.Height, err)
+	}
+
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	return es.writeLines(h.Height, bz)
+}
+
+// IndexTxEvents writes a line for each of the transaction results txrs.
+func (es *EventSink) IndexTxEvents(txrs []*abci.TxResult) error {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+
+	var buf bytes.Buffer
+	for i, txr := range txrs {
+		bz, err := json.Marshal(TxLine{
+			Type:    LineTypeTx,
+			ChainID: es.chainID,
+			Height:  txr.Height,
+			Index:   txr.Index,
+			Hash:    fmt.Sprintf("%X", types.Tx(txr.Tx).Hash()),
+			Tx:      txr.Tx,
+			Result:  txr.Result,
+		})
+		if err != nil {
+			return fmt.Errorf("encoding tx result at height %d: %w", txr.Height, err)
+		}
+		buf.Write(bz)
+		buf.WriteByte('\n')
+
+		// The lines of a height are written together.
+		if i == len(txrs)-1 || txrs[i+1].Height != txr.Height {
+			if err := es.write(txr.Height, buf.Bytes()); err != nil {
+				return err
+			}
+			buf.Reset()
+		}
+	}
+	return nil
+}
+
+// writeLines writes the line bz, at the given height, followed by a newline.
+func (es *EventSink) writeLines(height int64, bz []byte) error {
+	return es.write(height, append(bz, '\n'))
+}
+
+// write appends data to the file holding the given height, opening it first
+// if it is not the head file.
+func (es *EventSink) write(height int64, data []byte) error {
+	if es.head == nil || height < es.headFile.FromHeight || height > es.headFile.ToHeight {
+		if err := es.openFile(height); err != nil {
+			return err
+		}
+	}
+	if _, err := es.head.Write(data); err != nil {
+		return fmt.Errorf("writing to %s: %w", es.headFile.Name, err)
+	}
+	return nil
+}
+
+// openFile opens the file holding the given height as the head file, and adds
+// it to the index if it is new.
+func (es *EventSink) openFile(height int64) error {
+	if err := es.closeHead(); err != nil {
+		return err
+	}
+
+	fromHeight, toHeight := indexer.BucketOf(height, es.fileHeights)
+	f := File{
+		Name:       fmt.Sprintf("events-%d-%d.jsonl", fromHeight, toHeight),
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+	}
+	i := sort.Search(len(es.index.Files), func(i int) bool {
+		return es.index.Files[i].FromHeight >= fromHeight
+	})
+	if i == len(es.index.Files) || es.index.Files[i].Name != f.Name {
+		es.index.Files = append(es.index.Files, File{})
+		copy(es.index.Files[i+1:], es.index.Files[i:])
+		es.index.Files[i] = f
+		if err := es.saveIndex(); err != nil {
+			return err
+		}
+	}
+
+	af, err := autofile.OpenAutoFile(filepath.Join(es.dir, f.Name))
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", f.Name, err)
+	}
+	es.head, es.headFile = af, f
+	return nil
+}
+
+func (es *EventSink) closeHead() error {
+	if es.head == nil {
+		return nil
+	}
+	err := es.head.Close()
+	es.head = nil
+	return err
+}
+
+// saveIndex atomically replaces the index file with the index of the sink.
+func (es *EventSink) saveIndex() error {
+	bz, err := json.MarshalIndent(es.index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tempfile.WriteFileAtomic(filepath.Join(es.dir, IndexFile), bz, 0o600); err != nil {
+		return fmt.Errorf("saving the jsonl index: %w", err)
+	}
+	return nil
+}
+
+// PruneTxEvents marks the transaction results below retainHeight as pruned,
+// and removes the files of which all the heights are pruned. It returns the
+// number of files removed.
+func (es *EventSink) PruneTxEvents(retainHeight int64) (int64, error) {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	es.index.TxPrunedHeight = max(es.index.TxPrunedHeight, retainHeight)
+	return es.removePrunedFiles()
+}
+
+// PruneBlockEvents marks the block events below retainHeight as pruned, and
+// removes the files of which all the heights are pruned. It returns the number
+// of files removed.
+func (es *EventSink) PruneBlockEvents(retainHeight int64) (int64, error) {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	es.index.BlockPrunedHeight = max(es.index.BlockPrunedHeight, retainHeight)
+	return es.removePrunedFiles()
+}
+
+func (es *EventSink) removePrunedFiles() (int64, error) {
+	prunedHeight := min(es.index.TxPrunedHeight, es.index.BlockPrunedHeight)
+	var removed []File
+	for len(es.index.Files) > 0 && es.index.Files[0].ToHeight < prunedHeight {
+		removed = append(removed, es.index.Files[0])
+		es.index.Files = es.index.Files[1:]
+	}
+	// The index is saved before the files are removed, so that it never
+	// lists a missing file.
+	if err := es.saveIndex(); err != nil {
+		return 0, err
+	}
+	for _, f := range removed {
+		if f.Name == es.headFile.Name {
+			if err := es.closeHead(); err != nil {
+				return 0, err
+			}
+		}
+		if err := os.Remove(filepath.Join(es.dir, f.Name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return 0, fmt.Errorf("removing %s: %w", f.Name, err)
+		}
+	}
+	return int64(len(removed)), nil
+}
+
+// Names of the retain heights of the indexers.
+const (
+	txIndexerRetainHeight    = "tx_indexer"
+	blockIndexerRetainHeight = "block_indexer"
+)
+
+// retainHeight returns the field of the index holding the retain height with
+// the given name.
+func (idx *index) retainHeight(name string) **int64 {
+	if name == txIndexerRetainHeight {
+		return &idx.TxRetainHeight
+	}
+	return &idx.BlockRetainHeight
+}
+
+// setRetainHeight records the retain height with the given name.
+func (es *EventSink) setRetainHeight(name string, height int64) error {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	*es.index.retainHeight(name) = &height
+	return es.saveIndex()
+}
+
+// getRetainHeight returns the retain height with the given name, or
+// state.ErrKeyNotFound if it was not set.
+func (es *EventSink) getRetainHeight(name string) (int64, error) {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	height := *es.index.retainHeight(name)
+	if height == nil {
+		return 0, state.ErrKeyNotFound
+	}
+	return *height, nil
+}
+
+// Stop closes the head file of the sink.
+func (es *EventSink) Stop() error {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	return es.closeHead()
+}
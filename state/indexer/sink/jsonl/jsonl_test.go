@@ -0,0 +1,135 @@
+package jsonl_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/v2/abci/types"
+	"github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/state/indexer/sink/jsonl"
+	"github.com/cometbft/cometbft/v2/state/txindex"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+const chainID = "test-chain"
+
+func TestEventSink(t *testing.T) {
+	dir := t.TempDir()
+	es, err := jsonl.NewEventSink(dir, chainID, 10)
+	require.NoError(t, err)
+
+	for height := int64(1); height <= 25; height++ {
+		require.NoError(t, es.BlockIndexer().Index(types.EventDataNewBlockEvents{
+			Height: height,
+			Events: []abci.Event{makeEvent("begin_event", "proposer", "FCAA001")},
+			NumTxs: 1,
+		}))
+		require.NoError(t, es.TxIndexer().AddBatch(&txindex.Batch{Ops: []*abci.TxResult{{
+			Height: height,
+			Tx:     types.Tx(fmt.Sprintf("tx%d", height)),
+			Result: abci.ExecTxResult{Events: []abci.Event{makeEvent("transfer", "amount", "10")}},
+		}}}))
+	}
+
+	files := es.Files()
+	require.Equal(t, []jsonl.File{
+		{Name: "events-1-10.jsonl", FromHeight: 1, ToHeight: 10},
+		{Name: "events-11-20.jsonl", FromHeight: 11, ToHeight: 20},
+		{Name: "events-21-30.jsonl", FromHeight: 21, ToHeight: 30},
+	}, files)
+
+	t.Run("Lines", func(t *testing.T) {
+		lines := readLines(t, filepath.Join(dir, files[1].Name))
+		require.Len(t, lines, 20)
+
+		var block jsonl.BlockLine
+		require.NoError(t, json.Unmarshal(lines[0], &block))
+		require.Equal(t, jsonl.LineTypeBlock, block.Type)
+		require.Equal(t, chainID, block.ChainID)
+		require.Equal(t, int64(11), block.Height)
+		require.Equal(t, "proposer", block.Events[0].Attributes[0].Key)
+
+		var tx jsonl.TxLine
+		require.NoError(t, json.Unmarshal(lines[1], &tx))
+		require.Equal(t, jsonl.LineTypeTx, tx.Type)
+		require.Equal(t, int64(11), tx.Height)
+		require.Equal(t, []byte("tx11"), tx.Tx)
+		require.Equal(t, fmt.Sprintf("%X", types.Tx("tx11").Hash()), tx.Hash)
+		require.Equal(t, "transfer", tx.Result.Events[0].Type)
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		_, err := es.TxIndexer().GetRetainHeight()
+		require.ErrorIs(t, err, state.ErrKeyNotFound)
+		require.NoError(t, es.TxIndexer().SetRetainHeight(21))
+		require.NoError(t, es.BlockIndexer().SetRetainHeight(15))
+
+		// the heights below 15 are pruned for the transactions only
+		numPruned, retainHeight, err := es.TxIndexer().Prune(21)
+		require.NoError(t, err)
+		require.Zero(t, numPruned)
+		require.Equal(t, int64(21), retainHeight)
+
+		numPruned, _, err = es.BlockIndexer().Prune(15)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), numPruned)
+		require.Len(t, es.Files(), 2)
+		require.NoFileExists(t, filepath.Join(dir, files[0].Name))
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		require.NoError(t, es.Stop())
+		es, err := jsonl.NewEventSink(dir, chainID, 10)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = es.Stop() })
+
+		require.Equal(t, files[1:], es.Files())
+		retainHeight, err := es.BlockIndexer().GetRetainHeight()
+		require.NoError(t, err)
+		require.Equal(t, int64(15), retainHeight)
+
+		// lines are appended to the existing files
+		require.NoError(t, es.BlockIndexer().Index(types.EventDataNewBlockEvents{Height: 26}))
+		require.Len(t, readLines(t, filepath.Join(dir, files[2].Name)), 11)
+
+		numPruned, _, err := es.BlockIndexer().Prune(21)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), numPruned)
+		require.Equal(t, files[2:], es.Files())
+	})
+
+	t.Run("NotSupported", func(t *testing.T) {
+		_, err := es.TxIndexer().Get(types.Tx("tx1").Hash())
+		require.Error(t, err)
+		_, err = es.BlockIndexer().Has(1)
+		require.Error(t, err)
+	})
+}
+
+func makeEvent(typ, key, value string) abci.Event {
+	return abci.Event{
+		Type:       typ,
+		Attributes: []abci.EventAttribute{{Key: key, Value: value, Index: true}},
+	}
+}
+
+func readLines(t *testing.T, path string) [][]byte {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
@@ -20,6 +20,7 @@ import (
 	abci "github.com/cometbft/cometbft/v2/abci/types"
 	"github.com/cometbft/cometbft/v2/libs/log"
 	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	"github.com/cometbft/cometbft/v2/state/indexer"
 	"github.com/cometbft/cometbft/v2/state/txindex"
 	"github.com/cometbft/cometbft/v2/types"
 )
@@ -68,6 +69,12 @@ func (BackportTxIndexer) Search(context.Context, *query.Query, txindex.Paginatio
 	return nil, 0, errors.New("the TxIndexer.Search method is not supported")
 }
 
+// Count is implemented to satisfy the TxIndexer interface, but it is not
+// supported by the psql event sink and reports an error for all inputs.
+func (BackportTxIndexer) Count(context.Context, *query.Query, int64) (int, []indexer.HeightBucket, error) {
+	return 0, nil, errors.New("the TxIndexer.Count method is not supported")
+}
+
 func (BackportTxIndexer) SetLogger(log.Logger) {}
 
 // Close closes the indexer's underlying database. The caller is responsible for
@@ -117,4 +124,10 @@ func (BackportBlockIndexer) Search(context.Context, *query.Query) ([]int64, erro
 	return nil, errors.New("the BlockIndexer.Search method is not supported")
 }
 
+// Count is implemented to satisfy the BlockIndexer interface, but it is not
+// supported by the psql event sink and reports an error for all inputs.
+func (BackportBlockIndexer) Count(context.Context, *query.Query, int64) (int, []indexer.HeightBucket, error) {
+	return 0, nil, errors.New("the BlockIndexer.Count method is not supported")
+}
+
 func (BackportBlockIndexer) SetLogger(log.Logger) {}
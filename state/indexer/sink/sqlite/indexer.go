@@ -0,0 +1,191 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/v2/abci/types"
+	"github.com/cometbft/cometbft/v2/libs/log"
+	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	"github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/state/indexer"
+	"github.com/cometbft/cometbft/v2/state/txindex"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// TxIndexer returns the transaction indexer backed by es.
+func (es *EventSink) TxIndexer() TxIndexer {
+	return TxIndexer{sqlite: es}
+}
+
+// TxIndexer implements the txindex.TxIndexer interface by delegating
+// indexing and search operations to an underlying SQLite event sink.
+type TxIndexer struct{ sqlite *EventSink }
+
+var (
+	_ txindex.TxIndexer       = TxIndexer{}
+	_ txindex.SinkHeightStore = TxIndexer{}
+)
+
+// GetRetainHeight returns the retain height set by the pruning service, as
+// part of TxIndexer.
+func (b TxIndexer) GetRetainHeight() (int64, error) {
+	return b.sqlite.getRetainHeight(txIndexerRetainHeight)
+}
+
+// SetRetainHeight sets the retain height, as part of TxIndexer.
+func (b TxIndexer) SetRetainHeight(retainHeight int64) error {
+	return b.sqlite.setRetainHeight(txIndexerRetainHeight, retainHeight)
+}
+
+// GetReindexCheckpoint returns the progress of the last re-index of the events,
+// or state.ErrKeyNotFound if they never were.
+func (b TxIndexer) GetReindexCheckpoint() (txindex.ReindexCheckpoint, error) {
+	return b.sqlite.getReindexCheckpoint()
+}
+
+// SetReindexCheckpoint records the progress of a re-index of the events.
+func (b TxIndexer) SetReindexCheckpoint(cp txindex.ReindexCheckpoint) error {
+	return b.sqlite.setReindexCheckpoint(cp)
+}
+
+// GetSinkHeight returns the height up to which the additional sink with the
+// given name indexed the blocks, or 0 if it was never recorded.
+func (b TxIndexer) GetSinkHeight(name string) (int64, error) {
+	height, err := b.sqlite.getRetainHeight(sinkHeightPrefix + name)
+	if errors.Is(err, state.ErrKeyNotFound) {
+		return 0, nil
+	}
+	return height, err
+}
+
+// SetSinkHeight records the height up to which the additional sink with the
+// given name indexed the blocks.
+func (b TxIndexer) SetSinkHeight(name string, height int64) error {
+	return b.sqlite.setRetainHeight(sinkHeightPrefix+name, height)
+}
+
+// Prune deletes the transactions below retainHeight, as part of TxIndexer.
+func (b TxIndexer) Prune(retainHeight int64) (numPruned, newRetainHeight int64, err error) {
+	numPruned, err = b.sqlite.PruneTxEvents(retainHeight)
+	if err != nil {
+		return 0, 0, err
+	}
+	return numPruned, retainHeight, nil
+}
+
+// AddBatch indexes a batch of transactions in SQLite, as part of TxIndexer.
+func (b TxIndexer) AddBatch(batch *txindex.Batch) error {
+	return b.sqlite.IndexTxEvents(batch.Ops)
+}
+
+// Index indexes a single transaction result in SQLite, as part of TxIndexer.
+func (b TxIndexer) Index(txr *abci.TxResult) error {
+	return b.sqlite.IndexTxEvents([]*abci.TxResult{txr})
+}
+
+// Get returns the result of the transaction with the given hash, or nil if it
+// is not indexed, as part of TxIndexer.
+func (b TxIndexer) Get(hash []byte) (*abci.TxResult, error) {
+	if len(hash) == 0 {
+		return nil, txindex.ErrorEmptyHash
+	}
+	return b.sqlite.GetTxByHash(hash)
+}
+
+// Search returns the transactions matching q, as part of TxIndexer.
+func (b TxIndexer) Search(ctx context.Context, q *query.Query, pagSettings txindex.Pagination) ([]*abci.TxResult, int, error) {
+	if pagSettings.Limit > 0 {
+		return b.sqlite.SearchTxEvents(ctx, q, pagSettings.OrderDesc, pagSettings.Cursor, 0, pagSettings.Limit)
+	}
+	if !pagSettings.IsPaginated {
+		return b.sqlite.SearchTxEvents(ctx, q, pagSettings.OrderDesc, nil, 0, 0)
+	}
+	if pagSettings.PerPage < 1 {
+		return nil, 0, fmt.Errorf("zero or negative perPage: %d", pagSettings.PerPage)
+	}
+	offset := (pagSettings.Page - 1) * pagSettings.PerPage
+	results, total, err := b.sqlite.SearchTxEvents(ctx, q, pagSettings.OrderDesc, nil, offset, pagSettings.PerPage)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Now that we know the total number of results, validate that the page
+	// requested is within bounds.
+	pages := ((total - 1) / pagSettings.PerPage) + 1
+	if pagSettings.Page <= 0 || pagSettings.Page > pages {
+		return nil, 0, fmt.Errorf("page should be within [1, %d] range, given %d", pages, pagSettings.Page)
+	}
+	return results, total, nil
+}
+
+// Count returns the number of transactions matching q, as part of TxIndexer.
+func (b TxIndexer) Count(ctx context.Context, q *query.Query, bucketSize int64) (int, []indexer.HeightBucket, error) {
+	return b.sqlite.CountTxEvents(ctx, q, bucketSize)
+}
+
+func (TxIndexer) SetLogger(log.Logger) {}
+
+// Close closes the indexer's underlying database. The caller is responsible for
+// calling Close when done with the indexer.
+func (b TxIndexer) Close() error {
+	return b.sqlite.Stop()
+}
+
+// BlockIndexer returns the block indexer backed by es.
+func (es *EventSink) BlockIndexer() BlockIndexer {
+	return BlockIndexer{sqlite: es}
+}
+
+// BlockIndexer implements the indexer.BlockIndexer interface by delegating
+// indexing and search operations to an underlying SQLite event sink.
+type BlockIndexer struct{ sqlite *EventSink }
+
+var _ indexer.BlockIndexer = BlockIndexer{}
+
+// GetRetainHeight returns the retain height set by the pruning service, as
+// part of BlockIndexer.
+func (b BlockIndexer) GetRetainHeight() (int64, error) {
+	return b.sqlite.getRetainHeight(blockIndexerRetainHeight)
+}
+
+// SetRetainHeight sets the retain height, as part of BlockIndexer.
+func (b BlockIndexer) SetRetainHeight(retainHeight int64) error {
+	return b.sqlite.setRetainHeight(blockIndexerRetainHeight, retainHeight)
+}
+
+// Prune deletes the events of the blocks below retainHeight, as part of
+// BlockIndexer.
+func (b BlockIndexer) Prune(retainHeight int64) (numPruned, newRetainHeight int64, err error) {
+	numPruned, err = b.sqlite.PruneBlockEvents(retainHeight)
+	if err != nil {
+		return 0, 0, err
+	}
+	return numPruned, retainHeight, nil
+}
+
+// Has reports whether the given height has been indexed, as part of
+// BlockIndexer.
+func (b BlockIndexer) Has(height int64) (bool, error) {
+	return b.sqlite.HasBlock(height)
+}
+
+// Index indexes block begin and end events for the specified block.  It is
+// part of the BlockIndexer interface.
+func (b BlockIndexer) Index(block types.EventDataNewBlockEvents) error {
+	return b.sqlite.IndexBlockEvents(block)
+}
+
+// Search returns the heights of the blocks matching q, as part of
+// BlockIndexer.
+func (b BlockIndexer) Search(ctx context.Context, q *query.Query) ([]int64, error) {
+	return b.sqlite.SearchBlockEvents(ctx, q)
+}
+
+// Count returns the number of the heights of the blocks matching q, as part
+// of BlockIndexer.
+func (b BlockIndexer) Count(ctx context.Context, q *query.Query, bucketSize int64) (int, []indexer.HeightBucket, error) {
+	return b.sqlite.CountBlockEvents(ctx, q, bucketSize)
+}
+
+func (BlockIndexer) SetLogger(log.Logger) {}
@@ -0,0 +1,255 @@
+package sqlite
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"modernc.org/sqlite"
+
+	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	"github.com/cometbft/cometbft/v2/libs/pubsub/query/syntax"
+	"github.com/cometbft/cometbft/v2/state/indexer"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// matchFunc is the name of the SQL function evaluating a condition, such as
+// "transfer.amount > 5", against an attribute value, with the semantics of the
+// query package.
+const matchFunc = "cometbft_match"
+
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction(matchFunc, 3, matchCondition)
+}
+
+var (
+	// searchConditions holds the conditions evaluated by matchFunc for the
+	// searches in progress, by search ID. They are compiled once per search,
+	// when its SQL expression is built, and dropped when it completes.
+	searchConditionsMtx sync.RWMutex
+	searchConditions    = make(map[int64][]compiledCondition)
+	lastSearchID        atomic.Int64
+)
+
+type compiledCondition struct {
+	q   *query.Query
+	tag string
+	err error
+}
+
+// matchCondition implements matchFunc: its arguments are the ID of the
+// search, the index of the condition in those of the search, and the
+// attribute value.
+func matchCondition(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	id, _ := args[0].(int64)
+	index, _ := args[1].(int64)
+	value, ok := args[2].(string)
+	if !ok {
+		return int64(0), nil
+	}
+	searchConditionsMtx.RLock()
+	conds := searchConditions[id]
+	searchConditionsMtx.RUnlock()
+	if index < 0 || index >= int64(len(conds)) {
+		return nil, fmt.Errorf("no condition %d for search %d", index, id)
+	}
+	c := conds[index]
+	if c.err != nil {
+		return nil, c.err
+	}
+	if matches, _ := c.q.Matches(map[string][]string{c.tag: {value}}); matches {
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
+
+// searchKind describes the rows searched: the alias of the table of which
+// the row IDs are matched against those of the events, and the keys of the
+// conditions evaluated against the columns rather than the meta-events.
+type searchKind struct {
+	rowID     string
+	eventID   string
+	eventCond string
+	heightKey string
+	hashKey   string
+}
+
+var (
+	txSearch = searchKind{
+		rowID:     "t.rowid",
+		eventID:   "e.tx_id",
+		eventCond: "e.tx_id IS NOT NULL",
+		heightKey: types.TxHeightKey,
+		hashKey:   types.TxHashKey,
+	}
+	blockSearch = searchKind{
+		rowID:     "b.rowid",
+		eventID:   "e.block_id",
+		eventCond: "e.tx_id IS NULL",
+		heightKey: types.BlockHeightKey,
+	}
+)
+
+// whereClause builds the SQL expression, and its arguments, selecting the
+// transactions or blocks matching a query.
+type whereClause struct {
+	strings.Builder
+	kind searchKind
+	args []any
+
+	// the ID of the search, and the conditions it evaluates with matchFunc
+	id      int64
+	matches []compiledCondition
+}
+
+func newWhereClause(kind searchKind) *whereClause {
+	return &whereClause{kind: kind, id: lastSearchID.Add(1)}
+}
+
+// disjunction adds the expression matching any of conjs, and makes the
+// conditions it evaluates with matchFunc available until release is called.
+func (w *whereClause) disjunction(conjs []indexer.Conjunction) {
+	for i, conj := range conjs {
+		if i > 0 {
+			w.WriteString(" OR ")
+		}
+		w.WriteString("(")
+		w.conjunction(conj)
+		w.WriteString(")")
+	}
+	if len(w.matches) > 0 {
+		searchConditionsMtx.Lock()
+		searchConditions[w.id] = w.matches
+		searchConditionsMtx.Unlock()
+	}
+}
+
+// release drops the conditions of the search, once it completed.
+func (w *whereClause) release() {
+	searchConditionsMtx.Lock()
+	delete(searchConditions, w.id)
+	searchConditionsMtx.Unlock()
+}
+
+// conjunction adds the expression matching conj. As with the kv indexers, the
+// conditions on event attributes must all be matched by the same event.
+func (w *whereClause) conjunction(conj indexer.Conjunction) {
+	var (
+		and    bool
+		events []syntax.Condition
+	)
+	sep := func() {
+		if and {
+			w.WriteString(" AND ")
+		}
+		and = true
+	}
+	for _, c := range conj.Conditions {
+		if w.columnCondition(c) {
+			sep()
+			w.writeColumnCondition(c)
+			continue
+		}
+		if c.Tag == w.kind.heightKey || c.Tag == w.kind.hashKey {
+			// The meta-event of the key is matched separately.
+			sep()
+			w.writeEventConditions(c)
+			continue
+		}
+		events = append(events, c)
+	}
+	if len(events) > 0 {
+		sep()
+		w.writeEventConditions(events...)
+	}
+	for _, c := range conj.Negated {
+		sep()
+		w.WriteString("NOT (")
+		if w.columnCondition(c) {
+			w.writeColumnCondition(c)
+		} else {
+			w.writeEventConditions(c)
+		}
+		w.WriteString(")")
+	}
+	if !and {
+		w.WriteString("1")
+	}
+}
+
+// columnCondition reports whether c is evaluated against the height or hash
+// column.
+func (w *whereClause) columnCondition(c syntax.Condition) bool {
+	switch {
+	case c.Tag == w.kind.heightKey:
+		return c.Arg != nil && c.Arg.Type == syntax.TNumber && sqlOperator(c.Op) != ""
+	case c.Tag == w.kind.hashKey:
+		return c.Op == syntax.TEq && c.Arg != nil && c.Arg.Type == syntax.TString
+	default:
+		return false
+	}
+}
+
+func (w *whereClause) writeColumnCondition(c syntax.Condition) {
+	if c.Tag == w.kind.hashKey {
+		w.WriteString("t.tx_hash = ?")
+		w.args = append(w.args, strings.ToUpper(c.Arg.Value()))
+		return
+	}
+	w.WriteString("b.height " + sqlOperator(c.Op) + " ?")
+	f, _ := c.Arg.Number().Float64()
+	w.args = append(w.args, f)
+}
+
+// writeEventConditions adds the expression matching the rows with an event
+// matching all of conds.
+func (w *whereClause) writeEventConditions(conds ...syntax.Condition) {
+	w.WriteString(w.kind.rowID + " IN (SELECT " + w.kind.eventID + " FROM events e")
+	for i := range conds {
+		a := "a" + strconv.Itoa(i)
+		w.WriteString(" JOIN attributes " + a + " ON " + a + ".event_id = e.rowid")
+	}
+	w.WriteString(" WHERE " + w.kind.eventCond)
+	for i, c := range conds {
+		a := "a" + strconv.Itoa(i)
+		w.WriteString(" AND " + a + ".composite_key = ?")
+		w.args = append(w.args, c.Tag)
+		switch {
+		case c.Op == syntax.TExists:
+		case c.Op == syntax.TEq && c.Arg != nil && c.Arg.Type == syntax.TString:
+			w.WriteString(" AND " + a + ".value = ?")
+			w.args = append(w.args, c.Arg.Value())
+		case c.Op == syntax.TContains && c.Arg != nil:
+			w.WriteString(" AND instr(" + a + ".value, ?) > 0")
+			w.args = append(w.args, c.Arg.Value())
+		default:
+			w.WriteString(" AND " + matchFunc + "(?, ?, " + a + ".value)")
+			w.args = append(w.args, w.id, len(w.matches))
+			q, err := query.CompileExpr(syntax.NewConditionExpr(c))
+			w.matches = append(w.matches, compiledCondition{q: q, tag: c.Tag, err: err})
+		}
+	}
+	w.WriteString(")")
+}
+
+// sqlOperator returns the SQL operator of a comparison, or "" for other
+// operators.
+func sqlOperator(op syntax.Token) string {
+	switch op {
+	case syntax.TEq:
+		return "="
+	case syntax.TLt:
+		return "<"
+	case syntax.TLeq:
+		return "<="
+	case syntax.TGt:
+		return ">"
+	case syntax.TGeq:
+		return ">="
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,598 @@
+// Package sqlite implements an event sink backed by an embedded SQLite
+// database.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed" // embed the schema
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmos/gogoproto/proto"
+	_ "modernc.org/sqlite" // provide the sqlite db driver.
+
+	abci "github.com/cometbft/cometbft/v2/abci/types"
+	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	"github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/state/indexer"
+	"github.com/cometbft/cometbft/v2/state/txindex"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+const driverName = "sqlite"
+
+// Names of the retain heights of the indexers, of the progress of the last
+// re-index by the reindex-event command, and of the heights of the additional
+// sinks, in the retain_heights table.
+const (
+	txIndexerRetainHeight    = "tx_indexer"
+	blockIndexerRetainHeight = "block_indexer"
+	reindexCheckpoint        = "reindex_checkpoint"
+	reindexStartHeight       = "reindex_start_height"
+	reindexEndHeight         = "reindex_end_height"
+
+	// sinkHeightPrefix prefixes the names of the heights up to which the
+	// additional sinks of the indexer service indexed the blocks.
+	sinkHeightPrefix = "sink_height:"
+)
+
+// schema is the schema of the database, installed when the sink is created.
+//
+//go:embed schema.sql
+var schema string
+
+// EventSink is an indexer backend providing the tx/block index services.  This
+// implementation stores records in a SQLite database using the schema
+// defined in state/indexer/sink/sqlite/schema.sql.
+type EventSink struct {
+	store   *sql.DB
+	chainID string
+}
+
+// NewEventSink constructs an event sink associated with the SQLite database
+// stored in the file at path, which is created, with the schema, if it does
+// not exist. Events written to the sink are attributed to the specified
+// chainID.
+func NewEventSink(path, chainID string) (*EventSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating directory of the sqlite database: %w", err)
+	}
+	// Writers wait for each other rather than failing with SQLITE_BUSY, and
+	// the write-ahead log lets searches run while blocks are indexed.
+	dsn := "file:" + path + "?_txlock=immediate&_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("installing the sqlite schema: %w", err)
+	}
+	return &EventSink{store: db, chainID: chainID}, nil
+}
+
+// DB returns the underlying SQLite connection used by the sink.
+// This is exported to support testing.
+func (es *EventSink) DB() *sql.DB { return es.store }
+
+// runInTransaction executes query in a fresh database transaction.
+// If query reports an error, the transaction is rolled back and the
+// error from query is reported to the caller.
+// Otherwise, the result of committing the transaction is returned.
+func runInTransaction(db *sql.DB, query func(*sql.Tx) error) error {
+	dbtx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := query(dbtx); err != nil {
+		_ = dbtx.Rollback() // report the initial error, not the rollback
+		return err
+	}
+	return dbtx.Commit()
+}
+
+// insertEvents inserts the events, with their indexed attributes, of the block
+// blockID, and of the transaction txID if it is > 0.
+func insertEvents(dbtx *sql.Tx, blockID, txID int64, events []abci.Event) error {
+	// Populate the transaction ID field iff one is defined (> 0).
+	var txIDArg any
+	if txID > 0 {
+		txIDArg = txID
+	}
+	eventStmt, err := dbtx.Prepare(`INSERT INTO events (block_id, tx_id, type) VALUES (?, ?, ?);`)
+	if err != nil {
+		return fmt.Errorf("preparing event insert statement: %w", err)
+	}
+	defer eventStmt.Close()
+	attrStmt, err := dbtx.Prepare(`INSERT INTO attributes (event_id, key, composite_key, value) VALUES (?, ?, ?, ?);`)
+	if err != nil {
+		return fmt.Errorf("preparing attribute insert statement: %w", err)
+	}
+	defer attrStmt.Close()
+
+	for _, event := range events {
+		// Skip events with an empty type.
+		if event.Type == "" {
+			continue
+		}
+		res, err := eventStmt.Exec(blockID, txIDArg, event.Type)
+		if err != nil {
+			return fmt.Errorf("inserting event: %w", err)
+		}
+		eventID, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		for _, attr := range event.Attributes {
+			if !attr.Index {
+				continue
+			}
+			compositeKey := event.Type + "." + attr.Key
+			if _, err := attrStmt.Exec(eventID, attr.Key, compositeKey, attr.Value); err != nil {
+				return fmt.Errorf("inserting attribute: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// makeIndexedEvent constructs an event from the specified composite key and
+// value. If the key has the form "type.name", the event will have a single
+// attribute with that name and the value; otherwise the event will have only
+// a type and no attributes.
+func makeIndexedEvent(compositeKey, value string) abci.Event {
+	i := strings.Index(compositeKey, ".")
+	if i < 0 {
+		return abci.Event{Type: compositeKey}
+	}
+	return abci.Event{Type: compositeKey[:i], Attributes: []abci.EventAttribute{
+		{Key: compositeKey[i+1:], Value: value, Index: true},
+	}}
+}
+
+// blockID returns the row ID of the block at height, adding the block to the
+// blocks table if it is not there yet.
+func (es *EventSink) blockID(dbtx *sql.Tx, height int64, ts time.Time) (int64, error) {
+	if _, err := dbtx.Exec(`
+INSERT INTO blocks (height, chain_id, created_at)
+  VALUES (?, ?, ?)
+  ON CONFLICT DO NOTHING;
+`, height, es.chainID, ts); err != nil {
+		return 0, err
+	}
+	var blockID int64
+	err := dbtx.QueryRow(`SELECT rowid FROM blocks WHERE height = ? AND chain_id = ?;`,
+		height, es.chainID).Scan(&blockID)
+	return blockID, err
+}
+
+// IndexBlockEvents indexes the specified block header, part of the
+// indexer.EventSink interface.
+func (es *EventSink) IndexBlockEvents(h types.EventDataNewBlockEvents) error {
+	ts := time.Now().UTC()
+
+	return runInTransaction(es.store, func(dbtx *sql.Tx) error {
+		blockID, err := es.blockID(dbtx, h.Height, ts)
+		if err != nil {
+			return fmt.Errorf("indexing block header: %w", err)
+		}
+		var indexed bool
+		if err := dbtx.QueryRow(`SELECT EXISTS(SELECT 1 FROM events WHERE block_id = ? AND tx_id IS NULL);`,
+			blockID).Scan(&indexed); err != nil {
+			return fmt.Errorf("indexing block header: %w", err)
+		}
+		if indexed {
+			return nil // we already saw this block; quietly succeed
+		}
+
+		// Insert the special block meta-event for height.
+		events := append([]abci.Event{makeIndexedEvent(types.BlockHeightKey, strconv.FormatInt(h.Height, 10))}, h.Events...)
+		if err := insertEvents(dbtx, blockID, 0, events); err != nil {
+			return fmt.Errorf("indexing block events: %w", err)
+		}
+		return nil
+	})
+}
+
+// IndexTxEvents indexes the specified transaction results, part of the
+// indexer.EventSink interface. The results already indexed are skipped.
+func (es *EventSink) IndexTxEvents(txrs []*abci.TxResult) error {
+	ts := time.Now().UTC()
+
+	return runInTransaction(es.store, func(dbtx *sql.Tx) error {
+		for _, txr := range txrs {
+			if txr == nil {
+				continue
+			}
+			blockID, err := es.blockID(dbtx, txr.Height, ts)
+			if err != nil {
+				return fmt.Errorf("getting block id for tx: %w", err)
+			}
+			// Encode the result message in protobuf wire format for indexing.
+			resultData, err := proto.Marshal(txr)
+			if err != nil {
+				return fmt.Errorf("marshaling tx_result: %w", err)
+			}
+			// Index the hash of the underlying transaction as a hex string.
+			txHash := fmt.Sprintf("%X", types.Tx(txr.Tx).Hash())
+			res, err := dbtx.Exec(`
+INSERT INTO tx_results (block_id, "index", created_at, tx_hash, tx_result)
+  VALUES (?, ?, ?, ?, ?)
+  ON CONFLICT DO NOTHING;
+`, blockID, txr.Index, ts, txHash, resultData)
+			if err != nil {
+				return fmt.Errorf("indexing tx_result: %w", err)
+			}
+			if n, err := res.RowsAffected(); err != nil {
+				return err
+			} else if n == 0 {
+				continue // already indexed
+			}
+			txID, err := res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			// Insert the special transaction meta-events for hash and height.
+			events := append([]abci.Event{
+				makeIndexedEvent(types.TxHashKey, txHash),
+				makeIndexedEvent(types.TxHeightKey, strconv.FormatInt(txr.Height, 10)),
+			},
+				txr.Result.Events...,
+			)
+			if err := insertEvents(dbtx, blockID, txID, events); err != nil {
+				return fmt.Errorf("indexing tx events: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// SearchBlockEvents returns the heights, in ascending order, of the indexed
+// blocks matching q.
+func (es *EventSink) SearchBlockEvents(ctx context.Context, q *query.Query) ([]int64, error) {
+	conjs, err := indexer.DisjunctiveNormalForm(q)
+	if err != nil {
+		return nil, err
+	}
+	w := newWhereClause(blockSearch)
+	w.disjunction(conjs)
+	defer w.release()
+	rows, err := es.store.QueryContext(ctx, `
+SELECT b.height FROM blocks b
+  WHERE b.chain_id = ?
+    AND EXISTS (SELECT 1 FROM events e WHERE e.block_id = b.rowid AND e.tx_id IS NULL)
+    AND (`+w.String()+`)
+  ORDER BY b.height;
+`, append([]any{es.chainID}, w.args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("searching blocks: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]int64, 0)
+	for rows.Next() {
+		var height int64
+		if err := rows.Scan(&height); err != nil {
+			return nil, err
+		}
+		results = append(results, height)
+	}
+	return results, rows.Err()
+}
+
+// CountBlockEvents returns the number of the heights of the blocks matching q
+// and, if bucketSize is positive, their number in each range of bucketSize
+// heights.
+func (es *EventSink) CountBlockEvents(ctx context.Context, q *query.Query, bucketSize int64) (int, []indexer.HeightBucket, error) {
+	conjs, err := indexer.DisjunctiveNormalForm(q)
+	if err != nil {
+		return 0, nil, err
+	}
+	w := newWhereClause(blockSearch)
+	w.disjunction(conjs)
+	defer w.release()
+	return es.countByHeight(ctx, `
+FROM blocks b
+  WHERE b.chain_id = ?
+    AND EXISTS (SELECT 1 FROM events e WHERE e.block_id = b.rowid AND e.tx_id IS NULL)
+    AND (`+w.String()+`)`, append([]any{es.chainID}, w.args...), bucketSize)
+}
+
+// CountTxEvents returns the number of the indexed transactions matching q
+// and, if bucketSize is positive, their number in each range of bucketSize
+// heights.
+func (es *EventSink) CountTxEvents(ctx context.Context, q *query.Query, bucketSize int64) (int, []indexer.HeightBucket, error) {
+	conjs, err := indexer.DisjunctiveNormalForm(q)
+	if err != nil {
+		return 0, nil, err
+	}
+	w := newWhereClause(txSearch)
+	w.disjunction(conjs)
+	defer w.release()
+	return es.countByHeight(ctx, `
+FROM tx_results t JOIN blocks b ON b.rowid = t.block_id
+  WHERE b.chain_id = ? AND (`+w.String()+`)`, append([]any{es.chainID}, w.args...), bucketSize)
+}
+
+// countByHeight counts the rows selected by from, a FROM clause of blocks b,
+// and their number in each range of bucketSize heights if it is positive.
+func (es *EventSink) countByHeight(ctx context.Context, from string, args []any, bucketSize int64) (int, []indexer.HeightBucket, error) {
+	if bucketSize <= 0 {
+		var total int
+		if err := es.store.QueryRowContext(ctx, `SELECT COUNT(*) `+from+`;`, args...).Scan(&total); err != nil {
+			return 0, nil, fmt.Errorf("counting: %w", err)
+		}
+		return total, nil, nil
+	}
+
+	rows, err := es.store.QueryContext(ctx, `SELECT (b.height - 1) / ? AS bucket, COUNT(*) `+from+`
+  GROUP BY bucket ORDER BY bucket;`, append([]any{bucketSize}, args...)...)
+	if err != nil {
+		return 0, nil, fmt.Errorf("counting: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		total   int
+		buckets = make([]indexer.HeightBucket, 0)
+	)
+	for rows.Next() {
+		var (
+			bucket int64
+			count  int
+		)
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return 0, nil, err
+		}
+		total += count
+		buckets = append(buckets, indexer.HeightBucket{
+			FromHeight: bucket*bucketSize + 1,
+			ToHeight:   (bucket + 1) * bucketSize,
+			Count:      count,
+		})
+	}
+	return total, buckets, rows.Err()
+}
+
+// SearchTxEvents returns the indexed transaction results matching q, ordered
+// by height and index, and their total number. If cursor is not nil, only the
+// results after it are considered. If limit is > 0, at most limit results are
+// returned, skipping the first offset ones.
+func (es *EventSink) SearchTxEvents(
+	ctx context.Context,
+	q *query.Query,
+	orderDesc bool,
+	cursor *txindex.Cursor,
+	offset, limit int,
+) ([]*abci.TxResult, int, error) {
+	conjs, err := indexer.DisjunctiveNormalForm(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	w := newWhereClause(txSearch)
+	w.disjunction(conjs)
+	defer w.release()
+	from := `
+FROM tx_results t JOIN blocks b ON b.rowid = t.block_id
+  WHERE b.chain_id = ? AND (` + w.String() + `)`
+	args := append([]any{es.chainID}, w.args...)
+	if cursor != nil {
+		cmp := ">"
+		if orderDesc {
+			cmp = "<"
+		}
+		from += ` AND (b.height, t."index") ` + cmp + ` (?, ?)`
+		args = append(args, cursor.Height, cursor.Index)
+	}
+
+	var total int
+	if err := es.store.QueryRowContext(ctx, `SELECT COUNT(*) `+from+`;`, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting txs: %w", err)
+	}
+
+	order := "ASC"
+	if orderDesc {
+		order = "DESC"
+	}
+	stmt := `SELECT t.tx_result ` + from + `
+  ORDER BY b.height ` + order + `, t."index" ` + order
+	if limit > 0 {
+		stmt += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, offset)
+	}
+	rows, err := es.store.QueryContext(ctx, stmt+`;`, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("searching txs: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*abci.TxResult, 0)
+	for rows.Next() {
+		var resultData []byte
+		if err := rows.Scan(&resultData); err != nil {
+			return nil, 0, err
+		}
+		txr := new(abci.TxResult)
+		if err := proto.Unmarshal(resultData, txr); err != nil {
+			return nil, 0, fmt.Errorf("unmarshaling tx_result: %w", err)
+		}
+		results = append(results, txr)
+	}
+	return results, total, rows.Err()
+}
+
+// GetTxByHash returns the indexed result of the transaction with the given
+// hash, or nil if it is not indexed.
+func (es *EventSink) GetTxByHash(hash []byte) (*abci.TxResult, error) {
+	var resultData []byte
+	err := es.store.QueryRow(`
+SELECT t.tx_result FROM tx_results t JOIN blocks b ON b.rowid = t.block_id
+  WHERE t.tx_hash = ? AND b.chain_id = ?
+  ORDER BY b.height LIMIT 1;
+`, fmt.Sprintf("%X", hash), es.chainID).Scan(&resultData)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting tx_result: %w", err)
+	}
+	txr := new(abci.TxResult)
+	if err := proto.Unmarshal(resultData, txr); err != nil {
+		return nil, fmt.Errorf("unmarshaling tx_result: %w", err)
+	}
+	return txr, nil
+}
+
+// HasBlock reports whether the events of the block at height are indexed.
+func (es *EventSink) HasBlock(height int64) (bool, error) {
+	var indexed bool
+	err := es.store.QueryRow(`
+SELECT EXISTS(
+  SELECT 1 FROM blocks b JOIN events e ON e.block_id = b.rowid
+    WHERE b.height = ? AND b.chain_id = ? AND e.tx_id IS NULL
+);`, height, es.chainID).Scan(&indexed)
+	return indexed, err
+}
+
+// PruneTxEvents deletes the transaction results, with their events, of the
+// blocks below retainHeight. It returns the number of heights pruned.
+func (es *EventSink) PruneTxEvents(retainHeight int64) (numPruned int64, err error) {
+	err = runInTransaction(es.store, func(dbtx *sql.Tx) error {
+		if err := dbtx.QueryRow(`
+SELECT COUNT(DISTINCT b.height) FROM tx_results t JOIN blocks b ON b.rowid = t.block_id
+  WHERE b.chain_id = ? AND b.height < ?;
+`, es.chainID, retainHeight).Scan(&numPruned); err != nil {
+			return err
+		}
+		for _, stmt := range []string{
+			`DELETE FROM attributes WHERE event_id IN (
+  SELECT e.rowid FROM events e JOIN blocks b ON b.rowid = e.block_id
+    WHERE b.chain_id = ? AND b.height < ? AND e.tx_id IS NOT NULL
+);`,
+			`DELETE FROM events WHERE tx_id IS NOT NULL AND block_id IN (
+  SELECT rowid FROM blocks WHERE chain_id = ? AND height < ?
+);`,
+			`DELETE FROM tx_results WHERE block_id IN (
+  SELECT rowid FROM blocks WHERE chain_id = ? AND height < ?
+);`,
+		} {
+			if _, err := dbtx.Exec(stmt, es.chainID, retainHeight); err != nil {
+				return err
+			}
+		}
+		return es.deleteUnusedBlocks(dbtx, retainHeight)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("pruning txs: %w", err)
+	}
+	return numPruned, nil
+}
+
+// PruneBlockEvents deletes the events of the blocks below retainHeight. It
+// returns the number of heights pruned.
+func (es *EventSink) PruneBlockEvents(retainHeight int64) (numPruned int64, err error) {
+	err = runInTransaction(es.store, func(dbtx *sql.Tx) error {
+		if err := dbtx.QueryRow(`
+SELECT COUNT(DISTINCT b.height) FROM events e JOIN blocks b ON b.rowid = e.block_id
+  WHERE b.chain_id = ? AND b.height < ? AND e.tx_id IS NULL;
+`, es.chainID, retainHeight).Scan(&numPruned); err != nil {
+			return err
+		}
+		for _, stmt := range []string{
+			`DELETE FROM attributes WHERE event_id IN (
+  SELECT e.rowid FROM events e JOIN blocks b ON b.rowid = e.block_id
+    WHERE b.chain_id = ? AND b.height < ? AND e.tx_id IS NULL
+);`,
+			`DELETE FROM events WHERE tx_id IS NULL AND block_id IN (
+  SELECT rowid FROM blocks WHERE chain_id = ? AND height < ?
+);`,
+		} {
+			if _, err := dbtx.Exec(stmt, es.chainID, retainHeight); err != nil {
+				return err
+			}
+		}
+		return es.deleteUnusedBlocks(dbtx, retainHeight)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("pruning blocks: %w", err)
+	}
+	return numPruned, nil
+}
+
+// deleteUnusedBlocks deletes the blocks below retainHeight of which both the
+// events and the transactions were pruned.
+func (es *EventSink) deleteUnusedBlocks(dbtx *sql.Tx, retainHeight int64) error {
+	_, err := dbtx.Exec(`
+DELETE FROM blocks
+  WHERE chain_id = ? AND height < ?
+    AND NOT EXISTS (SELECT 1 FROM events WHERE block_id = blocks.rowid)
+    AND NOT EXISTS (SELECT 1 FROM tx_results WHERE block_id = blocks.rowid);
+`, es.chainID, retainHeight)
+	return err
+}
+
+// setRetainHeight records the retain height with the given name.
+func (es *EventSink) setRetainHeight(name string, height int64) error {
+	_, err := es.store.Exec(`
+INSERT INTO retain_heights (name, height) VALUES (?, ?)
+  ON CONFLICT (name) DO UPDATE SET height = excluded.height;
+`, name, height)
+	return err
+}
+
+// getRetainHeight returns the retain height with the given name, or
+// state.ErrKeyNotFound if it was not set.
+func (es *EventSink) getRetainHeight(name string) (int64, error) {
+	var height int64
+	err := es.store.QueryRow(`SELECT height FROM retain_heights WHERE name = ?;`, name).Scan(&height)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, state.ErrKeyNotFound
+	}
+	return height, err
+}
+
+// setReindexCheckpoint records the progress of a re-index, in a single
+// transaction.
+func (es *EventSink) setReindexCheckpoint(cp txindex.ReindexCheckpoint) error {
+	return runInTransaction(es.store, func(dbtx *sql.Tx) error {
+		for name, height := range map[string]int64{
+			reindexStartHeight: cp.StartHeight,
+			reindexEndHeight:   cp.EndHeight,
+			reindexCheckpoint:  cp.Height,
+		} {
+			if _, err := dbtx.Exec(`
+INSERT INTO retain_heights (name, height) VALUES (?, ?)
+  ON CONFLICT (name) DO UPDATE SET height = excluded.height;
+`, name, height); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// getReindexCheckpoint returns the progress of the last re-index, or
+// state.ErrKeyNotFound if there was none.
+func (es *EventSink) getReindexCheckpoint() (txindex.ReindexCheckpoint, error) {
+	var cp txindex.ReindexCheckpoint
+	for name, height := range map[string]*int64{
+		reindexStartHeight: &cp.StartHeight,
+		reindexEndHeight:   &cp.EndHeight,
+		reindexCheckpoint:  &cp.Height,
+	} {
+		h, err := es.getRetainHeight(name)
+		if err != nil {
+			return cp, err
+		}
+		*height = h
+	}
+	return cp, nil
+}
+
+// Stop closes the underlying SQLite database.
+func (es *EventSink) Stop() error { return es.store.Close() }
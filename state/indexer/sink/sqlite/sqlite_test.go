@@ -0,0 +1,286 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/v2/abci/types"
+	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	"github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/state/indexer"
+	"github.com/cometbft/cometbft/v2/state/txindex"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+const chainID = "test-chainID"
+
+func newTestSink(t *testing.T) *EventSink {
+	t.Helper()
+	es, err := NewEventSink(filepath.Join(t.TempDir(), "data", "tx_index.sqlite"), chainID)
+	require.NoError(t, err)
+	t.Cleanup(func() { es.Stop() })
+	return es
+}
+
+func txResultWithEvents(height int64, index uint32, events []abci.Event) *abci.TxResult {
+	return &abci.TxResult{
+		Height: height,
+		Index:  index,
+		Tx:     types.Tx(fmt.Sprintf("tx-%d-%d", height, index)),
+		Result: abci.ExecTxResult{
+			Code:   abci.CodeTypeOK,
+			Events: events,
+		},
+	}
+}
+
+func attrEvent(typ, key, value string) abci.Event {
+	return abci.Event{Type: typ, Attributes: []abci.EventAttribute{{Key: key, Value: value, Index: true}}}
+}
+
+func TestIndexing(t *testing.T) {
+	es := newTestSink(t)
+	txIndexer := es.TxIndexer()
+	blockIndexer := es.BlockIndexer()
+
+	for h := int64(1); h <= 5; h++ {
+		require.NoError(t, blockIndexer.Index(types.EventDataNewBlockEvents{
+			Height: h,
+			Events: []abci.Event{attrEvent("finalize_block", "proposer", fmt.Sprintf("FCAA00%d", h))},
+		}))
+		batch := txindex.NewBatch(2)
+		for i := uint32(0); i < 2; i++ {
+			require.NoError(t, batch.Add(txResultWithEvents(h, i, []abci.Event{
+				{Type: "account", Attributes: []abci.EventAttribute{
+					{Key: "number", Value: fmt.Sprint(h*10 + int64(i)), Index: true},
+					{Key: "owner", Value: "Ivan", Index: i == 0},
+				}},
+				attrEvent("transfer", "amount", "18446744073709551616"), // 2^64
+			})))
+		}
+		require.NoError(t, txIndexer.AddBatch(batch))
+	}
+
+	// Indexing again is a no-op.
+	require.NoError(t, blockIndexer.Index(types.EventDataNewBlockEvents{Height: 1}))
+	require.NoError(t, txIndexer.Index(txResultWithEvents(1, 0, nil)))
+
+	txr := txResultWithEvents(3, 1, nil)
+	res, err := txIndexer.Get(types.Tx(txr.Tx).Hash())
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	assert.EqualValues(t, 3, res.Height)
+	assert.EqualValues(t, 1, res.Index)
+	res, err = txIndexer.Get(types.Tx("unknown").Hash())
+	require.NoError(t, err)
+	require.Nil(t, res)
+
+	ok, err := blockIndexer.Has(5)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = blockIndexer.Has(6)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	t.Run("SearchTxs", func(t *testing.T) {
+		testCases := []struct {
+			q       string
+			results []string // height/index
+		}{
+			{"tx.height = 2", []string{"2/0", "2/1"}},
+			{"tx.height > 2 AND tx.height <= 3", []string{"3/0", "3/1"}},
+			{fmt.Sprintf("tx.hash = '%x'", types.Tx(txr.Tx).Hash()), []string{"3/1"}},
+			{"account.number = 41", []string{"4/1"}},
+			{"account.number >= 41 AND account.number < 51", []string{"4/1", "5/0"}},
+			{"account.owner = 'Ivan' AND tx.height = 4", []string{"4/0"}},
+			{"account.owner CONTAINS 'va'", []string{"1/0", "2/0", "3/0", "4/0", "5/0"}},
+			{"account.owner EXISTS AND account.number > 30", []string{"4/0", "5/0"}},
+			// The conditions must be matched by the same event.
+			{"account.number = 10 AND transfer.amount EXISTS", nil},
+			{"transfer.amount > 18446744073709551615 AND tx.height = 1", []string{"1/0", "1/1"}},
+			{"transfer.amount > 18446744073709551616", nil},
+			{"account.number = 10 OR account.number = 51", []string{"1/0", "5/1"}},
+			{"tx.height < 3 AND NOT account.owner EXISTS", []string{"1/1", "2/1"}},
+			{"NOT (tx.height > 1 OR account.owner = 'Ivan')", []string{"1/1"}},
+			{"(tx.height = 1 OR tx.height = 5) AND NOT account.number = 11", []string{"1/0", "5/0", "5/1"}},
+			{"account.owner = 'Vlad'", nil},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.q, func(t *testing.T) {
+				results, total, err := txIndexer.Search(context.Background(), query.MustCompile(tc.q), txindex.Pagination{})
+				require.NoError(t, err)
+				require.Len(t, results, total)
+				got := make([]string, 0, len(results))
+				for _, r := range results {
+					got = append(got, fmt.Sprintf("%d/%d", r.Height, r.Index))
+				}
+				require.ElementsMatch(t, tc.results, got)
+			})
+		}
+		// The conditions compiled for the searches are dropped once they
+		// complete.
+		searchConditionsMtx.RLock()
+		defer searchConditionsMtx.RUnlock()
+		require.Empty(t, searchConditions)
+	})
+
+	t.Run("SearchTxsPaginated", func(t *testing.T) {
+		q := query.MustCompile("account.number > 0")
+		results, total, err := txIndexer.Search(context.Background(), q, txindex.Pagination{
+			OrderDesc: true, IsPaginated: true, Page: 2, PerPage: 3,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 10, total)
+		require.Len(t, results, 3)
+		assert.Equal(t, "tx-4-0", string(results[0].Tx))
+		assert.Equal(t, "tx-3-1", string(results[1].Tx))
+		assert.Equal(t, "tx-3-0", string(results[2].Tx))
+
+		_, _, err = txIndexer.Search(context.Background(), q, txindex.Pagination{
+			IsPaginated: true, Page: 5, PerPage: 3,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("SearchTxsCursor", func(t *testing.T) {
+		q := query.MustCompile("account.number > 0")
+		results, total, err := txIndexer.Search(context.Background(), q, txindex.Pagination{
+			Cursor: &txindex.Cursor{Height: 2, Index: 1}, Limit: 3,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 6, total)
+		require.Len(t, results, 3)
+		assert.Equal(t, "tx-3-0", string(results[0].Tx))
+		assert.Equal(t, "tx-4-0", string(results[2].Tx))
+
+		results, total, err = txIndexer.Search(context.Background(), q, txindex.Pagination{
+			OrderDesc: true, Cursor: &txindex.Cursor{Height: 2, Index: 1}, Limit: 3,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, total)
+		require.Len(t, results, 3)
+		assert.Equal(t, "tx-2-0", string(results[0].Tx))
+		assert.Equal(t, "tx-1-0", string(results[2].Tx))
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		total, buckets, err := txIndexer.Count(context.Background(), query.MustCompile("account.owner EXISTS OR account.number = 51"), 2)
+		require.NoError(t, err)
+		require.Equal(t, 6, total)
+		require.Equal(t, []indexer.HeightBucket{
+			{FromHeight: 1, ToHeight: 2, Count: 2},
+			{FromHeight: 3, ToHeight: 4, Count: 2},
+			{FromHeight: 5, ToHeight: 6, Count: 2},
+		}, buckets)
+
+		total, buckets, err = txIndexer.Count(context.Background(), query.MustCompile("tx.height >= 4"), 0)
+		require.NoError(t, err)
+		require.Equal(t, 4, total)
+		require.Nil(t, buckets)
+
+		total, buckets, err = blockIndexer.Count(context.Background(), query.MustCompile("block.height > 1"), 3)
+		require.NoError(t, err)
+		require.Equal(t, 4, total)
+		require.Equal(t, []indexer.HeightBucket{
+			{FromHeight: 1, ToHeight: 3, Count: 2},
+			{FromHeight: 4, ToHeight: 6, Count: 2},
+		}, buckets)
+	})
+
+	t.Run("SearchBlocks", func(t *testing.T) {
+		testCases := []struct {
+			q       string
+			results []int64
+		}{
+			{"block.height = 3", []int64{3}},
+			{"block.height >= 4", []int64{4, 5}},
+			{"finalize_block.proposer = 'FCAA002'", []int64{2}},
+			{"finalize_block.proposer = 'FCAA002' OR block.height > 4", []int64{2, 5}},
+			{"NOT finalize_block.proposer CONTAINS '00'", []int64{}},
+			{"NOT block.height = 3", []int64{1, 2, 4, 5}},
+			// The block search does not match the events of transactions.
+			{"account.number = 10", []int64{}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.q, func(t *testing.T) {
+				results, err := blockIndexer.Search(context.Background(), query.MustCompile(tc.q))
+				require.NoError(t, err)
+				require.Equal(t, tc.results, results)
+			})
+		}
+	})
+}
+
+func TestPrune(t *testing.T) {
+	es := newTestSink(t)
+	txIndexer := es.TxIndexer()
+	blockIndexer := es.BlockIndexer()
+
+	_, err := txIndexer.GetRetainHeight()
+	require.ErrorIs(t, err, state.ErrKeyNotFound)
+	_, err = blockIndexer.GetRetainHeight()
+	require.ErrorIs(t, err, state.ErrKeyNotFound)
+	require.NoError(t, txIndexer.SetRetainHeight(3))
+	require.NoError(t, blockIndexer.SetRetainHeight(4))
+	height, err := txIndexer.GetRetainHeight()
+	require.NoError(t, err)
+	require.EqualValues(t, 3, height)
+	height, err = blockIndexer.GetRetainHeight()
+	require.NoError(t, err)
+	require.EqualValues(t, 4, height)
+
+	for h := int64(1); h <= 5; h++ {
+		require.NoError(t, blockIndexer.Index(types.EventDataNewBlockEvents{
+			Height: h,
+			Events: []abci.Event{attrEvent("finalize_block", "proposer", "FCAA001")},
+		}))
+		require.NoError(t, txIndexer.Index(txResultWithEvents(h, 0, []abci.Event{attrEvent("account", "number", "1")})))
+	}
+
+	numPruned, retainHeight, err := txIndexer.Prune(3)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, numPruned)
+	require.EqualValues(t, 3, retainHeight)
+	results, _, err := txIndexer.Search(context.Background(), query.MustCompile("account.number = 1"), txindex.Pagination{})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	res, err := txIndexer.Get(types.Tx(txResultWithEvents(1, 0, nil).Tx).Hash())
+	require.NoError(t, err)
+	require.Nil(t, res)
+
+	// The events of the blocks are kept until the block indexer is pruned.
+	heights, err := blockIndexer.Search(context.Background(), query.MustCompile("finalize_block.proposer = 'FCAA001'"))
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 3, 4, 5}, heights)
+
+	numPruned, retainHeight, err = blockIndexer.Prune(4)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, numPruned)
+	require.EqualValues(t, 4, retainHeight)
+	heights, err = blockIndexer.Search(context.Background(), query.MustCompile("finalize_block.proposer = 'FCAA001'"))
+	require.NoError(t, err)
+	require.Equal(t, []int64{4, 5}, heights)
+	ok, err := blockIndexer.Has(3)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// The transactions of a block whose events were pruned are kept.
+	results, _, err = txIndexer.Search(context.Background(), query.MustCompile("tx.height = 3"), txindex.Pagination{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	// Only the blocks of which everything was pruned are deleted.
+	var numBlocks int
+	require.NoError(t, es.DB().QueryRow(`SELECT COUNT(*) FROM blocks;`).Scan(&numBlocks))
+	require.Equal(t, 3, numBlocks)
+
+	// Pruning again is a no-op.
+	numPruned, _, err = txIndexer.Prune(3)
+	require.NoError(t, err)
+	require.Zero(t, numPruned)
+}
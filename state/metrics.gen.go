@@ -62,6 +62,12 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "block_store_base_height",
 			Help:      "BlockStoreBaseHeight shows the first height at which a block is available",
 		}, labels).With(labelsAndValues...),
+		BlockStoreColdHeight: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "block_store_cold_height",
+			Help:      "BlockStoreColdHeight shows the height below which blocks were moved to the cold database",
+		}, labels).With(labelsAndValues...),
 		ABCIResultsBaseHeight: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -107,6 +113,7 @@ func NopMetrics() *Metrics {
 		PruningServiceBlockIndexerRetainHeight: discard.NewGauge(),
 		ApplicationBlockRetainHeight:           discard.NewGauge(),
 		BlockStoreBaseHeight:                   discard.NewGauge(),
+		BlockStoreColdHeight:                   discard.NewGauge(),
 		ABCIResultsBaseHeight:                  discard.NewGauge(),
 		TxIndexerBaseHeight:                    discard.NewGauge(),
 		BlockIndexerBaseHeight:                 discard.NewGauge(),
@@ -48,6 +48,10 @@ type Metrics struct {
 	// a block is available
 	BlockStoreBaseHeight metrics.Gauge
 
+	// BlockStoreColdHeight shows the height below which blocks
+	// were moved to the cold database
+	BlockStoreColdHeight metrics.Gauge
+
 	// ABCIResultsBaseHeight shows the first height at which
 	// abci results are available
 	ABCIResultsBaseHeight metrics.Gauge
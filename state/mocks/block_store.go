@@ -50,6 +50,34 @@ func (_m *BlockStore) Close() error {
 	return r0
 }
 
+// DeleteBlocksAbove provides a mock function with given fields: height
+func (_m *BlockStore) DeleteBlocksAbove(height int64) (int64, error) {
+	ret := _m.Called(height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBlocksAbove")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(height)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(height)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteLatestBlock provides a mock function with no fields
 func (_m *BlockStore) DeleteLatestBlock() error {
 	ret := _m.Called()
@@ -220,6 +220,34 @@ func (_m *Store) LoadConsensusParams(height int64) (types.ConsensusParams, error
 	return r0, r1
 }
 
+// LoadConsensusParamsChangeHeight provides a mock function with given fields: height
+func (_m *Store) LoadConsensusParamsChangeHeight(height int64) (int64, error) {
+	ret := _m.Called(height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoadConsensusParamsChangeHeight")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(height)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(height)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // LoadFinalizeBlockResponse provides a mock function with given fields: height
 func (_m *Store) LoadFinalizeBlockResponse(height int64) (*v2.FinalizeBlockResponse, error) {
 	ret := _m.Called(height)
@@ -366,6 +394,34 @@ func (_m *Store) LoadValidators(height int64) (*types.ValidatorSet, error) {
 	return r0, r1
 }
 
+// LoadValidatorsChangeHeight provides a mock function with given fields: height
+func (_m *Store) LoadValidatorsChangeHeight(height int64) (int64, error) {
+	ret := _m.Called(height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoadValidatorsChangeHeight")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(height)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(height)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PruneABCIResponses provides a mock function with given fields: targetRetainHeight, forceCompact
 func (_m *Store) PruneABCIResponses(targetRetainHeight int64, forceCompact bool) (int64, int64, error) {
 	ret := _m.Called(targetRetainHeight, forceCompact)
@@ -429,6 +485,24 @@ func (_m *Store) PruneStates(fromHeight int64, toHeight int64, evidenceThreshold
 	return r0, r1
 }
 
+// Rewind provides a mock function with given fields: _a0
+func (_m *Store) Rewind(_a0 state.State) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rewind")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(state.State) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Save provides a mock function with given fields: _a0
 func (_m *Store) Save(_a0 state.State) error {
 	ret := _m.Called(_a0)
@@ -519,6 +593,24 @@ func (_m *Store) SaveFinalizeBlockResponse(height int64, res *v2.FinalizeBlockRe
 	return r0
 }
 
+// SaveValidatorSet provides a mock function with given fields: height, valSet
+func (_m *Store) SaveValidatorSet(height int64, valSet *types.ValidatorSet) error {
+	ret := _m.Called(height, valSet)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveValidatorSet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, *types.ValidatorSet) error); ok {
+		r0 = rf(height, valSet)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetOfflineStateSyncHeight provides a mock function with given fields: height
 func (_m *Store) SetOfflineStateSyncHeight(height int64) error {
 	ret := _m.Called(height)
@@ -2,6 +2,8 @@ package state
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/cometbft/cometbft/v2/libs/service"
 	"github.com/cometbft/cometbft/v2/state/indexer"
 	"github.com/cometbft/cometbft/v2/state/txindex"
+	cmttime "github.com/cometbft/cometbft/v2/types/time"
 )
 
 var (
@@ -35,20 +38,41 @@ type Pruner struct {
 	stateStore   Store
 	blockIndexer indexer.BlockIndexer
 	txIndexer    txindex.TxIndexer
+	// Additional indexer sinks pruned to the retain heights of the indexers
+	indexerSinks []txindex.Sink
 	interval     time.Duration
 	observer     PrunerObserver
 	metrics      *Metrics
 
+	// Block store to move old blocks to the cold database with, if enabled
+	coldStore       ColdBlockStore
+	coldAfterBlocks int64
+	coldAfterTime   time.Duration
+
+	// The data less than these durations old is never pruned, whatever the
+	// retain heights, if not zero
+	blocksRetainTime      time.Duration
+	abciResultsRetainTime time.Duration
+	indexerRetainTime     time.Duration
+
 	// Preserve the number of state entries pruned.
 	// Used to calculated correctly when to trigger compactions
 	prunedStates uint64
 }
 
 type prunerConfig struct {
-	dcEnabled bool
-	interval  time.Duration
-	observer  PrunerObserver
-	metrics   *Metrics
+	dcEnabled       bool
+	interval        time.Duration
+	observer        PrunerObserver
+	metrics         *Metrics
+	coldStore       ColdBlockStore
+	coldAfterBlocks int64
+	coldAfterTime   time.Duration
+	indexerSinks    []txindex.Sink
+
+	blocksRetainTime      time.Duration
+	abciResultsRetainTime time.Duration
+	indexerRetainTime     time.Duration
 }
 
 func defaultPrunerConfig() *prunerConfig {
@@ -88,6 +112,37 @@ func WithPrunerMetrics(metrics *Metrics) PrunerOption {
 	}
 }
 
+// WithPrunerColdStorage makes the pruner move the blocks which are more than
+// afterBlocks blocks behind the latest height, and older than afterTime, to the
+// cold database of cs after pruning blocks. A zero afterBlocks or afterTime is
+// ignored.
+func WithPrunerColdStorage(cs ColdBlockStore, afterBlocks int64, afterTime time.Duration) PrunerOption {
+	return func(p *prunerConfig) {
+		p.coldStore = cs
+		p.coldAfterBlocks = afterBlocks
+		p.coldAfterTime = afterTime
+	}
+}
+
+// WithPrunerRetainTime makes the pruner keep the blocks, ABCI results and
+// indexed data less than blocks, abciResults and indexers old, respectively,
+// even if the retain heights allow pruning them. The retain heights are
+// lowered to the height of the first block within each duration, using the
+// block times. A zero duration is ignored.
+func WithPrunerRetainTime(blocks, abciResults, indexers time.Duration) PrunerOption {
+	return func(p *prunerConfig) {
+		p.blocksRetainTime = blocks
+		p.abciResultsRetainTime = abciResults
+		p.indexerRetainTime = indexers
+	}
+}
+
+// WithPrunerIndexerSinks makes the pruner set the retain heights of the tx and
+// block indexers on the indexers of sinks as well, and prune them along.
+func WithPrunerIndexerSinks(sinks ...txindex.Sink) PrunerOption {
+	return func(p *prunerConfig) { p.indexerSinks = sinks }
+}
+
 // NewPruner creates a service that controls background pruning of node data.
 //
 // Assumes that the initial application and data companion retain heights have
@@ -108,12 +163,21 @@ func NewPruner(
 		bs:           bs,
 		txIndexer:    txIndexer,
 		blockIndexer: blockIndexer,
+		indexerSinks: cfg.indexerSinks,
 		stateStore:   stateStore,
 		logger:       logger,
 		interval:     cfg.interval,
 		observer:     cfg.observer,
 		metrics:      cfg.metrics,
 		dcEnabled:    cfg.dcEnabled,
+
+		coldStore:       cfg.coldStore,
+		coldAfterBlocks: cfg.coldAfterBlocks,
+		coldAfterTime:   cfg.coldAfterTime,
+
+		blocksRetainTime:      cfg.blocksRetainTime,
+		abciResultsRetainTime: cfg.abciResultsRetainTime,
+		indexerRetainTime:     cfg.indexerRetainTime,
 	}
 	p.BaseService = *service.NewBaseService(logger, "Pruner", p)
 	return p
@@ -239,12 +303,12 @@ func (p *Pruner) SetTxIndexerRetainHeight(height int64) error {
 		if !errors.Is(err, ErrKeyNotFound) {
 			return err
 		}
-		return p.txIndexer.SetRetainHeight(height)
+		return p.setTxIndexerRetainHeight(height)
 	}
 	if currentRetainHeight > height {
 		return ErrPrunerCannotLowerRetainHeight
 	}
-	if err := p.txIndexer.SetRetainHeight(height); err != nil {
+	if err := p.setTxIndexerRetainHeight(height); err != nil {
 		return err
 	}
 	p.metrics.PruningServiceTxIndexerRetainHeight.Set(float64(height))
@@ -265,18 +329,46 @@ func (p *Pruner) SetBlockIndexerRetainHeight(height int64) error {
 		if !errors.Is(err, ErrKeyNotFound) {
 			return err
 		}
-		return p.blockIndexer.SetRetainHeight(height)
+		return p.setBlockIndexerRetainHeight(height)
 	}
 	if currentRetainHeight > height {
 		return ErrPrunerCannotLowerRetainHeight
 	}
-	if err := p.blockIndexer.SetRetainHeight(height); err != nil {
+	if err := p.setBlockIndexerRetainHeight(height); err != nil {
 		return err
 	}
 	p.metrics.PruningServiceBlockIndexerRetainHeight.Set(float64(height))
 	return nil
 }
 
+// setTxIndexerRetainHeight sets the retain height of the tx indexer and of
+// the tx indexers of the additional sinks.
+func (p *Pruner) setTxIndexerRetainHeight(height int64) error {
+	if err := p.txIndexer.SetRetainHeight(height); err != nil {
+		return err
+	}
+	for _, s := range p.indexerSinks {
+		if err := s.TxIndexer.SetRetainHeight(height); err != nil {
+			return fmt.Errorf("setting the tx indexer retain height of sink %s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// setBlockIndexerRetainHeight sets the retain height of the block indexer and
+// of the block indexers of the additional sinks.
+func (p *Pruner) setBlockIndexerRetainHeight(height int64) error {
+	if err := p.blockIndexer.SetRetainHeight(height); err != nil {
+		return err
+	}
+	for _, s := range p.indexerSinks {
+		if err := s.BlockIndexer.SetRetainHeight(height); err != nil {
+			return fmt.Errorf("setting the block indexer retain height of sink %s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
 // GetApplicationRetainHeight is a convenience method for accessing the
 // GetApplicationRetainHeight method of the underlying state store.
 func (p *Pruner) GetApplicationRetainHeight() (int64, error) {
@@ -344,6 +436,11 @@ func (p *Pruner) pruneBlocks() {
 				})
 			}
 			lastRetainHeight = newRetainHeight
+			// Blocks are moved to the cold database by the same routine as
+			// they are pruned, so that both never run concurrently.
+			if p.coldStore != nil {
+				p.moveBlocksToColdStore()
+			}
 			time.Sleep(p.interval)
 		}
 	}
@@ -377,6 +474,7 @@ func (p *Pruner) pruneTxIndexerToRetainHeight(lastRetainHeight int64) int64 {
 		p.logger.Error("Failed to get Indexer retain height", "err", err)
 		return lastRetainHeight
 	}
+	targetRetainHeight = p.limitRetainHeight(targetRetainHeight, p.indexerRetainTime)
 
 	if lastRetainHeight >= targetRetainHeight {
 		return lastRetainHeight
@@ -389,6 +487,11 @@ func (p *Pruner) pruneTxIndexerToRetainHeight(lastRetainHeight int64) int64 {
 		p.metrics.TxIndexerBaseHeight.Set(float64(newTxIndexerRetainHeight))
 		p.logger.Debug("Pruned tx indexer", "count", numPrunedTxIndexer, "newTxIndexerRetainHeight", newTxIndexerRetainHeight)
 	}
+	for _, s := range p.indexerSinks {
+		if _, _, err := s.TxIndexer.Prune(targetRetainHeight); err != nil {
+			p.logger.Error("Failed to prune tx indexer", "sink", s.Name, "err", err, "targetRetainHeight", targetRetainHeight)
+		}
+	}
 	return newTxIndexerRetainHeight
 }
 
@@ -403,6 +506,7 @@ func (p *Pruner) pruneBlockIndexerToRetainHeight(lastRetainHeight int64) int64 {
 		p.logger.Error("Failed to get Indexer retain height", "err", err)
 		return lastRetainHeight
 	}
+	targetRetainHeight = p.limitRetainHeight(targetRetainHeight, p.indexerRetainTime)
 
 	if lastRetainHeight >= targetRetainHeight {
 		return lastRetainHeight
@@ -415,11 +519,16 @@ func (p *Pruner) pruneBlockIndexerToRetainHeight(lastRetainHeight int64) int64 {
 		p.metrics.BlockIndexerBaseHeight.Set(float64(newBlockIndexerRetainHeight))
 		p.logger.Debug("Pruned block indexer", "count", numPrunedBlockIndexer, "newBlockIndexerRetainHeight", newBlockIndexerRetainHeight)
 	}
+	for _, s := range p.indexerSinks {
+		if _, _, err := s.BlockIndexer.Prune(targetRetainHeight); err != nil {
+			p.logger.Error("Failed to prune block indexer", "sink", s.Name, "err", err, "targetRetainHeight", targetRetainHeight)
+		}
+	}
 	return newBlockIndexerRetainHeight
 }
 
 func (p *Pruner) pruneBlocksToRetainHeight(lastRetainHeight int64) int64 {
-	targetRetainHeight := p.findMinBlockRetainHeight()
+	targetRetainHeight := p.limitRetainHeight(p.findMinBlockRetainHeight(), p.blocksRetainTime)
 	if targetRetainHeight == lastRetainHeight {
 		return lastRetainHeight
 	}
@@ -445,6 +554,7 @@ func (p *Pruner) pruneABCIResToRetainHeight(lastRetainHeight int64) int64 {
 		}
 		return lastRetainHeight
 	}
+	targetRetainHeight = p.limitRetainHeight(targetRetainHeight, p.abciResultsRetainTime)
 
 	if lastRetainHeight == targetRetainHeight {
 		return lastRetainHeight
@@ -470,6 +580,69 @@ func (p *Pruner) pruneABCIResToRetainHeight(lastRetainHeight int64) int64 {
 	return newRetainHeight
 }
 
+func (p *Pruner) moveBlocksToColdStore() {
+	height := p.findColdStorageHeight()
+	if height <= p.coldStore.ColdHeight() {
+		return
+	}
+	moved, err := p.coldStore.MoveBlocksToColdStore(height)
+	coldHeight := p.coldStore.ColdHeight()
+	if err != nil {
+		p.logger.Error("Failed to move blocks to the cold database", "err", err, "height", height, "coldHeight", coldHeight)
+	} else if moved > 0 {
+		p.logger.Debug("Moved blocks to the cold database", "count", moved, "coldHeight", coldHeight)
+	}
+	p.metrics.BlockStoreColdHeight.Set(float64(coldHeight))
+}
+
+// findColdStorageHeight returns the height below which blocks must be in the
+// cold database.
+func (p *Pruner) findColdStorageHeight() int64 {
+	height := p.bs.Height()
+	if p.coldAfterBlocks > 0 {
+		height -= p.coldAfterBlocks
+	}
+	if p.coldAfterTime <= 0 {
+		return height
+	}
+	// Find the first block which is not old enough, between the blocks which
+	// were already moved and height.
+	from := max(p.coldStore.ColdHeight(), p.bs.Base())
+	return p.findFirstBlockNotBefore(from, height, cmttime.Now().Add(-p.coldAfterTime))
+}
+
+// findTimeRetainHeight returns the lowest height to retain so that the blocks
+// which are less than retainTime old are kept: the height of the first such
+// block, or the latest height if all blocks are older. The heights below the
+// block store base are considered older. It returns 0 if retainTime is 0.
+func (p *Pruner) findTimeRetainHeight(retainTime time.Duration) int64 {
+	if retainTime <= 0 {
+		return 0
+	}
+	return p.findFirstBlockNotBefore(p.bs.Base(), p.bs.Height(), cmttime.Now().Add(-retainTime))
+}
+
+// limitRetainHeight lowers the retain height targetRetainHeight, if set, to
+// the height needed to keep the data of the last retainTime.
+func (p *Pruner) limitRetainHeight(targetRetainHeight int64, retainTime time.Duration) int64 {
+	if targetRetainHeight <= 0 || retainTime <= 0 {
+		return targetRetainHeight
+	}
+	return min(targetRetainHeight, p.findTimeRetainHeight(retainTime))
+}
+
+// findFirstBlockNotBefore returns the first height between from and to
+// (exclusive) of a block whose time is not before t, or to if there is none.
+func (p *Pruner) findFirstBlockNotBefore(from, to int64, t time.Time) int64 {
+	if to <= from {
+		return to
+	}
+	return from + int64(sort.Search(int(to-from), func(i int) bool {
+		meta := p.bs.LoadBlockMeta(from + int64(i))
+		return meta == nil || !meta.Header.Time.Before(t)
+	}))
+}
+
 func (p *Pruner) findMinBlockRetainHeight() int64 {
 	appRetainHeight, err := p.stateStore.GetApplicationRetainHeight()
 	if err != nil {
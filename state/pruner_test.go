@@ -17,6 +17,7 @@ import (
 	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
 	sm "github.com/cometbft/cometbft/v2/state"
 	blockidxkv "github.com/cometbft/cometbft/v2/state/indexer/block/kv"
+	"github.com/cometbft/cometbft/v2/state/indexer/sink/jsonl"
 	"github.com/cometbft/cometbft/v2/state/txindex"
 	"github.com/cometbft/cometbft/v2/state/txindex/kv"
 	"github.com/cometbft/cometbft/v2/store"
@@ -152,7 +153,7 @@ func containsAllTxs(results []*abci.TxResult, txs []string) bool {
 	return true
 }
 
-func createTestSetup(t *testing.T) (*sm.Pruner, *kv.TxIndex, blockidxkv.BlockerIndexer) {
+func createTestSetup(t *testing.T) (*sm.Pruner, *kv.TxIndex, *blockidxkv.BlockerIndexer) {
 	t.Helper()
 	config := test.ResetTestRoot("pruner_test")
 	t.Cleanup(func() {
@@ -190,7 +191,7 @@ func createTestSetup(t *testing.T) (*sm.Pruner, *kv.TxIndex, blockidxkv.BlockerI
 	bs := store.NewBlockStore(blockDB)
 	pruner := sm.NewPruner(stateStore, bs, blockIndexer, txIndexer, log.TestingLogger())
 
-	return pruner, txIndexer, *blockIndexer
+	return pruner, txIndexer, blockIndexer
 }
 
 func getEventsAndResults(height int64) (types.EventDataNewBlockEvents, *abci.TxResult, *abci.TxResult) {
@@ -225,6 +226,39 @@ func getEventsAndResults(height int64) (types.EventDataNewBlockEvents, *abci.TxR
 	return events, txResult1, txResult2
 }
 
+func TestPruneIndexerSinks(t *testing.T) {
+	es, err := jsonl.NewEventSink(t.TempDir(), "test", 1)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = es.Stop() })
+
+	memDB := db.NewMemDB()
+	txIndexer := kv.NewTxIndex(memDB)
+	blockIndexer := blockidxkv.New(db.NewPrefixDB(memDB, []byte("block_events")))
+	stateStore := sm.NewStore(db.NewMemDB(), sm.StoreOptions{})
+	pruner := sm.NewPruner(stateStore, store.NewBlockStore(db.NewMemDB()), blockIndexer, txIndexer, log.TestingLogger(),
+		sm.WithPrunerIndexerSinks(txindex.Sink{Name: "jsonl", TxIndexer: es.TxIndexer(), BlockIndexer: es.BlockIndexer()}))
+
+	for height := int64(1); height <= 4; height++ {
+		events, txResult1, txResult2 := getEventsAndResults(height)
+		require.NoError(t, es.IndexBlockEvents(events))
+		require.NoError(t, es.IndexTxEvents([]*abci.TxResult{txResult1, txResult2}))
+	}
+
+	require.NoError(t, pruner.SetTxIndexerRetainHeight(3))
+	require.NoError(t, pruner.SetBlockIndexerRetainHeight(3))
+	retainHeight, err := es.TxIndexer().GetRetainHeight()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), retainHeight)
+
+	// the heights of the sink are pruned once both indexers are pruned
+	pruner.PruneTxIndexerToRetainHeight(0)
+	require.Len(t, es.Files(), 4)
+	pruner.PruneBlockIndexerToRetainHeight(0)
+	files := es.Files()
+	require.Len(t, files, 2)
+	require.Equal(t, int64(3), files[0].FromHeight)
+}
+
 // When trying to prune the only block in the store it should not succeed
 // State should also not be pruned.
 func TestPruningWithHeight1(t *testing.T) {
@@ -277,3 +311,47 @@ func TestPruningWithHeight1(t *testing.T) {
 	require.Equal(t, uint64(0), pruned)
 	require.NoError(t, err)
 }
+
+func TestPrunerRetainTime(t *testing.T) {
+	state, stateDB, _ := makeState(1, 1, "retain-time-chain")
+	stateStore := sm.NewStore(stateDB, sm.StoreOptions{})
+	bs := store.NewBlockStore(db.NewMemDB())
+	memDB := db.NewMemDB()
+	txIndexer := kv.NewTxIndex(memDB)
+	blockIndexer := blockidxkv.New(db.NewPrefixDB(memDB, []byte("block_events")))
+
+	// Block h is 11 - h hours old.
+	now := time.Now()
+	for h := int64(1); h <= 10; h++ {
+		block := makeBlock(state, h, new(types.Commit))
+		block.Time = now.Add(-time.Duration(11-h) * time.Hour)
+		partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		bs.SaveBlock(block, partSet, &types.Commit{Height: h})
+		require.NoError(t, stateStore.SaveFinalizeBlockResponse(h, &abci.FinalizeBlockResponse{AppHash: []byte{byte(h)}}))
+		events, _, _ := getEventsAndResults(h)
+		require.NoError(t, blockIndexer.Index(events))
+	}
+
+	pruner := sm.NewPruner(stateStore, bs, blockIndexer, txIndexer, log.TestingLogger(),
+		sm.WithPrunerRetainTime(0, 5*time.Hour+30*time.Minute, 8*time.Hour+30*time.Minute))
+
+	require.Zero(t, pruner.FindTimeRetainHeight(0))
+	require.EqualValues(t, 6, pruner.FindTimeRetainHeight(5*time.Hour+30*time.Minute))
+	require.EqualValues(t, 1, pruner.FindTimeRetainHeight(24*time.Hour))
+	require.EqualValues(t, 10, pruner.FindTimeRetainHeight(time.Minute))
+
+	// The retain heights are lowered to keep the data of the retain times.
+	require.NoError(t, stateStore.SaveABCIResRetainHeight(9))
+	require.EqualValues(t, 6, pruner.PruneABCIResToRetainHeight(0))
+	_, err := stateStore.LoadFinalizeBlockResponse(5)
+	require.Error(t, err)
+	_, err = stateStore.LoadFinalizeBlockResponse(6)
+	require.NoError(t, err)
+
+	require.NoError(t, pruner.SetBlockIndexerRetainHeight(9))
+	require.EqualValues(t, 3, pruner.PruneBlockIndexerToRetainHeight(0))
+	heights, err := blockIndexer.Search(context.Background(), query.MustCompile("block.height <= 4"))
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 4}, heights)
+}
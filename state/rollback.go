@@ -124,3 +124,163 @@ func Rollback(bs BlockStore, ss Store, removeBlock bool) (int64, []byte, error)
 
 	return rolledBackState.LastBlockHeight, rolledBackState.AppHash, nil
 }
+
+// RollbackPlan describes the changes made by RollbackTo.
+type RollbackPlan struct {
+	// Height and AppHash are those of the state after the rollback.
+	Height  int64  `json:"height"`
+	AppHash []byte `json:"app_hash"`
+	// StateHeight is the height of the state before the rollback. The ABCI
+	// responses, validator sets and consensus params stored for the heights
+	// after Height, up to StateHeight, are deleted.
+	StateHeight int64 `json:"state_height"`
+	// The blocks from DeletedBlocksFrom to DeletedBlocksTo, if not zero, are
+	// removed from the block store.
+	DeletedBlocksFrom int64 `json:"deleted_blocks_from"`
+	DeletedBlocksTo   int64 `json:"deleted_blocks_to"`
+}
+
+// RollbackTo overwrites the current CometBFT state with the state at the
+// given height, rebuilt from the stored blocks, validator sets and consensus
+// params, and deletes the state data of the later heights. The blocks above
+// height + 1 are removed as well, so that block height + 1 is executed again on
+// restart; if removeBlocks is true, block height + 1 is removed too.
+//
+// The state is rewound before the blocks are removed, each in a single batch,
+// so an interrupted rollback is completed by running it again. If dryRun is
+// true, nothing is changed and the returned plan describes what would be.
+// Note that this function does not affect application state.
+func RollbackTo(bs BlockStore, ss Store, height int64, removeBlocks, dryRun bool) (*RollbackPlan, error) {
+	currentState, err := ss.Load()
+	if err != nil {
+		return nil, err
+	}
+	if currentState.IsEmpty() {
+		return nil, errors.New("no state found")
+	}
+
+	switch {
+	case height < currentState.InitialHeight:
+		return nil, fmt.Errorf("cannot roll back below the initial height %d", currentState.InitialHeight)
+	case height < bs.Base():
+		return nil, fmt.Errorf("cannot roll back below the block store base %d", bs.Base())
+	case height > currentState.LastBlockHeight:
+		return nil, fmt.Errorf("cannot roll back to height %d, above the state height %d",
+			height, currentState.LastBlockHeight)
+	}
+
+	plan := &RollbackPlan{
+		Height:      height,
+		AppHash:     currentState.AppHash,
+		StateHeight: currentState.LastBlockHeight,
+	}
+	var rolledBackState State
+	if height < currentState.LastBlockHeight {
+		// The block store height is equal to the state height, or one above if
+		// the node stopped before saving the state.
+		if storeHeight := bs.Height(); storeHeight != currentState.LastBlockHeight &&
+			storeHeight != currentState.LastBlockHeight+1 {
+			return nil, fmt.Errorf("statestore height (%d) is not one below or equal to blockstore height (%d)",
+				currentState.LastBlockHeight, storeHeight)
+		}
+		rolledBackState, err = rollbackState(bs, ss, currentState, height)
+		if err != nil {
+			return nil, err
+		}
+		plan.AppHash = rolledBackState.AppHash
+	}
+
+	keptHeight := height + 1
+	if removeBlocks {
+		keptHeight = height
+	}
+	if storeHeight := bs.Height(); storeHeight > keptHeight {
+		plan.DeletedBlocksFrom, plan.DeletedBlocksTo = keptHeight+1, storeHeight
+	}
+	if dryRun {
+		return plan, nil
+	}
+
+	if height < currentState.LastBlockHeight {
+		if err := ss.Rewind(rolledBackState); err != nil {
+			return nil, fmt.Errorf("failed to save rolled back state: %w", err)
+		}
+	}
+	if plan.DeletedBlocksTo != 0 {
+		if _, err := bs.DeleteBlocksAbove(keptHeight); err != nil {
+			return nil, fmt.Errorf("failed to remove blocks from blockstore: %w", err)
+		}
+	}
+	return plan, nil
+}
+
+// rollbackState returns the state at height, a height below the one of
+// currentState.
+func rollbackState(bs BlockStore, ss Store, currentState State, height int64) (State, error) {
+	rollbackBlock := bs.LoadBlockMeta(height)
+	if rollbackBlock == nil {
+		return State{}, fmt.Errorf("block at height %d not found", height)
+	}
+	// The app hash and last results hash are only agreed upon in the
+	// following block.
+	nextBlock := bs.LoadBlockMeta(height + 1)
+	if nextBlock == nil {
+		return State{}, fmt.Errorf("block at height %d not found", height+1)
+	}
+
+	lastValidators, err := ss.LoadValidators(height)
+	if err != nil {
+		return State{}, err
+	}
+	validators, err := ss.LoadValidators(height + 1)
+	if err != nil {
+		return State{}, err
+	}
+	nextValidators, err := ss.LoadValidators(height + 2)
+	if err != nil {
+		return State{}, err
+	}
+	params, err := ss.LoadConsensusParams(height + 1)
+	if err != nil {
+		return State{}, err
+	}
+
+	// The state at height saved its next validators at height + 2, and its
+	// params at height + 1, along with the heights at which they last changed.
+	valChangeHeight, err := ss.LoadValidatorsChangeHeight(height + 2)
+	if err != nil {
+		return State{}, err
+	}
+	paramsChangeHeight, err := ss.LoadConsensusParamsChangeHeight(height + 1)
+	if err != nil {
+		return State{}, err
+	}
+
+	return State{
+		Version: cmtstate.Version{
+			Consensus: cmtversion.Consensus{
+				Block: version.BlockProtocol,
+				App:   params.Version.App,
+			},
+			Software: version.CMTSemVer,
+		},
+		// immutable fields
+		ChainID:       currentState.ChainID,
+		InitialHeight: currentState.InitialHeight,
+
+		LastBlockHeight: rollbackBlock.Header.Height,
+		LastBlockID:     rollbackBlock.BlockID,
+		LastBlockTime:   rollbackBlock.Header.Time,
+
+		NextValidators:              nextValidators,
+		Validators:                  validators,
+		LastValidators:              lastValidators,
+		LastHeightValidatorsChanged: valChangeHeight,
+
+		ConsensusParams:                  params,
+		LastHeightConsensusParamsChanged: paramsChangeHeight,
+
+		LastResultsHash: nextBlock.Header.LastResultsHash,
+		AppHash:         nextBlock.Header.AppHash,
+	}, nil
+}
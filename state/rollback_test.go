@@ -10,6 +10,7 @@ import (
 	dbm "github.com/cometbft/cometbft-db"
 	cmtstate "github.com/cometbft/cometbft/api/cometbft/state/v2"
 	cmtversion "github.com/cometbft/cometbft/api/cometbft/version/v1"
+	abci "github.com/cometbft/cometbft/v2/abci/types"
 	"github.com/cometbft/cometbft/v2/crypto"
 	"github.com/cometbft/cometbft/v2/crypto/tmhash"
 	"github.com/cometbft/cometbft/v2/state"
@@ -287,3 +288,156 @@ func makeBlockIDRandom() types.BlockID {
 		},
 	}
 }
+
+func TestRollbackTo(t *testing.T) {
+	const (
+		initialHeight int64 = 10
+		height        int64 = 15
+	)
+	blockStore := store.NewBlockStore(dbm.NewMemDB())
+	stateStore := state.NewStore(dbm.NewMemDB(), state.StoreOptions{DiscardABCIResponses: false})
+
+	valSet, _ := types.RandValidatorSet(5, 10)
+	params := types.DefaultConsensusParams()
+	params.Version.App = 10
+
+	// Build a chain where the validators change at height 14, and the
+	// consensus params at height 14 as well.
+	states := make(map[int64]state.State)
+	prevState := state.State{
+		Version: cmtstate.Version{
+			Consensus: cmtversion.Consensus{Block: version.BlockProtocol, App: 10},
+			Software:  version.CMTSemVer,
+		},
+		ChainID:                          "test-chain",
+		InitialHeight:                    initialHeight,
+		AppHash:                          crypto.CRandBytes(tmhash.Size),
+		LastResultsHash:                  crypto.CRandBytes(tmhash.Size),
+		LastValidators:                   valSet,
+		Validators:                       valSet,
+		NextValidators:                   valSet.CopyIncrementProposerPriority(1),
+		LastHeightValidatorsChanged:      initialHeight + 1,
+		ConsensusParams:                  *params,
+		LastHeightConsensusParamsChanged: initialHeight + 1,
+	}
+	for h := initialHeight; h <= height; h++ {
+		block := &types.Block{
+			Header: types.Header{
+				Version:            prevState.Version.Consensus,
+				ChainID:            prevState.ChainID,
+				Time:               cmttime.Now(),
+				Height:             h,
+				AppHash:            prevState.AppHash,
+				LastResultsHash:    prevState.LastResultsHash,
+				ValidatorsHash:     prevState.Validators.Hash(),
+				NextValidatorsHash: prevState.NextValidators.Hash(),
+				ConsensusHash:      prevState.ConsensusParams.Hash(),
+				ProposerAddress:    prevState.Validators.GetProposer().Address,
+			},
+			LastCommit: &types.Commit{Height: h - 1},
+		}
+		partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		blockStore.SaveBlock(block, partSet, &types.Commit{Height: h})
+
+		nextState := prevState.Copy()
+		nextState.LastBlockHeight = h
+		nextState.LastBlockID = types.BlockID{Hash: block.Hash(), PartSetHeader: partSet.Header()}
+		nextState.LastBlockTime = block.Time
+		nextState.AppHash = crypto.CRandBytes(tmhash.Size)
+		nextState.LastResultsHash = crypto.CRandBytes(tmhash.Size)
+		nextState.LastValidators = prevState.Validators
+		nextState.Validators = prevState.NextValidators
+		nextState.NextValidators = prevState.NextValidators.CopyIncrementProposerPriority(1)
+		if h == 12 {
+			nextState.NextValidators, _ = types.RandValidatorSet(4, 10)
+			nextState.LastHeightValidatorsChanged = h + 2
+		}
+		if h == 13 {
+			params.Block.MaxBytes = 1000
+			nextState.ConsensusParams = *params
+			nextState.LastHeightConsensusParamsChanged = h + 1
+		}
+		if h == initialHeight {
+			require.NoError(t, stateStore.Bootstrap(nextState))
+		} else {
+			require.NoError(t, stateStore.Save(nextState))
+		}
+		require.NoError(t, stateStore.SaveFinalizeBlockResponse(h, &abci.FinalizeBlockResponse{AppHash: nextState.AppHash}))
+		states[h] = nextState
+		prevState = nextState
+	}
+
+	_, err := state.RollbackTo(blockStore, stateStore, height+1, false, false)
+	require.Error(t, err)
+	_, err = state.RollbackTo(blockStore, stateStore, initialHeight-1, false, false)
+	require.Error(t, err)
+
+	// Rolling back across the changes restores the heights at which the
+	// validators and params last changed.
+	plan, err := state.RollbackTo(blockStore, stateStore, 13, false, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 15, plan.DeletedBlocksFrom)
+	loadedState, err := stateStore.Load()
+	require.NoError(t, err)
+	require.Equal(t, states[13].LastBlockID, loadedState.LastBlockID)
+	require.Equal(t, states[13].NextValidators.Hash(), loadedState.NextValidators.Hash())
+	require.EqualValues(t, 14, loadedState.LastHeightValidatorsChanged)
+	require.EqualValues(t, 14, loadedState.LastHeightConsensusParamsChanged)
+
+	// A dry run reports what would be removed, without changing anything.
+	plan, err = state.RollbackTo(blockStore, stateStore, 11, false, true)
+	require.NoError(t, err)
+	require.Equal(t, &state.RollbackPlan{
+		Height:            11,
+		AppHash:           states[11].AppHash,
+		StateHeight:       13,
+		DeletedBlocksFrom: 13,
+		DeletedBlocksTo:   14,
+	}, plan)
+	loadedState, err = stateStore.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, 13, loadedState.LastBlockHeight)
+	require.EqualValues(t, 14, blockStore.Height())
+
+	plan, err = state.RollbackTo(blockStore, stateStore, 11, true, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 12, plan.DeletedBlocksFrom)
+	require.Equal(t, int64(11), blockStore.Height())
+	require.Nil(t, blockStore.LoadBlockMeta(12))
+
+	want := states[11]
+	loadedState, err = stateStore.Load()
+	require.NoError(t, err)
+	require.Equal(t, want.LastBlockHeight, loadedState.LastBlockHeight)
+	require.Equal(t, want.LastBlockID, loadedState.LastBlockID)
+	require.Equal(t, want.AppHash, loadedState.AppHash)
+	require.Equal(t, want.LastResultsHash, loadedState.LastResultsHash)
+	require.Equal(t, want.LastValidators.Hash(), loadedState.LastValidators.Hash())
+	require.Equal(t, want.Validators.Hash(), loadedState.Validators.Hash())
+	require.Equal(t, want.NextValidators.Hash(), loadedState.NextValidators.Hash())
+	require.Equal(t, want.ConsensusParams.Hash(), loadedState.ConsensusParams.Hash())
+	require.Equal(t, want.LastHeightValidatorsChanged, loadedState.LastHeightValidatorsChanged)
+	require.Equal(t, want.LastHeightConsensusParamsChanged, loadedState.LastHeightConsensusParamsChanged)
+
+	// The state data of the later heights is removed, while the validators and
+	// params the rolled back state refers to are still loadable.
+	for h := int64(12); h <= height; h++ {
+		_, err := stateStore.LoadFinalizeBlockResponse(h)
+		require.Error(t, err, "height %d", h)
+	}
+	_, err = stateStore.LoadValidators(14)
+	require.Error(t, err)
+	vals, err := stateStore.LoadValidators(13)
+	require.NoError(t, err)
+	require.Equal(t, states[11].NextValidators.Hash(), vals.Hash())
+	loadedParams, err := stateStore.LoadConsensusParams(12)
+	require.NoError(t, err)
+	require.Equal(t, states[11].ConsensusParams.Hash(), loadedParams.Hash())
+
+	// Rolling back again to the same height is a no-op.
+	plan, err = state.RollbackTo(blockStore, stateStore, 11, true, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(11), plan.StateHeight)
+	require.Zero(t, plan.DeletedBlocksTo)
+}
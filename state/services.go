@@ -38,10 +38,20 @@ type BlockStore interface {
 	LoadBlockExtendedCommit(height int64) *types.ExtendedCommit
 
 	DeleteLatestBlock() error
+	DeleteBlocksAbove(height int64) (int64, error)
 
 	Close() error
 }
 
+// ColdBlockStore is implemented by the block stores able to move old blocks to
+// a secondary, cold, database.
+type ColdBlockStore interface {
+	// ColdHeight returns the height below which the blocks were moved.
+	ColdHeight() int64
+	// MoveBlocksToColdStore moves the blocks below height to the cold database.
+	MoveBlocksToColdStore(height int64) (int64, error)
+}
+
 // -----------------------------------------------------------------------------
 // evidence pool
 
@@ -14,6 +14,7 @@ import (
 	cmtstate "github.com/cometbft/cometbft/api/cometbft/state/v2"
 	cmtproto "github.com/cometbft/cometbft/api/cometbft/types/v2"
 	abci "github.com/cometbft/cometbft/v2/abci/types"
+	"github.com/cometbft/cometbft/v2/internal/compression"
 	cmtos "github.com/cometbft/cometbft/v2/internal/os"
 	"github.com/cometbft/cometbft/v2/libs/log"
 	cmtmath "github.com/cometbft/cometbft/v2/libs/math"
@@ -171,12 +172,22 @@ type Store interface {
 	LoadLastFinalizeBlockResponse(height int64) (*abci.FinalizeBlockResponse, error)
 	// LoadConsensusParams loads the consensus params for a given height
 	LoadConsensusParams(height int64) (types.ConsensusParams, error)
+	// LoadValidatorsChangeHeight loads the last height at which the validator set of a given height changed
+	LoadValidatorsChangeHeight(height int64) (int64, error)
+	// LoadConsensusParamsChangeHeight loads the last height at which the consensus params of a given height changed
+	LoadConsensusParamsChangeHeight(height int64) (int64, error)
 	// Save overwrites the previous state with the updated one
 	Save(state State) error
 	// SaveFinalizeBlockResponse saves ABCIResponses for a given height
 	SaveFinalizeBlockResponse(height int64, res *abci.FinalizeBlockResponse) error
 	// Bootstrap is used for bootstrapping state when not starting from a initial height.
 	Bootstrap(state State) error
+	// SaveValidatorSet saves the validator set at a given height, e.g. when backfilling the
+	// heights below a state sync snapshot
+	SaveValidatorSet(height int64, valSet *types.ValidatorSet) error
+	// Rewind overwrites the current state with the given previous state, and deletes the
+	// ABCI responses, validator sets and consensus params of the heights after it
+	Rewind(state State) error
 	// PruneStates takes the height from which to start pruning and which height stop at
 	PruneStates(fromHeight, toHeight, evidenceThresholdHeight int64, previouslyPrunedStates uint64) (uint64, error)
 	// PruneABCIResponses will prune all ABCI responses below the given height.
@@ -228,6 +239,11 @@ type StoreOptions struct {
 	Logger log.Logger
 
 	DBKeyLayout string
+
+	// Compression is the algorithm, "none" or "zstd", the ABCI responses are
+	// compressed with when saved. ABCI responses saved with another algorithm
+	// remain readable, see RecompressABCIResponses.
+	Compression string
 }
 
 var _ Store = (*dbStore)(nil)
@@ -261,6 +277,8 @@ func setDBKeyLayout(store *dbStore, dbKeyLayoutVersion string) string {
 	case "v2":
 		store.DBKeyLayout = &v2Layout{}
 		dbKeyLayoutVersion = "v2"
+	case KeyLayoutMigratingToV2:
+		panic("the migration of the key layout to v2 is not complete, run it again")
 	default:
 		panic("Unknown version. Expected v1 or v2, given " + dbKeyLayoutVersion)
 	}
@@ -287,6 +305,9 @@ func NewStore(db dbm.DB, options StoreOptions) Store {
 	}
 
 	dbKeyLayoutVersion := setDBKeyLayout(&store, options.DBKeyLayout)
+	if err := compression.Validate(options.Compression); err != nil {
+		panic(err)
+	}
 
 	if options.Logger != nil {
 		options.Logger.Info(
@@ -388,6 +409,20 @@ func (store dbStore) save(state State, key []byte) error {
 			panic(err)
 		}
 	}(batch)
+	marshallTime, err := store.saveToBatch(state, key, batch)
+	if err != nil {
+		return err
+	}
+	if err := batch.WriteSync(); err != nil {
+		panic(err)
+	}
+	store.StoreOptions.Metrics.StoreAccessDurationSeconds.With("method", "save").Observe(time.Since(start).Seconds() - marshallTime)
+	return nil
+}
+
+// saveToBatch adds the state, the next validators and the next consensus params to
+// batch. It returns the time taken to marshall the state, in seconds.
+func (store dbStore) saveToBatch(state State, key []byte, batch dbm.Batch) (float64, error) {
 	nextHeight := state.LastBlockHeight + 1
 	// If first block, save validators for the block.
 	if nextHeight == 1 {
@@ -395,17 +430,17 @@ func (store dbStore) save(state State, key []byte) error {
 		// This extra logic due to validator set changes being delayed 1 block.
 		// It may get overwritten due to InitChain validator updates.
 		if err := store.saveValidatorsInfo(nextHeight, nextHeight, state.Validators, batch); err != nil {
-			return err
+			return 0, err
 		}
 	}
 	// Save next validators.
 	if err := store.saveValidatorsInfo(nextHeight+1, state.LastHeightValidatorsChanged, state.NextValidators, batch); err != nil {
-		return err
+		return 0, err
 	}
 	// Save next consensus params.
 	if err := store.saveConsensusParamsInfo(nextHeight,
 		state.LastHeightConsensusParamsChanged, state.ConsensusParams, batch); err != nil {
-		return err
+		return 0, err
 	}
 
 	// Counting the amount of time taken to marshall the state.
@@ -415,13 +450,47 @@ func (store dbStore) save(state State, key []byte) error {
 	stateMarshallDiff := time.Since(stateMarshallTime).Seconds()
 
 	if err := batch.Set(key, stateBytes); err != nil {
+		return 0, err
+	}
+	return stateMarshallDiff, nil
+}
+
+// Rewind overwrites the current state with state, which must be a previous one, and
+// deletes the ABCI responses, validator sets and consensus params of the heights after
+// it, in a single batch. The validator sets and consensus params which state refers to
+// are saved as well, as in Save.
+func (store dbStore) Rewind(state State) error {
+	defer addTimeSample(store.StoreOptions.Metrics.StoreAccessDurationSeconds.With("method", "rewind"), time.Now())()
+
+	current, err := store.Load()
+	if err != nil {
 		return err
 	}
-	if err := batch.WriteSync(); err != nil {
-		panic(err)
+	if state.LastBlockHeight > current.LastBlockHeight {
+		return fmt.Errorf("cannot rewind the state at height %d to the later height %d",
+			current.LastBlockHeight, state.LastBlockHeight)
 	}
-	store.StoreOptions.Metrics.StoreAccessDurationSeconds.With("method", "save").Observe(time.Since(start).Seconds() - stateMarshallDiff)
-	return nil
+
+	batch := store.db.NewBatch()
+	defer batch.Close()
+
+	// Save persists the validators up to the height after the next one, and the
+	// consensus params up to the next height.
+	for h := state.LastBlockHeight + 1; h <= current.LastBlockHeight; h++ {
+		if err := batch.Delete(store.DBKeyLayout.CalcABCIResponsesKey(h)); err != nil {
+			return err
+		}
+		if err := batch.Delete(store.DBKeyLayout.CalcConsensusParamsKey(h + 1)); err != nil {
+			return err
+		}
+		if err := batch.Delete(store.DBKeyLayout.CalcValidatorsKey(h + 2)); err != nil {
+			return err
+		}
+	}
+	if _, err := store.saveToBatch(state, stateKey, batch); err != nil {
+		return err
+	}
+	return batch.WriteSync()
 }
 
 // BootstrapState saves a new state, used e.g. by state sync when starting from non-zero height.
@@ -703,6 +772,10 @@ func (store dbStore) LoadFinalizeBlockResponse(height int64) (*abci.FinalizeBloc
 	if len(buf) == 0 {
 		return nil, ErrNoABCIResponsesForHeight{height}
 	}
+	buf, err = compression.Decode(buf)
+	if err != nil {
+		return nil, ErrABCIResponseCorruptedOrSpecChangeForHeight{Height: height, Err: err}
+	}
 
 	resp := new(abci.FinalizeBlockResponse)
 	err = resp.Unmarshal(buf)
@@ -776,7 +849,10 @@ func (store dbStore) LoadLastFinalizeBlockResponse(height int64) (*abci.Finalize
 		return nil, fmt.Errorf("expected last ABCI responses at height %d, but none are found", height)
 	}
 	resp := new(abci.FinalizeBlockResponse)
-	err = resp.Unmarshal(buf)
+	buf, err = compression.Decode(buf)
+	if err == nil {
+		err = resp.Unmarshal(buf)
+	}
 	if err != nil {
 		cmtos.Exit(fmt.Sprintf(`LoadLastFinalizeBlockResponse: Data has been corrupted or its spec has changed: %v\n`, err))
 	}
@@ -803,6 +879,7 @@ func (store dbStore) SaveFinalizeBlockResponse(height int64, resp *abci.Finalize
 	if err != nil {
 		return err
 	}
+	bz = compression.Encode(store.Compression, bz)
 
 	// Save the ABCI response.
 	//
@@ -962,6 +1039,17 @@ func (store dbStore) LoadValidators(height int64) (*types.ValidatorSet, error) {
 	return vip, nil
 }
 
+// LoadValidatorsChangeHeight returns the last height at which the validator
+// set for a given height changed, as recorded when it was saved.
+// Returns ErrNoValSetForHeight if the validator set can't be found for this height.
+func (store dbStore) LoadValidatorsChangeHeight(height int64) (int64, error) {
+	valInfo, _, err := loadValidatorsInfo(store.db, store.DBKeyLayout.CalcValidatorsKey(height))
+	if err != nil {
+		return 0, ErrNoValSetForHeight{height}
+	}
+	return valInfo.LastHeightChanged, nil
+}
+
 func lastStoredHeightFor(height, lastHeightChanged int64) int64 {
 	checkpointHeight := height - height%valSetCheckpointInterval
 	return cmtmath.MaxInt64(checkpointHeight, lastHeightChanged)
@@ -1061,6 +1149,17 @@ func (store dbStore) LoadConsensusParams(height int64) (types.ConsensusParams, e
 	return types.ConsensusParamsFromProto(paramsInfo.ConsensusParams), nil
 }
 
+// LoadConsensusParamsChangeHeight returns the last height at which the
+// consensus params for a given height changed, as recorded when they were
+// saved.
+func (store dbStore) LoadConsensusParamsChangeHeight(height int64) (int64, error) {
+	paramsInfo, err := store.loadConsensusParamsInfo(height)
+	if err != nil {
+		return 0, fmt.Errorf("could not find consensus params for height #%d: %w", height, err)
+	}
+	return paramsInfo.LastHeightChanged, nil
+}
+
 func (store dbStore) loadConsensusParamsInfo(height int64) (*cmtstate.ConsensusParamsInfo, error) {
 	start := time.Now()
 	buf, err := store.db.Get(store.DBKeyLayout.CalcConsensusParamsKey(height))
@@ -1110,6 +1209,18 @@ func (store dbStore) saveConsensusParamsInfo(nextHeight, changeHeight int64, par
 	return nil
 }
 
+// SaveValidatorSet persists the full validator set at the given height, independently of the
+// heights at which it changed.
+func (store dbStore) SaveValidatorSet(height int64, valSet *types.ValidatorSet) error {
+	batch := store.db.NewBatch()
+	defer batch.Close()
+
+	if err := store.saveValidatorsInfo(height, height, valSet, batch); err != nil {
+		return err
+	}
+	return batch.WriteSync()
+}
+
 func (store dbStore) SetOfflineStateSyncHeight(height int64) error {
 	err := store.db.SetSync(offlineStateSyncHeight, int64ToBytes(height))
 	if err != nil {
@@ -1,6 +1,7 @@
 package state_test
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"testing"
@@ -647,3 +648,106 @@ func TestIntConversion(t *testing.T) {
 	b := sm.Int64ToBytes(x)
 	require.Equal(t, x, sm.Int64FromBytes(b))
 }
+
+func TestMigrateKeyLayoutToV2(t *testing.T) {
+	stateDB := dbm.NewMemDB()
+	stateStore := sm.NewStore(stateDB, sm.StoreOptions{DBKeyLayout: "v1"})
+	val, _ := types.RandValidator(true, 10)
+	vals := types.NewValidatorSet([]*types.Validator{val})
+	state := sm.State{
+		ChainID:                          "migrate-chain",
+		InitialHeight:                    1,
+		LastBlockHeight:                  3,
+		Validators:                       vals,
+		NextValidators:                   vals,
+		LastValidators:                   vals,
+		LastHeightValidatorsChanged:      1,
+		ConsensusParams:                  *types.DefaultConsensusParams(),
+		LastHeightConsensusParamsChanged: 4,
+	}
+	require.NoError(t, stateStore.Save(state))
+	for h := int64(1); h <= 3; h++ {
+		require.NoError(t, stateStore.SaveValidatorSet(h, vals))
+		require.NoError(t, stateStore.SaveFinalizeBlockResponse(h, &abci.FinalizeBlockResponse{AppHash: []byte{byte(h)}}))
+	}
+
+	n, err := sm.MigrateKeyLayoutToV2(stateDB, 2)
+	require.NoError(t, err)
+	assert.Positive(t, n)
+
+	// The layout version recorded by the migration takes precedence.
+	stateStore = sm.NewStore(stateDB, sm.StoreOptions{DBKeyLayout: "v1"})
+	for h := int64(1); h <= 3; h++ {
+		loadedVals, err := stateStore.LoadValidators(h)
+		require.NoError(t, err)
+		assert.Equal(t, vals.Hash(), loadedVals.Hash())
+		resp, err := stateStore.LoadFinalizeBlockResponse(h)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{byte(h)}, resp.AppHash)
+	}
+	params, err := stateStore.LoadConsensusParams(4)
+	require.NoError(t, err)
+	assert.Equal(t, state.ConsensusParams.Hash(), params.Hash())
+
+	n, err = sm.MigrateKeyLayoutToV2(stateDB, 2)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	require.NoError(t, stateDB.Set([]byte("version"), []byte(sm.KeyLayoutMigratingToV2)))
+	assert.Panics(t, func() { sm.NewStore(stateDB, sm.StoreOptions{}) })
+}
+
+func TestRecompressABCIResponses(t *testing.T) {
+	stateDB := dbm.NewMemDB()
+	stateStore := sm.NewStore(stateDB, sm.StoreOptions{Compression: "zstd"})
+	val, _ := types.RandValidator(true, 10)
+	vals := types.NewValidatorSet([]*types.Validator{val})
+	state := sm.State{
+		ChainID:                          "compression-chain",
+		InitialHeight:                    1,
+		LastBlockHeight:                  4,
+		Validators:                       vals,
+		NextValidators:                   vals,
+		LastValidators:                   vals,
+		LastHeightValidatorsChanged:      1,
+		ConsensusParams:                  *types.DefaultConsensusParams(),
+		LastHeightConsensusParamsChanged: 1,
+	}
+	require.NoError(t, stateStore.Save(state))
+	resp := func(h int64) *abci.FinalizeBlockResponse {
+		return &abci.FinalizeBlockResponse{
+			TxResults: []*abci.ExecTxResult{{Code: 0, Data: bytes.Repeat([]byte{byte(h)}, 1000)}},
+			AppHash:   []byte{byte(h)},
+		}
+	}
+	// Heights 1 and 2 are compressed, 3 and 4 are not.
+	for h := int64(1); h <= 4; h++ {
+		if h == 3 {
+			stateStore = sm.NewStore(stateDB, sm.StoreOptions{Compression: "none"})
+		}
+		require.NoError(t, stateStore.SaveFinalizeBlockResponse(h, resp(h)))
+	}
+	for h := int64(1); h <= 4; h++ {
+		loaded, err := stateStore.LoadFinalizeBlockResponse(h)
+		require.NoError(t, err)
+		assert.Equal(t, resp(h).TxResults[0].Data, loaded.TxResults[0].Data)
+	}
+	loaded, err := sm.NewStore(stateDB, sm.StoreOptions{Compression: "zstd"}).LoadLastFinalizeBlockResponse(2)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2}, loaded.AppHash)
+
+	stateStore = sm.NewStore(stateDB, sm.StoreOptions{Compression: "zstd"})
+	n, err := sm.RecompressABCIResponses(stateStore, 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+	n, err = sm.RecompressABCIResponses(stateStore, 1)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	for h := int64(1); h <= 4; h++ {
+		loaded, err := stateStore.LoadFinalizeBlockResponse(h)
+		require.NoError(t, err)
+		assert.Equal(t, resp(h).TxResults[0].Data, loaded.TxResults[0].Data)
+	}
+
+	assert.Panics(t, func() { sm.NewStore(stateDB, sm.StoreOptions{Compression: "gzip"}) })
+}
@@ -7,6 +7,7 @@ import (
 	abci "github.com/cometbft/cometbft/v2/abci/types"
 	"github.com/cometbft/cometbft/v2/libs/log"
 	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	"github.com/cometbft/cometbft/v2/state/indexer"
 )
 
 // XXX/TODO: These types should be moved to the indexer package.
@@ -44,6 +45,11 @@ type TxIndexer interface {
 	// provide a valid context to cancel long-running searches.
 	Search(ctx context.Context, q *query.Query, pagSettings Pagination) ([]*abci.TxResult, int, error)
 
+	// Count returns the number of transactions matching the provided query
+	// and, if bucketSize is positive, their number in each range of bucketSize
+	// heights. Unlike Search, it does not load the matching transactions.
+	Count(ctx context.Context, q *query.Query, bucketSize int64) (int, []indexer.HeightBucket, error)
+
 	// SetLogger configures a logger for this TxIndexer. This logger may be used
 	// to report database I/O operations, indexing progress, or errors encountered
 	// during storage and retrieval.
@@ -71,6 +77,29 @@ type TxIndexer interface {
 	Close() error
 }
 
+// ReindexCheckpoint records the progress of a re-index of the events of the
+// heights from StartHeight to EndHeight by the reindex-event command: the
+// events were reindexed up to Height.
+type ReindexCheckpoint struct {
+	StartHeight int64
+	EndHeight   int64
+	Height      int64
+}
+
+// SinkHeightStore records the heights up to which the additional sinks of the
+// IndexerService indexed the blocks, so that they resume from there after a
+// restart. The IndexerService uses the transaction indexer of its primary
+// sink as the store if it implements SinkHeightStore.
+type SinkHeightStore interface {
+	// GetSinkHeight returns the height recorded for the sink with the given
+	// name, or 0 if none was.
+	GetSinkHeight(name string) (int64, error)
+
+	// SetSinkHeight records the height up to which the sink with the given
+	// name indexed the blocks.
+	SetSinkHeight(name string, height int64) error
+}
+
 // Batch groups together multiple Index operations to be performed at the same time.
 // NOTE: Batch is NOT thread-safe and must not be modified after starting its execution.
 type Batch struct {
@@ -85,6 +114,30 @@ type Pagination struct {
 	IsPaginated bool
 	Page        int
 	PerPage     int
+
+	// If Limit is > 0, at most Limit results are returned, starting after
+	// Cursor in the order of the search, or from the first result if Cursor is
+	// nil, instead of the page Page. The number of results returned by Search
+	// is then the number of results after Cursor.
+	Cursor *Cursor
+	Limit  int
+}
+
+// A Cursor is the position of a transaction in the results of a search, from
+// which the search is resumed.
+type Cursor struct {
+	Height int64
+	Index  uint32
+}
+
+// After reports whether the transaction at the given height and index comes
+// after the cursor, in ascending order if desc is false, or in descending
+// order otherwise.
+func (c Cursor) After(height int64, index uint32, desc bool) bool {
+	if desc {
+		return height < c.Height || (height == c.Height && index < c.Index)
+	}
+	return height > c.Height || (height == c.Height && index > c.Index)
 }
 
 // NewBatch creates a new Batch.
@@ -2,7 +2,12 @@ package txindex
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 
+	abci "github.com/cometbft/cometbft/v2/abci/types"
 	"github.com/cometbft/cometbft/v2/libs/service"
 	"github.com/cometbft/cometbft/v2/state/indexer"
 	"github.com/cometbft/cometbft/v2/types"
@@ -12,17 +17,126 @@ import (
 
 const (
 	subscriber = "IndexerService"
+
+	// PrimarySinkName is the name of the sink made of the transaction and
+	// block indexers the IndexerService is created with.
+	PrimarySinkName = "primary"
+
+	// sinkBufferSize is the number of blocks buffered for an additional sink
+	// before it starts lagging behind.
+	sinkBufferSize = 100
 )
 
+var errStopped = errors.New("indexer service stopped")
+
+// Sink is a pair of transaction and block indexers, backed by the same
+// storage, to which the IndexerService indexes the events.
+type Sink struct {
+	Name         string
+	TxIndexer    TxIndexer
+	BlockIndexer indexer.BlockIndexer
+}
+
+// BlockEventsLoader loads the events and the transaction results of the
+// committed block at the given height. It is used to catch up a sink lagging
+// behind.
+type BlockEventsLoader func(height int64) (types.EventDataNewBlockEvents, *Batch, error)
+
+// BlockStore is the store from which LoadBlockEvents loads the committed
+// blocks.
+type BlockStore interface {
+	LoadBlock(height int64) (*types.Block, *types.BlockMeta)
+}
+
+// FinalizeBlockResponseStore is the store from which LoadBlockEvents loads the
+// results of the committed blocks.
+type FinalizeBlockResponseStore interface {
+	LoadFinalizeBlockResponse(height int64) (*abci.FinalizeBlockResponse, error)
+}
+
+// LoadBlockEvents loads the events of the committed block at height, and its
+// transaction results, from blockStore and resultStore. It backs the
+// BlockEventsLoader of the node and the reindex-event command.
+func LoadBlockEvents(
+	blockStore BlockStore,
+	resultStore FinalizeBlockResponseStore,
+	height int64,
+) (types.EventDataNewBlockEvents, *Batch, error) {
+	block, _ := blockStore.LoadBlock(height)
+	if block == nil {
+		return types.EventDataNewBlockEvents{}, nil, fmt.Errorf("not able to load block at height %d from the blockstore", height)
+	}
+	resp, err := resultStore.LoadFinalizeBlockResponse(height)
+	if err != nil {
+		return types.EventDataNewBlockEvents{}, nil,
+			fmt.Errorf("not able to load ABCI Response at height %d from the statestore: %w", height, err)
+	}
+
+	batch := NewBatch(int64(len(resp.TxResults)))
+	for i, txResult := range resp.TxResults {
+		if err := batch.Add(&abci.TxResult{
+			Height: height,
+			Index:  uint32(i),
+			Tx:     block.Txs[i],
+			Result: *txResult,
+		}); err != nil {
+			return types.EventDataNewBlockEvents{}, nil, fmt.Errorf("adding tx to batch: %w", err)
+		}
+	}
+	return types.EventDataNewBlockEvents{
+		Height: height,
+		Events: resp.Events,
+		NumTxs: int64(len(resp.TxResults)),
+	}, batch, nil
+}
+
+// IndexerServiceOption sets an optional parameter on the IndexerService.
+type IndexerServiceOption func(*IndexerService)
+
+// WithSinks adds sinks to which the events are indexed besides the transaction
+// and block indexers of the service. Each of them is indexed by its own
+// routine, so that a slow or failing sink does not hold the others back.
+func WithSinks(sinks ...Sink) IndexerServiceOption {
+	return func(is *IndexerService) {
+		for _, s := range sinks {
+			is.sinks = append(is.sinks, newSinkIndexer(s))
+		}
+	}
+}
+
+// WithBlockEventsLoader sets the loader with which a sink that failed to index
+// some heights, or fell behind, catches up. lastHeight returns the height of
+// the last committed block, up to which the sinks catch up from their recorded
+// heights when the service starts. Without a loader, those heights are
+// skipped.
+func WithBlockEventsLoader(loader BlockEventsLoader, lastHeight func() int64) IndexerServiceOption {
+	return func(is *IndexerService) {
+		is.loader = loader
+		is.lastHeight = lastHeight
+	}
+}
+
 // IndexerService connects event bus, transaction and block indexers together in
 // order to index transactions and blocks coming from the event bus.
 type IndexerService struct {
 	service.BaseService
 
-	txIdxr           TxIndexer
-	blockIdxr        indexer.BlockIndexer
 	eventBus         *types.EventBus
 	terminateOnError bool
+
+	// sinks[0] is the primary sink, indexed synchronously.
+	sinks      []*sinkIndexer
+	loader     BlockEventsLoader
+	lastHeight func() int64
+
+	// heightStore records the heights of the additional sinks, nil if the
+	// transaction indexer of the primary sink cannot.
+	heightStore SinkHeightStore
+
+	// stopSinks makes the routines of the additional sinks return, before
+	// their indexers are closed.
+	stopSinks chan struct{}
+	wg        sync.WaitGroup
 }
 
 // NewIndexerService returns a new service instance.
@@ -31,17 +145,46 @@ func NewIndexerService(
 	blockIdxr indexer.BlockIndexer,
 	eventBus *types.EventBus,
 	terminateOnError bool,
+	options ...IndexerServiceOption,
 ) *IndexerService {
 	is := &IndexerService{
-		txIdxr:           txIdxr,
-		blockIdxr:        blockIdxr,
 		eventBus:         eventBus,
 		terminateOnError: terminateOnError,
+		sinks: []*sinkIndexer{newSinkIndexer(Sink{
+			Name:         PrimarySinkName,
+			TxIndexer:    txIdxr,
+			BlockIndexer: blockIdxr,
+		})},
 	}
 	is.BaseService = *service.NewBaseService(nil, "IndexerService", is)
+	if store, ok := txIdxr.(SinkHeightStore); ok {
+		is.heightStore = store
+	}
+	for _, option := range options {
+		option(is)
+	}
 	return is
 }
 
+// Sinks returns the sinks of the service, starting with the primary one.
+func (is *IndexerService) Sinks() []Sink {
+	sinks := make([]Sink, len(is.sinks))
+	for i, s := range is.sinks {
+		sinks[i] = s.Sink
+	}
+	return sinks
+}
+
+// SinkHeights returns, for each sink, the height up to which the blocks are
+// indexed. It is 0 for a sink which has not indexed a block yet.
+func (is *IndexerService) SinkHeights() map[string]int64 {
+	heights := make(map[string]int64, len(is.sinks))
+	for _, s := range is.sinks {
+		heights[s.Name] = s.height.Load()
+	}
+	return heights
+}
+
 // OnStart implements service.Service by subscribing for all transactions
 // and indexing them by events.
 func (is *IndexerService) OnStart() error {
@@ -61,6 +204,22 @@ func (is *IndexerService) OnStart() error {
 		return err
 	}
 
+	if err := is.loadSinkHeights(); err != nil {
+		return err
+	}
+
+	is.stopSinks = make(chan struct{})
+	for _, s := range is.sinks[1:] {
+		is.wg.Add(1)
+		go func(s *sinkIndexer) {
+			err := is.runSink(s)
+			is.wg.Done()
+			if err != nil {
+				is.stop()
+			}
+		}(s)
+	}
+
 	go func() {
 		for {
 			select {
@@ -86,36 +245,23 @@ func (is *IndexerService) OnStart() error {
 						)
 
 						if is.terminateOnError {
-							if err := is.Stop(); err != nil { //nolint:revive // suppress max-control-nesting linter
-								is.Logger.Error("failed to stop", "err", err)
-							}
+							is.stop()
 							return
 						}
 					}
 				}
 
-				if err := is.blockIdxr.Index(eventNewBlockEvents); err != nil {
-					is.Logger.Error("failed to index block", "height", height, "err", err)
-					if is.terminateOnError {
-						if err := is.Stop(); err != nil {
-							is.Logger.Error("failed to stop", "err", err)
-						}
-						return
+				for _, s := range is.sinks[1:] {
+					select {
+					case s.blocks <- blockEvents{events: eventNewBlockEvents, batch: batch}:
+					default:
+						is.Logger.Error("sink is lagging behind, it will catch up later", "sink", s.Name, "height", height)
 					}
-				} else {
-					is.Logger.Info("indexed block events", "height", height)
 				}
 
-				if err = is.txIdxr.AddBatch(batch); err != nil {
-					is.Logger.Error("failed to index block txs", "height", height, "err", err)
-					if is.terminateOnError {
-						if err := is.Stop(); err != nil {
-							is.Logger.Error("failed to stop", "err", err)
-						}
-						return
-					}
-				} else {
-					is.Logger.Debug("indexed transactions", "height", height, "num_txs", numTxs)
+				if err := is.indexSink(is.sinks[0], eventNewBlockEvents, batch); err != nil && is.terminateOnError {
+					is.stop()
+					return
 				}
 			}
 		}
@@ -129,5 +275,166 @@ func (is *IndexerService) OnStop() {
 		_ = is.eventBus.UnsubscribeAll(context.Background(), subscriber)
 	}
 
-	is.txIdxr.Close()
+	close(is.stopSinks)
+	is.wg.Wait()
+	for _, s := range is.sinks {
+		s.TxIndexer.Close()
+	}
+}
+
+// loadSinkHeights sets the heights of the additional sinks to the ones
+// recorded before the service was last stopped.
+func (is *IndexerService) loadSinkHeights() error {
+	if is.heightStore == nil {
+		if len(is.sinks) > 1 {
+			is.Logger.Info("the heights of the sinks are not recorded by the primary sink, they will start from the next block",
+				"sink", PrimarySinkName)
+		}
+		return nil
+	}
+	for _, s := range is.sinks[1:] {
+		height, err := is.heightStore.GetSinkHeight(s.Name)
+		if err != nil {
+			return fmt.Errorf("loading the height of sink %q: %w", s.Name, err)
+		}
+		s.height.Store(height)
+	}
+	return nil
+}
+
+// runSink indexes the blocks sent to the additional sink s until the service
+// stops, after catching it up with the blocks committed since its recorded
+// height. It returns an error if the service has to terminate.
+func (is *IndexerService) runSink(s *sinkIndexer) error {
+	if last := s.height.Load(); last > 0 && is.loader != nil && is.lastHeight != nil {
+		if height := is.lastHeight(); height > last {
+			if err := is.catchUpSink(s, last+1, height); errors.Is(err, errStopped) {
+				return nil
+			} else if err != nil {
+				is.Logger.Error("failed to catch up sink", "sink", s.Name, "height", s.height.Load(), "err", err)
+				if is.terminateOnError {
+					return err
+				}
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-is.stopSinks:
+			return nil
+		case b := <-s.blocks:
+			if err := is.indexSink(s, b.events, b.batch); err != nil && is.terminateOnError {
+				return err
+			}
+		}
+	}
+}
+
+// indexSink indexes the block events and the batch of transactions to the sink
+// s, after catching it up with the heights it has not indexed yet.
+func (is *IndexerService) indexSink(s *sinkIndexer, events types.EventDataNewBlockEvents, batch *Batch) error {
+	height := events.Height
+	last := s.height.Load()
+	if last > 0 && height <= last {
+		return nil
+	}
+
+	if last > 0 && height > last+1 {
+		if is.loader == nil {
+			is.Logger.Error("sink skipped heights", "sink", s.Name, "from", last+1, "to", height-1)
+		} else if err := is.catchUpSink(s, last+1, height-1); errors.Is(err, errStopped) {
+			return nil
+		} else if err != nil {
+			is.Logger.Error("failed to catch up sink", "sink", s.Name, "height", s.height.Load(), "err", err)
+			return err
+		}
+	}
+
+	if err := s.index(events, batch); err != nil {
+		is.Logger.Error("failed to index block", "sink", s.Name, "height", height, "err", err)
+		return err
+	}
+	is.setSinkHeight(s, height)
+	is.Logger.Info("indexed block events", "sink", s.Name, "height", height, "num_txs", batch.Size())
+	return nil
+}
+
+// catchUpSink indexes the heights from fromHeight to toHeight to the sink s,
+// loading their events with the loader of the service.
+func (is *IndexerService) catchUpSink(s *sinkIndexer, fromHeight, toHeight int64) error {
+	is.Logger.Info("catching up sink", "sink", s.Name, "from", fromHeight, "to", toHeight)
+	for height := fromHeight; height <= toHeight; height++ {
+		select {
+		case <-is.stopSinks:
+			return errStopped
+		default:
+		}
+
+		events, batch, err := is.loader(height)
+		if err != nil {
+			return fmt.Errorf("loading the events at height %d: %w", height, err)
+		}
+		if err := s.index(events, batch); err != nil {
+			return err
+		}
+		is.setSinkHeight(s, height)
+	}
+	return nil
+}
+
+// setSinkHeight sets the height up to which the blocks are indexed to the sink
+// s, recording it if s is an additional sink.
+func (is *IndexerService) setSinkHeight(s *sinkIndexer, height int64) {
+	s.height.Store(height)
+	if is.heightStore == nil || s == is.sinks[0] {
+		return
+	}
+	if err := is.heightStore.SetSinkHeight(s.Name, height); err != nil {
+		is.Logger.Error("failed to record the height of sink", "sink", s.Name, "height", height, "err", err)
+	}
+}
+
+// stop stops the service after a failure, when terminateOnError is set.
+func (is *IndexerService) stop() {
+	if err := is.Stop(); err != nil {
+		is.Logger.Error("failed to stop", "err", err)
+	}
+}
+
+// blockEvents are the events of a block and its batch of transactions.
+type blockEvents struct {
+	events types.EventDataNewBlockEvents
+	batch  *Batch
+}
+
+// sinkIndexer is a sink along with the blocks waiting to be indexed to it and
+// the height up to which the blocks are indexed.
+type sinkIndexer struct {
+	Sink
+
+	blocks chan blockEvents
+	height atomic.Int64
+}
+
+func newSinkIndexer(s Sink) *sinkIndexer {
+	return &sinkIndexer{
+		Sink:   s,
+		blocks: make(chan blockEvents, sinkBufferSize),
+	}
+}
+
+// index indexes the block events and the batch of transactions to the sink.
+// The transactions are indexed even if the block events failed to be.
+func (s *sinkIndexer) index(events types.EventDataNewBlockEvents, batch *Batch) error {
+	var errs []error
+	if err := s.BlockIndexer.Index(events); err != nil {
+		errs = append(errs, fmt.Errorf("indexing block at height %d: %w", events.Height, err))
+	}
+	if batch != nil {
+		if err := s.TxIndexer.AddBatch(batch); err != nil {
+			errs = append(errs, fmt.Errorf("indexing txs at height %d: %w", events.Height, err))
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -1,7 +1,9 @@
 package txindex_test
 
 import (
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -48,6 +50,192 @@ func TestIndexerServiceIndexesBlocks(t *testing.T) {
 	require.Equal(t, txResult2, res)
 }
 
+// failingTxIndex is a tx indexer failing to index the transactions while fail
+// is set.
+type failingTxIndex struct {
+	*kv.TxIndex
+	fail atomic.Bool
+}
+
+func (txi *failingTxIndex) AddBatch(b *txindex.Batch) error {
+	if txi.fail.Load() {
+		return errors.New("sink unavailable")
+	}
+	return txi.TxIndex.AddBatch(b)
+}
+
+func TestIndexerServiceCatchesUpLaggingSink(t *testing.T) {
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	store := db.NewMemDB()
+	txIndexer := kv.NewTxIndex(store)
+	blockIndexer := blockidxkv.New(db.NewPrefixDB(store, []byte("block_events")))
+
+	sinkStore := db.NewMemDB()
+	sinkTxIndexer := &failingTxIndex{TxIndex: kv.NewTxIndex(sinkStore)}
+	sinkBlockIndexer := blockidxkv.New(db.NewPrefixDB(sinkStore, []byte("block_events")))
+
+	service := txindex.NewIndexerService(txIndexer, blockIndexer, eventBus, false,
+		txindex.WithSinks(txindex.Sink{Name: "secondary", TxIndexer: sinkTxIndexer, BlockIndexer: sinkBlockIndexer}),
+		txindex.WithBlockEventsLoader(loadEventsAndResults, nil))
+	service.SetLogger(log.TestingLogger())
+	require.NoError(t, service.Start())
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	publish := func(height int64) {
+		events, txResult1, txResult2 := getEventsAndResults(height)
+		require.NoError(t, eventBus.PublishEventNewBlockEvents(events))
+		require.NoError(t, eventBus.PublishEventTx(types.EventDataTx{TxResult: *txResult1}))
+		require.NoError(t, eventBus.PublishEventTx(types.EventDataTx{TxResult: *txResult2}))
+	}
+	waitHeights := func(primary, secondary int64) {
+		require.Eventually(t, func() bool {
+			heights := service.SinkHeights()
+			return heights[txindex.PrimarySinkName] == primary && heights["secondary"] == secondary
+		}, time.Second, 10*time.Millisecond)
+	}
+
+	publish(1)
+	waitHeights(1, 1)
+
+	// the secondary sink fails, the primary one keeps indexing
+	sinkTxIndexer.fail.Store(true)
+	publish(2)
+	publish(3)
+	waitHeights(3, 1)
+
+	// the secondary sink catches up once available
+	sinkTxIndexer.fail.Store(false)
+	publish(4)
+	waitHeights(4, 4)
+
+	for height := int64(1); height <= 4; height++ {
+		res, err := sinkTxIndexer.Get(types.Tx(fmt.Sprintf("bar%d", height)).Hash())
+		require.NoError(t, err)
+		require.NotNil(t, res, height)
+
+		ok, err := sinkBlockIndexer.Has(height)
+		require.NoError(t, err)
+		require.True(t, ok, height)
+	}
+}
+
+func TestIndexerServiceResumesSinkAfterRestart(t *testing.T) {
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	store := db.NewMemDB()
+	sinkStore := db.NewMemDB()
+	lastHeight := int64(0)
+
+	startService := func() *txindex.IndexerService {
+		service := txindex.NewIndexerService(
+			kv.NewTxIndex(store),
+			blockidxkv.New(db.NewPrefixDB(store, []byte("block_events"))),
+			eventBus, false,
+			txindex.WithSinks(txindex.Sink{
+				Name:         "secondary",
+				TxIndexer:    kv.NewTxIndex(sinkStore),
+				BlockIndexer: blockidxkv.New(db.NewPrefixDB(sinkStore, []byte("block_events"))),
+			}),
+			txindex.WithBlockEventsLoader(loadEventsAndResults, func() int64 { return lastHeight }))
+		service.SetLogger(log.TestingLogger())
+		require.NoError(t, service.Start())
+		return service
+	}
+
+	service := startService()
+	for height := int64(1); height <= 2; height++ {
+		events, txResult1, txResult2 := getEventsAndResults(height)
+		require.NoError(t, eventBus.PublishEventNewBlockEvents(events))
+		require.NoError(t, eventBus.PublishEventTx(types.EventDataTx{TxResult: *txResult1}))
+		require.NoError(t, eventBus.PublishEventTx(types.EventDataTx{TxResult: *txResult2}))
+	}
+	require.Eventually(t, func() bool {
+		return service.SinkHeights()["secondary"] == 2
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, service.Stop())
+
+	// the blocks up to height 5 are committed while the service is stopped
+	lastHeight = 5
+	service = startService()
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	require.Eventually(t, func() bool {
+		return service.SinkHeights()["secondary"] == 5
+	}, time.Second, 10*time.Millisecond)
+
+	sinkTxIndexer := kv.NewTxIndex(sinkStore)
+	for height := int64(1); height <= 5; height++ {
+		res, err := sinkTxIndexer.Get(types.Tx(fmt.Sprintf("foo%d", height)).Hash())
+		require.NoError(t, err)
+		require.NotNil(t, res, height)
+	}
+}
+
+// failingBlockIndexer is a block indexer failing to index the blocks.
+type failingBlockIndexer struct {
+	*blockidxkv.BlockerIndexer
+}
+
+func (failingBlockIndexer) Index(types.EventDataNewBlockEvents) error {
+	return errors.New("block indexer unavailable")
+}
+
+func TestIndexerServiceIndexesTxsIfBlockIndexingFails(t *testing.T) {
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	store := db.NewMemDB()
+	txIndexer := kv.NewTxIndex(store)
+	blockIndexer := failingBlockIndexer{blockidxkv.New(db.NewPrefixDB(store, []byte("block_events")))}
+
+	service := txindex.NewIndexerService(txIndexer, blockIndexer, eventBus, false)
+	service.SetLogger(log.TestingLogger())
+	require.NoError(t, service.Start())
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	events, txResult1, txResult2 := getEventsAndResults(1)
+	require.NoError(t, eventBus.PublishEventNewBlockEvents(events))
+	require.NoError(t, eventBus.PublishEventTx(types.EventDataTx{TxResult: *txResult1}))
+	require.NoError(t, eventBus.PublishEventTx(types.EventDataTx{TxResult: *txResult2}))
+
+	require.Eventually(t, func() bool {
+		res, err := txIndexer.Get(types.Tx("bar1").Hash())
+		return err == nil && res != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
 func createTestSetup(t *testing.T) (*txindex.IndexerService, *kv.TxIndex, indexer.BlockIndexer, *types.EventBus) {
 	t.Helper()
 	// event bus
@@ -109,3 +297,16 @@ func getEventsAndResults(height int64) (types.EventDataNewBlockEvents, *abci.TxR
 	}
 	return events, txResult1, txResult2
 }
+
+// loadEventsAndResults loads the events and results of getEventsAndResults, as
+// a txindex.BlockEventsLoader.
+func loadEventsAndResults(height int64) (types.EventDataNewBlockEvents, *txindex.Batch, error) {
+	events, txResult1, txResult2 := getEventsAndResults(height)
+	batch := txindex.NewBatch(2)
+	for _, txResult := range []*abci.TxResult{txResult1, txResult2} {
+		if err := batch.Add(txResult); err != nil {
+			return events, nil, err
+		}
+	}
+	return events, batch, nil
+}
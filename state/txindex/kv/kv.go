@@ -3,6 +3,7 @@ package kv
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/cosmos/gogoproto/proto"
 
@@ -36,23 +38,36 @@ const (
 var (
 	LastTxIndexerRetainHeightKey = []byte("LastTxIndexerRetainHeightKey")
 	TxIndexerRetainHeightKey     = []byte("TxIndexerRetainHeightKey")
+	ReindexCheckpointKey         = []byte("ReindexCheckpointKey")
+	SinkHeightKeyPrefix          = "SinkHeightKey/"
 )
 
 // TxIndex is the simplest possible indexer, backed by key-value storage (levelDB).
 type TxIndex struct {
 	store dbm.DB
 	// Number the events in the event list
-	eventSeq int64
+	eventSeq atomic.Int64
 
 	log log.Logger
 
 	compact            bool
 	compactionInterval int64
 	lastPruned         int64
+
+	// Selects the types of the events indexed, all if nil
+	eventFilter *indexer.EventFilter
 }
 
 type IndexerOption func(*TxIndex)
 
+// WithEventFilter makes the indexer index only the events selected by f,
+// besides the height and hash of the transactions, which are always indexed.
+func WithEventFilter(f *indexer.EventFilter) IndexerOption {
+	return func(txi *TxIndex) {
+		txi.eventFilter = f
+	}
+}
+
 // WithCompaction sets the compaciton parameters.
 func WithCompaction(compact bool, compactionInterval int64) IndexerOption {
 	return func(txi *TxIndex) {
@@ -189,6 +204,55 @@ func (txi *TxIndex) GetRetainHeight() (int64, error) {
 	return height, nil
 }
 
+// GetReindexCheckpoint returns the progress of the last re-index of the events
+// by the reindex-event command, or state.ErrKeyNotFound if they never were.
+func (txi *TxIndex) GetReindexCheckpoint() (txindex.ReindexCheckpoint, error) {
+	var cp txindex.ReindexCheckpoint
+	buf, err := txi.store.Get(ReindexCheckpointKey)
+	if err != nil {
+		return cp, err
+	}
+	if buf == nil {
+		return cp, state.ErrKeyNotFound
+	}
+	for _, h := range []*int64{&cp.StartHeight, &cp.EndHeight, &cp.Height} {
+		v, n := binary.Varint(buf)
+		if n <= 0 || v < 0 {
+			return cp, state.ErrInvalidHeightValue
+		}
+		*h, buf = v, buf[n:]
+	}
+	return cp, nil
+}
+
+// SetReindexCheckpoint records the progress of a re-index of the events.
+func (txi *TxIndex) SetReindexCheckpoint(cp txindex.ReindexCheckpoint) error {
+	buf := int64ToBytes(cp.StartHeight)
+	buf = append(buf, int64ToBytes(cp.EndHeight)...)
+	buf = append(buf, int64ToBytes(cp.Height)...)
+	return txi.store.SetSync(ReindexCheckpointKey, buf)
+}
+
+// GetSinkHeight returns the height up to which the additional sink with the
+// given name indexed the blocks, or 0 if it was never recorded.
+func (txi *TxIndex) GetSinkHeight(name string) (int64, error) {
+	buf, err := txi.store.Get([]byte(SinkHeightKeyPrefix + name))
+	if err != nil || buf == nil {
+		return 0, err
+	}
+	height := int64FromBytes(buf)
+	if height < 0 {
+		return 0, state.ErrInvalidHeightValue
+	}
+	return height, nil
+}
+
+// SetSinkHeight records the height up to which the additional sink with the
+// given name indexed the blocks.
+func (txi *TxIndex) SetSinkHeight(name string, height int64) error {
+	return txi.store.Set([]byte(SinkHeightKeyPrefix+name), int64ToBytes(height))
+}
+
 func (*TxIndex) setIndexerRetainHeight(height int64, batch dbm.Batch) error {
 	return batch.Set(LastTxIndexerRetainHeightKey, int64ToBytes(height))
 }
@@ -387,9 +451,11 @@ func (txi *TxIndex) deleteEvents(result *abci.TxResult, batch dbm.Batch) error {
 
 func (txi *TxIndex) indexEvents(result *abci.TxResult, hash []byte, store dbm.Batch) error {
 	for _, event := range result.Result.Events {
-		txi.eventSeq++
-		// only index events with a non-empty type
-		if len(event.Type) == 0 {
+		// The sequence number is read once, as the events of several heights
+		// may be indexed concurrently, e.g. by reindex-event.
+		eventSeq := txi.eventSeq.Add(1)
+		// only index events with a non-empty type, selected by the filter
+		if len(event.Type) == 0 || !txi.eventFilter.Indexed(event.Type) {
 			continue
 		}
 
@@ -405,7 +471,7 @@ func (txi *TxIndex) indexEvents(result *abci.TxResult, hash []byte, store dbm.Ba
 				return fmt.Errorf("event type and attribute key \"%s\" is reserved; please use a different key", compositeTag)
 			}
 			if attr.GetIndex() {
-				err := store.Set(keyForEvent(compositeTag, attr.Value, result, txi.eventSeq), hash)
+				err := store.Set(keyForEvent(compositeTag, attr.Value, result, eventSeq), hash)
 				if err != nil {
 					return err
 				}
@@ -419,6 +485,7 @@ func (txi *TxIndex) indexEvents(result *abci.TxResult, hash []byte, store dbm.Ba
 type hashKey struct {
 	hash   string
 	height int64
+	index  uint32
 }
 
 type hashKeySorter struct {
@@ -434,6 +501,9 @@ func byHeightDesc(i, j *hashKey) bool {
 	hi := i.height
 	hj := j.height
 	if hi == hj {
+		if i.index != j.index {
+			return i.index > j.index
+		}
 		return i.hash > j.hash
 	}
 	return hi > hj
@@ -443,6 +513,9 @@ func byHeightAsc(i, j *hashKey) bool {
 	hi := i.height
 	hj := j.height
 	if hi == hj {
+		if i.index != j.index {
+			return i.index < j.index
+		}
 		return i.hash < j.hash
 	}
 	return hi < hj
@@ -467,10 +540,19 @@ func (txi *TxIndex) Search(ctx context.Context, q *query.Query, pagSettings txin
 	default:
 	}
 
-	var hashesInitialized bool
-	filteredHashes := make(map[string]TxInfo)
+	// when resuming from a cursor, only the heights from the cursor on are
+	// searched
+	cursor := pagSettings.Cursor
+	if pagSettings.Limit > 0 && cursor != nil {
+		var err error
+		q, err = indexer.QueryFromHeight(q, types.TxHeightKey, cursor.Height, pagSettings.OrderDesc)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
 
-	// get a list of conditions (like "tx.height > 5")
+	// get a list of conditions (like "tx.height > 5"), if the query is a
+	// conjunction
 	conditions := q.Syntax()
 
 	// if there is a hash condition, return the result immediately
@@ -484,11 +566,136 @@ func (txi *TxIndex) Search(ctx context.Context, q *query.Query, pagSettings txin
 			return []*abci.TxResult{}, 0, fmt.Errorf("error while retrieving the result: %w", err)
 		case res == nil:
 			return []*abci.TxResult{}, 0, nil
+		case pagSettings.Limit > 0 && cursor != nil && !cursor.After(res.Height, res.Index, pagSettings.OrderDesc):
+			return []*abci.TxResult{}, 0, nil
 		default:
 			return []*abci.TxResult{res}, 0, nil
 		}
 	}
 
+	var filteredHashes map[string]TxInfo
+	if conditions != nil {
+		filteredHashes = txi.searchConditions(ctx, conditions)
+	} else {
+		filteredHashes, err = txi.searchDisjunction(ctx, q)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	numResults := len(filteredHashes)
+
+	// Convert map keys to slice for deterministic ordering
+	hashKeys := make([]hashKey, 0, numResults)
+	for k, v := range filteredHashes {
+		hashKeys = append(hashKeys, hashKey{hash: k, height: v.Height, index: v.Index})
+	}
+
+	var by func(i, j *hashKey) bool
+
+	if pagSettings.OrderDesc {
+		by = byHeightDesc
+	} else {
+		by = byHeightAsc
+	}
+
+	// Sort by height
+	sort.Sort(&hashKeySorter{
+		keys: hashKeys,
+		by:   by,
+	})
+
+	switch {
+	case pagSettings.Limit > 0:
+		// Skip the results up to the cursor, then return at most Limit of them.
+		if cursor != nil {
+			first := sort.Search(len(hashKeys), func(i int) bool {
+				return cursor.After(hashKeys[i].height, hashKeys[i].index, pagSettings.OrderDesc)
+			})
+			hashKeys = hashKeys[first:]
+		}
+		numResults = len(hashKeys)
+		if len(hashKeys) > pagSettings.Limit {
+			hashKeys = hashKeys[:pagSettings.Limit]
+		}
+
+	// If paginated, determine which hash keys to return
+	case pagSettings.IsPaginated:
+		// Now that we know the total number of results, validate that the page
+		// requested is within bounds
+		pagSettings.Page, err = validatePage(&pagSettings.Page, pagSettings.PerPage, numResults)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// Calculate pagination start and end indices
+		startIndex := (pagSettings.Page - 1) * pagSettings.PerPage
+		endIndex := startIndex + pagSettings.PerPage
+
+		// Apply pagination limits
+		if endIndex > len(hashKeys) {
+			endIndex = len(hashKeys)
+		}
+		if startIndex >= len(hashKeys) {
+			return []*abci.TxResult{}, 0, nil
+		}
+
+		hashKeys = hashKeys[startIndex:endIndex]
+	}
+
+	results := make([]*abci.TxResult, 0, len(hashKeys))
+	resultMap := make(map[string]struct{})
+RESULTS_LOOP:
+	for _, hKey := range hashKeys {
+		h := filteredHashes[hKey.hash].TxBytes
+		res, err := txi.Get(h)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get Tx{%X}: %w", h, err)
+		}
+		hashString := string(h)
+		if _, ok := resultMap[hashString]; !ok {
+			resultMap[hashString] = struct{}{}
+			results = append(results, res)
+		}
+		// Potentially exit early.
+		select {
+		case <-ctx.Done():
+			break RESULTS_LOOP
+		default:
+		}
+	}
+
+	return results, numResults, nil
+}
+
+// Count returns the number of transactions matching q and, if bucketSize is
+// positive, their number in each range of bucketSize heights. The matching
+// transactions are found as by Search, but are not loaded.
+func (txi *TxIndex) Count(ctx context.Context, q *query.Query, bucketSize int64) (int, []indexer.HeightBucket, error) {
+	select {
+	case <-ctx.Done():
+		return 0, nil, nil
+
+	default:
+	}
+
+	filteredHashes, err := txi.searchDisjunction(ctx, q)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	heights := make([]int64, 0, len(filteredHashes))
+	for _, info := range filteredHashes {
+		heights = append(heights, info.Height)
+	}
+	return len(heights), indexer.CountByHeight(heights, bucketSize), nil
+}
+
+// searchConditions returns the txs matching all the given conditions.
+func (txi *TxIndex) searchConditions(ctx context.Context, conditions []syntax.Condition) map[string]TxInfo {
+	var hashesInitialized bool
+	filteredHashes := make(map[string]TxInfo)
+
 	// conditions to skip because they're handled before "everything else"
 	skipIndexes := make([]int, 0)
 	var heightInfo HeightInfo
@@ -554,75 +761,65 @@ func (txi *TxIndex) Search(ctx context.Context, q *query.Query, pagSettings txin
 		}
 	}
 
-	numResults := len(filteredHashes)
-
-	// Convert map keys to slice for deterministic ordering
-	hashKeys := make([]hashKey, 0, numResults)
-	for k, v := range filteredHashes {
-		hashKeys = append(hashKeys, hashKey{hash: k, height: v.Height})
-	}
-
-	var by func(i, j *hashKey) bool
+	return filteredHashes
+}
 
-	if pagSettings.OrderDesc {
-		by = byHeightDesc
-	} else {
-		by = byHeightAsc
+// searchDisjunction returns the txs matching q, which is not a conjunction of
+// conditions, as the union of the txs matching each of the conjunctions of its
+// disjunctive normal form. The txs matching the negated conditions of a
+// conjunction are looked up in the index and excluded.
+func (txi *TxIndex) searchDisjunction(ctx context.Context, q *query.Query) (map[string]TxInfo, error) {
+	conjunctions, err := indexer.Disjunction(q)
+	if err != nil {
+		return nil, err
 	}
-
-	// Sort by height
-	sort.Sort(&hashKeySorter{
-		keys: hashKeys,
-		by:   by,
-	})
-
-	// If paginated, determine which hash keys to return
-	if pagSettings.IsPaginated {
-		// Now that we know the total number of results, validate that the page
-		// requested is within bounds
-		pagSettings.Page, err = validatePage(&pagSettings.Page, pagSettings.PerPage, numResults)
+	// The results are keyed by hash, as each tx may match several conjunctions.
+	results := make(map[string]TxInfo)
+	for _, conj := range conjunctions {
+		matches, err := txi.searchConjunction(ctx, conj.Conditions)
 		if err != nil {
-			return nil, 0, err
-		}
-
-		// Calculate pagination start and end indices
-		startIndex := (pagSettings.Page - 1) * pagSettings.PerPage
-		endIndex := startIndex + pagSettings.PerPage
-
-		// Apply pagination limits
-		if endIndex > len(hashKeys) {
-			endIndex = len(hashKeys)
+			return nil, err
 		}
-		if startIndex >= len(hashKeys) {
-			return []*abci.TxResult{}, 0, nil
-		}
-
-		hashKeys = hashKeys[startIndex:endIndex]
-	}
-
-	results := make([]*abci.TxResult, 0, len(hashKeys))
-	resultMap := make(map[string]struct{})
-RESULTS_LOOP:
-	for _, hKey := range hashKeys {
-		h := filteredHashes[hKey.hash].TxBytes
-		res, err := txi.Get(h)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to get Tx{%X}: %w", h, err)
+		if len(matches) == 0 {
+			continue
 		}
-		hashString := string(h)
-		if _, ok := resultMap[hashString]; !ok {
-			resultMap[hashString] = struct{}{}
-			results = append(results, res)
+		excluded := make(map[string]struct{})
+		for _, c := range conj.Negated {
+			negMatches, err := txi.searchConjunction(ctx, []syntax.Condition{c})
+			if err != nil {
+				return nil, err
+			}
+			for _, info := range negMatches {
+				excluded[string(info.TxBytes)] = struct{}{}
+			}
 		}
-		// Potentially exit early.
-		select {
-		case <-ctx.Done():
-			break RESULTS_LOOP
-		default:
+		for _, info := range matches {
+			if _, ok := excluded[string(info.TxBytes)]; !ok {
+				results[string(info.TxBytes)] = info
+			}
 		}
 	}
+	return results, nil
+}
 
-	return results, numResults, nil
+// searchConjunction returns the txs matching all the given conditions,
+// including a hash condition.
+func (txi *TxIndex) searchConjunction(ctx context.Context, conditions []syntax.Condition) (map[string]TxInfo, error) {
+	hash, ok, err := lookForHash(conditions)
+	if err != nil {
+		return nil, fmt.Errorf("error during searching for a hash in the query: %w", err)
+	}
+	if !ok {
+		return txi.searchConditions(ctx, conditions), nil
+	}
+	res, err := txi.Get(hash)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving the result: %w", err)
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return map[string]TxInfo{string(hash): {TxBytes: hash, Height: res.Height, Index: res.Index}}, nil
 }
 
 func lookForHash(conditions []syntax.Condition) (hash []byte, ok bool, err error) {
@@ -638,6 +835,7 @@ func lookForHash(conditions []syntax.Condition) (hash []byte, ok bool, err error
 type TxInfo struct {
 	TxBytes []byte
 	Height  int64
+	Index   uint32
 }
 
 func (*TxIndex) setTmpHashes(tmpHeights map[string]TxInfo, key, value []byte, height int64) {
@@ -650,6 +848,7 @@ func (*TxIndex) setTmpHashes(tmpHeights map[string]TxInfo, key, value []byte, he
 	txInfo := TxInfo{
 		TxBytes: valueCp,
 		Height:  height,
+		Index:   extractIndexFromKey(key),
 	}
 	tmpHeights[string(valueCp)+eventSeq] = txInfo
 }
@@ -1011,6 +1210,24 @@ func extractHeightFromKey(key []byte) (int64, error) {
 	return height, nil
 }
 
+// extractIndexFromKey returns the index of the transaction of key, which is
+// the last element of the key, before the event sequence.
+func extractIndexFromKey(key []byte) uint32 {
+	startPos := bytes.LastIndexByte(key, tagKeySeparatorRune)
+	if startPos == -1 {
+		return 0
+	}
+	indexBz := key[startPos+1:]
+	if endPos := bytes.Index(indexBz, []byte(eventSeqSeparator)); endPos != -1 {
+		indexBz = indexBz[:endPos]
+	}
+	index, err := strconv.ParseUint(string(indexBz), 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(index)
+}
+
 func extractValueFromKey(key []byte) string {
 	// Find the positions of tagKeySeparator in the byte slice
 	var indices []int
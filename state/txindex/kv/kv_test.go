@@ -16,6 +16,7 @@ import (
 	abci "github.com/cometbft/cometbft/v2/abci/types"
 	cmtrand "github.com/cometbft/cometbft/v2/internal/rand"
 	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	"github.com/cometbft/cometbft/v2/state/indexer"
 	blockidxkv "github.com/cometbft/cometbft/v2/state/indexer/block/kv"
 	"github.com/cometbft/cometbft/v2/state/txindex"
 	"github.com/cometbft/cometbft/v2/types"
@@ -74,6 +75,26 @@ func TestTxIndex(t *testing.T) {
 	assert.True(t, proto.Equal(txResult2, loadedTxResult2))
 }
 
+func TestTxIndexEventFilter(t *testing.T) {
+	txIndexer := NewTxIndex(db.NewMemDB(), WithEventFilter(indexer.NewEventFilter(nil, []string{"message"})))
+
+	txResult := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []abci.EventAttribute{{Key: "number", Value: "1", Index: true}}},
+		{Type: "message", Attributes: []abci.EventAttribute{{Key: "sender", Value: "addr1", Index: true}}},
+	})
+	require.NoError(t, txIndexer.Index(txResult))
+
+	for q, n := range map[string]int{
+		"account.number = 1":       1,
+		"message.sender = 'addr1'": 0,
+		"tx.height = 1":            1,
+	} {
+		results, _, err := txIndexer.Search(context.Background(), query.MustCompile(q), DefaultPagination)
+		require.NoError(t, err)
+		require.Len(t, results, n, q)
+	}
+}
+
 func TestTxIndex_Prune(t *testing.T) {
 	indexer := NewTxIndex(db.NewMemDB())
 
@@ -218,6 +239,16 @@ func TestTxSearch(t *testing.T) {
 		// search using EXISTS for non existing key
 		{"account.date EXISTS", 0},
 		{"not_allowed EXISTS", 0},
+		// search using OR
+		{"account.number = 2 OR account.owner = '/Ivan/'", 1},
+		{"account.number = 2 OR account.owner = 'Vlad'", 0},
+		{fmt.Sprintf("tx.hash = '%X' OR account.number = 2", hash), 1},
+		// search using NOT
+		{"account.number = 1 AND NOT account.owner = '/Ivan/'", 0},
+		{"account.number = 1 AND NOT account.owner = 'Vlad'", 1},
+		// search using parentheses
+		{"(account.number = 2 OR account.number >= 1) AND NOT account.owner CONTAINS 'Vlad'", 1},
+		{"NOT (account.owner = 'Vlad' OR account.number > 1) AND account.number EXISTS", 1},
 	}
 
 	ctx := context.Background()
@@ -235,6 +266,11 @@ func TestTxSearch(t *testing.T) {
 			}
 		})
 	}
+
+	// A query that can only be answered by scanning all the transactions is
+	// rejected.
+	_, _, err = indexer.Search(ctx, query.MustCompile("NOT account.number = 1"), DefaultPagination)
+	require.Error(t, err)
 }
 
 func TestTxSearchEventMatch(t *testing.T) {
@@ -955,3 +991,137 @@ func TestExtractEventSeqFromKey(t *testing.T) {
 		})
 	}
 }
+
+func TestTxSearchCursor(t *testing.T) {
+	indexer := NewTxIndex(db.NewMemDB())
+
+	for h := int64(1); h <= 3; h++ {
+		for i := uint32(0); i < 3; i++ {
+			txResult := txResultWithEvents([]abci.Event{
+				{Type: "account", Attributes: []abci.EventAttribute{{Key: "number", Value: "1", Index: true}}},
+				{Type: "account", Attributes: []abci.EventAttribute{{Key: "owner", Value: "Ivan", Index: true}}},
+			})
+			txResult.Tx = types.Tx(fmt.Sprintf("tx-%d-%d", h, i))
+			txResult.Height = h
+			txResult.Index = i
+			require.NoError(t, indexer.Index(txResult))
+		}
+	}
+
+	testCases := []struct {
+		q       string
+		pag     txindex.Pagination
+		total   int
+		results []string
+	}{
+		{"account.number = 1", txindex.Pagination{Limit: 2}, 9, []string{"tx-1-0", "tx-1-1"}},
+		{
+			"account.number = 1",
+			txindex.Pagination{Cursor: &txindex.Cursor{Height: 1, Index: 2}, Limit: 4},
+			6, []string{"tx-2-0", "tx-2-1", "tx-2-2", "tx-3-0"},
+		},
+		{
+			"account.number = 1 AND tx.height < 3",
+			txindex.Pagination{Cursor: &txindex.Cursor{Height: 2, Index: 0}, Limit: 4},
+			2, []string{"tx-2-1", "tx-2-2"},
+		},
+		{
+			"account.number = 1 AND tx.height <= 1",
+			txindex.Pagination{OrderDesc: true, Cursor: &txindex.Cursor{Height: 3, Index: 0}, Limit: 5},
+			3, []string{"tx-1-2", "tx-1-1", "tx-1-0"},
+		},
+		{
+			"account.owner = 'Ivan'",
+			txindex.Pagination{OrderDesc: true, Cursor: &txindex.Cursor{Height: 2, Index: 1}, Limit: 2},
+			4, []string{"tx-2-0", "tx-1-2"},
+		},
+		{
+			"account.number = 1 OR tx.height = 3",
+			txindex.Pagination{Cursor: &txindex.Cursor{Height: 3, Index: 2}, Limit: 2},
+			0, []string{},
+		},
+		{
+			"tx.height = 2",
+			txindex.Pagination{Cursor: &txindex.Cursor{Height: 2, Index: 1}, Limit: 5},
+			1, []string{"tx-2-2"},
+		},
+		{
+			fmt.Sprintf("tx.hash = '%X'", types.Tx("tx-2-1").Hash()),
+			txindex.Pagination{Cursor: &txindex.Cursor{Height: 2, Index: 1}, Limit: 5},
+			0, []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.q, func(t *testing.T) {
+			results, total, err := indexer.Search(context.Background(), query.MustCompile(tc.q), tc.pag)
+			require.NoError(t, err)
+			require.Equal(t, tc.total, total)
+			got := make([]string, 0, len(results))
+			for _, r := range results {
+				got = append(got, string(r.Tx))
+			}
+			require.Equal(t, tc.results, got)
+		})
+	}
+}
+
+func TestExtractIndexFromKey(t *testing.T) {
+	testCases := []struct {
+		str      string
+		expected uint32
+	}{
+		{"account.number/1/3/7$es$2", 7},
+		{"account.number/1/3/12", 12},
+		{"account.number", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.str, func(t *testing.T) {
+			assert.Equal(t, tc.expected, extractIndexFromKey([]byte(tc.str)))
+		})
+	}
+}
+
+func TestTxCount(t *testing.T) {
+	txIndexer := NewTxIndex(db.NewMemDB())
+	bucket := func(from, to int64, count int) indexer.HeightBucket {
+		return indexer.HeightBucket{FromHeight: from, ToHeight: to, Count: count}
+	}
+
+	for h := int64(1); h <= 5; h++ {
+		for i := uint32(0); i < 2; i++ {
+			txResult := txResultWithEvents([]abci.Event{
+				{Type: "account", Attributes: []abci.EventAttribute{{Key: "number", Value: fmt.Sprint(h), Index: true}}},
+				{Type: "account", Attributes: []abci.EventAttribute{{Key: "owner", Value: "Ivan", Index: i == 0}}},
+			})
+			txResult.Tx = types.Tx(fmt.Sprintf("tx-%d-%d", h, i))
+			txResult.Height = h
+			txResult.Index = i
+			require.NoError(t, txIndexer.Index(txResult))
+		}
+	}
+
+	testCases := []struct {
+		q          string
+		bucketSize int64
+		total      int
+		buckets    []indexer.HeightBucket
+	}{
+		{"account.number >= 2", 0, 8, nil},
+		{"account.owner = 'Ivan'", 2, 5, []indexer.HeightBucket{bucket(1, 2, 2), bucket(3, 4, 2), bucket(5, 6, 1)}},
+		{"account.number = 1 OR account.number = 5", 3, 4, []indexer.HeightBucket{bucket(1, 3, 2), bucket(4, 6, 2)}},
+		{"tx.height > 1 AND NOT account.owner EXISTS", 10, 4, []indexer.HeightBucket{bucket(1, 10, 4)}},
+		{fmt.Sprintf("tx.hash = '%X'", types.Tx("tx-2-1").Hash()), 1, 1, []indexer.HeightBucket{bucket(2, 2, 1)}},
+		{"account.owner = 'Vlad'", 1, 0, []indexer.HeightBucket{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.q, func(t *testing.T) {
+			total, buckets, err := txIndexer.Count(context.Background(), query.MustCompile(tc.q), tc.bucketSize)
+			require.NoError(t, err)
+			require.Equal(t, tc.total, total)
+			require.Equal(t, tc.buckets, buckets)
+		})
+	}
+}
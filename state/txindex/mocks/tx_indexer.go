@@ -8,6 +8,8 @@ import (
 	log "github.com/cometbft/cometbft/v2/libs/log"
 	mock "github.com/stretchr/testify/mock"
 
+	indexer "github.com/cometbft/cometbft/v2/state/indexer"
+
 	query "github.com/cometbft/cometbft/v2/libs/pubsub/query"
 
 	txindex "github.com/cometbft/cometbft/v2/state/txindex"
@@ -56,6 +58,43 @@ func (_m *TxIndexer) Close() error {
 	return r0
 }
 
+// Count provides a mock function with given fields: ctx, q, bucketSize
+func (_m *TxIndexer) Count(ctx context.Context, q *query.Query, bucketSize int64) (int, []indexer.HeightBucket, error) {
+	ret := _m.Called(ctx, q, bucketSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int
+	var r1 []indexer.HeightBucket
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *query.Query, int64) (int, []indexer.HeightBucket, error)); ok {
+		return rf(ctx, q, bucketSize)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *query.Query, int64) int); ok {
+		r0 = rf(ctx, q, bucketSize)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *query.Query, int64) []indexer.HeightBucket); ok {
+		r1 = rf(ctx, q, bucketSize)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]indexer.HeightBucket)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *query.Query, int64) error); ok {
+		r2 = rf(ctx, q, bucketSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Get provides a mock function with given fields: hash
 func (_m *TxIndexer) Get(hash []byte) (*v2.TxResult, error) {
 	ret := _m.Called(hash)
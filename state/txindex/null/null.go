@@ -7,6 +7,7 @@ import (
 	abci "github.com/cometbft/cometbft/v2/abci/types"
 	"github.com/cometbft/cometbft/v2/libs/log"
 	"github.com/cometbft/cometbft/v2/libs/pubsub/query"
+	"github.com/cometbft/cometbft/v2/state/indexer"
 	"github.com/cometbft/cometbft/v2/state/txindex"
 )
 
@@ -46,6 +47,10 @@ func (*TxIndex) Search(_ context.Context, _ *query.Query, _ txindex.Pagination)
 	return []*abci.TxResult{}, 0, nil
 }
 
+func (*TxIndex) Count(_ context.Context, _ *query.Query, _ int64) (int, []indexer.HeightBucket, error) {
+	return 0, nil, nil
+}
+
 func (*TxIndex) SetLogger(log.Logger) {
 }
 
@@ -0,0 +1,193 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cometbft/cometbft/v2/libs/log"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/store"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// backfillBatchSize is the number of heights whose light blocks, and blocks,
+// Backfill fetches concurrently.
+const backfillBatchSize = 16
+
+// BackfillProvider provides the data backfilled below a state sync snapshot.
+// The light blocks and blocks it returns are not trusted: Backfill verifies
+// them against the commit chain starting at the snapshot.
+type BackfillProvider interface {
+	// LightBlock returns the light block at the given height.
+	LightBlock(ctx context.Context, height int64) (*types.LightBlock, error)
+	// Block returns the block at the given height.
+	Block(ctx context.Context, height int64) (*types.Block, error)
+}
+
+// Backfill fetches the headers and commits below the state sync snapshot from
+// provider, from the height of commit down to stopHeight, and stores them in
+// blockStore along with the validator sets in stateStore. If blocks is true,
+// the full blocks are fetched and stored as well, lowering the base of
+// blockStore.
+//
+// Each header is trusted by hash from the one above it, starting from the
+// block ID of commit, which must be the verified commit of the snapshot
+// height. The commit of each height is verified against its validator set.
+// The heights are fetched concurrently, by batches of backfillBatchSize, and
+// verified and stored from the highest to the lowest.
+func Backfill(
+	ctx context.Context,
+	provider BackfillProvider,
+	blockStore *store.BlockStore,
+	stateStore sm.Store,
+	chainID string,
+	commit *types.Commit,
+	stopHeight int64,
+	blocks bool,
+	logger log.Logger,
+) error {
+	logger.Info("Backfilling blocks", "from", commit.Height, "to", stopHeight, "full_blocks", blocks)
+
+	trustedID := commit.BlockID
+	for top := commit.Height; top >= stopHeight; top -= backfillBatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		bottom := max(top-backfillBatchSize+1, stopHeight)
+		lightBlocks, fullBlocks, err := fetchBackfillBatch(ctx, provider, bottom, top, blocks)
+		if err != nil {
+			return err
+		}
+		for height := top; height >= bottom; height-- {
+			hash := trustedID.Hash
+			trustedID, err = saveBackfilledHeight(chainID, blockStore, stateStore,
+				lightBlocks[top-height], fullBlocks[top-height], blocks, height, trustedID)
+			if err != nil {
+				return err
+			}
+			logger.Debug("Backfilled block", "height", height, "hash", hash)
+		}
+	}
+
+	logger.Info("Backfilled blocks", "from", commit.Height, "to", stopHeight)
+	return nil
+}
+
+// fetchBackfillBatch fetches concurrently the light blocks, and the blocks if
+// blocks is true, from height top down to height bottom.
+func fetchBackfillBatch(
+	ctx context.Context,
+	provider BackfillProvider,
+	bottom, top int64,
+	blocks bool,
+) ([]*types.LightBlock, []*types.Block, error) {
+	lightBlocks := make([]*types.LightBlock, top-bottom+1)
+	fullBlocks := make([]*types.Block, top-bottom+1)
+	g, ctx := errgroup.WithContext(ctx)
+	for height := top; height >= bottom; height-- {
+		i := top - height
+		g.Go(func() error {
+			lb, err := provider.LightBlock(ctx, height)
+			if err != nil {
+				return fmt.Errorf("fetching light block at height %d: %w", height, err)
+			}
+			lightBlocks[i] = lb
+			if !blocks {
+				return nil
+			}
+			block, err := provider.Block(ctx, height)
+			if err != nil {
+				return fmt.Errorf("fetching block at height %d: %w", height, err)
+			}
+			fullBlocks[i] = block
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+	return lightBlocks, fullBlocks, nil
+}
+
+// saveBackfilledHeight verifies the light block lb at height against
+// trustedID, and the block as well if blocks is true, and stores them. It
+// returns the block ID of the height below, trusted from lb.
+func saveBackfilledHeight(
+	chainID string,
+	blockStore *store.BlockStore,
+	stateStore sm.Store,
+	lb *types.LightBlock,
+	block *types.Block,
+	blocks bool,
+	height int64,
+	trustedID types.BlockID,
+) (types.BlockID, error) {
+	if err := verifyBackfilledLightBlock(chainID, lb, height, trustedID); err != nil {
+		return trustedID, fmt.Errorf("invalid light block at height %d: %w", height, err)
+	}
+
+	if blocks {
+		parts, err := verifyBackfilledBlock(block, height, trustedID)
+		if err != nil {
+			return trustedID, fmt.Errorf("invalid block at height %d: %w", height, err)
+		}
+		if err := blockStore.SaveBackfilledBlock(block, parts, lb.Commit); err != nil {
+			return trustedID, fmt.Errorf("saving block at height %d: %w", height, err)
+		}
+	} else {
+		meta := &types.BlockMeta{BlockID: trustedID, Header: *lb.Header}
+		if err := blockStore.SaveBackfilledHeader(meta, lb.Commit); err != nil {
+			return trustedID, fmt.Errorf("saving header at height %d: %w", height, err)
+		}
+	}
+	if err := stateStore.SaveValidatorSet(height, lb.ValidatorSet); err != nil {
+		return trustedID, fmt.Errorf("saving validator set at height %d: %w", height, err)
+	}
+	return lb.LastBlockID, nil
+}
+
+// verifyBackfilledLightBlock checks that lb is the light block at height with
+// the trusted block ID, and that its commit is signed by its validators.
+func verifyBackfilledLightBlock(chainID string, lb *types.LightBlock, height int64, trustedID types.BlockID) error {
+	if lb.SignedHeader == nil || lb.Header == nil {
+		return errors.New("missing header")
+	}
+	if lb.Height != height {
+		return fmt.Errorf("expected height %d, got %d", height, lb.Height)
+	}
+	if err := lb.ValidateBasic(chainID); err != nil {
+		return err
+	}
+	if !bytes.Equal(lb.Hash(), trustedID.Hash) {
+		return fmt.Errorf("header hash %X does not match trusted hash %X", lb.Hash(), trustedID.Hash)
+	}
+	return lb.ValidatorSet.VerifyCommitLight(chainID, trustedID, height, lb.Commit)
+}
+
+// verifyBackfilledBlock checks that block is the complete block with the
+// trusted block ID at height, and returns its parts.
+func verifyBackfilledBlock(block *types.Block, height int64, trustedID types.BlockID) (*types.PartSet, error) {
+	if block == nil {
+		return nil, errors.New("missing block")
+	}
+	if block.Height != height {
+		return nil, fmt.Errorf("expected height %d, got %d", height, block.Height)
+	}
+	if err := block.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	parts, err := block.MakePartSet(types.BlockPartSizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+	if !blockID.Equals(trustedID) {
+		return nil, fmt.Errorf("block ID %v does not match trusted block ID %v", blockID, trustedID)
+	}
+	return parts, nil
+}
@@ -0,0 +1,172 @@
+package statesync
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/v2/internal/test"
+	"github.com/cometbft/cometbft/v2/libs/log"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/store"
+	"github.com/cometbft/cometbft/v2/types"
+	cmttime "github.com/cometbft/cometbft/v2/types/time"
+)
+
+const backfillChainID = "backfill-chain"
+
+// backfillProvider serves the blocks of a test chain, indexed by height.
+type backfillProvider struct {
+	lightBlocks map[int64]*types.LightBlock
+	blocks      map[int64]*types.Block
+}
+
+func (p *backfillProvider) LightBlock(_ context.Context, height int64) (*types.LightBlock, error) {
+	return p.lightBlocks[height], nil
+}
+
+func (p *backfillProvider) Block(_ context.Context, height int64) (*types.Block, error) {
+	return p.blocks[height], nil
+}
+
+// makeBackfillChain returns a provider for a chain of n signed blocks, and the
+// commit of the last one.
+func makeBackfillChain(t *testing.T, n int64) (*backfillProvider, *types.Commit) {
+	t.Helper()
+
+	vals, privVals := test.ValidatorSet(context.Background(), t, 2, 10)
+	p := &backfillProvider{
+		lightBlocks: make(map[int64]*types.LightBlock),
+		blocks:      make(map[int64]*types.Block),
+	}
+	lastCommit := &types.Commit{}
+	lastBlockID := types.BlockID{}
+	for height := int64(1); height <= n; height++ {
+		block := types.MakeBlock(height, test.MakeNTxs(height, 2), lastCommit, nil)
+		block.ChainID = backfillChainID
+		block.Time = cmttime.Now()
+		block.LastBlockID = lastBlockID
+		block.ValidatorsHash = vals.Hash()
+		block.NextValidatorsHash = vals.Hash()
+		block.ProposerAddress = vals.GetProposer().Address
+
+		parts, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+		commit, err := test.MakeCommit(blockID, height, 0, vals, privVals, backfillChainID, block.Time.Add(time.Second))
+		require.NoError(t, err)
+
+		p.blocks[height] = block
+		p.lightBlocks[height] = &types.LightBlock{
+			SignedHeader: &types.SignedHeader{Header: &block.Header, Commit: commit},
+			ValidatorSet: vals,
+		}
+		lastCommit, lastBlockID = commit, blockID
+	}
+	return p, lastCommit
+}
+
+func TestBackfill(t *testing.T) {
+	for _, blocks := range []bool{false, true} {
+		provider, commit := makeBackfillChain(t, 5)
+		blockStore := store.NewBlockStore(dbm.NewMemDB())
+		stateStore := sm.NewStore(dbm.NewMemDB(), sm.StoreOptions{})
+
+		err := Backfill(context.Background(), provider, blockStore, stateStore, backfillChainID, commit, 2, blocks,
+			log.NewNopLogger())
+		require.NoError(t, err)
+
+		for height := int64(2); height <= 5; height++ {
+			meta := blockStore.LoadBlockMeta(height)
+			require.NotNil(t, meta, "height %d", height)
+			assert.Equal(t, provider.lightBlocks[height].Hash(), meta.BlockID.Hash)
+			assert.Equal(t, provider.lightBlocks[height].Commit.Hash(), blockStore.LoadBlockCommit(height).Hash())
+
+			vals, err := stateStore.LoadValidators(height)
+			require.NoError(t, err)
+			assert.Equal(t, provider.lightBlocks[height].ValidatorSet.Hash(), vals.Hash())
+
+			block, _ := blockStore.LoadBlock(height)
+			if blocks {
+				require.NotNil(t, block)
+				assert.Equal(t, provider.blocks[height].Hash(), block.Hash())
+			} else {
+				assert.Nil(t, block)
+			}
+		}
+		assert.Nil(t, blockStore.LoadBlockMeta(1))
+
+		if blocks {
+			assert.EqualValues(t, 2, blockStore.Base())
+			assert.EqualValues(t, 5, blockStore.Height())
+		} else {
+			assert.EqualValues(t, 0, blockStore.Base())
+		}
+	}
+}
+
+// concurrentProvider is a backfillProvider recording the maximum number of
+// light blocks fetched at the same time.
+type concurrentProvider struct {
+	*backfillProvider
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (p *concurrentProvider) LightBlock(ctx context.Context, height int64) (*types.LightBlock, error) {
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		m := p.maxInFlight.Load()
+		if n <= m || p.maxInFlight.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return p.backfillProvider.LightBlock(ctx, height)
+}
+
+func TestBackfillFetchesConcurrently(t *testing.T) {
+	chain, commit := makeBackfillChain(t, 2*backfillBatchSize+3)
+	provider := &concurrentProvider{backfillProvider: chain}
+	blockStore := store.NewBlockStore(dbm.NewMemDB())
+	stateStore := sm.NewStore(dbm.NewMemDB(), sm.StoreOptions{})
+
+	err := Backfill(context.Background(), provider, blockStore, stateStore, backfillChainID, commit, 1, true,
+		log.NewNopLogger())
+	require.NoError(t, err)
+	assert.Greater(t, provider.maxInFlight.Load(), int32(1))
+	assert.LessOrEqual(t, provider.maxInFlight.Load(), int32(backfillBatchSize))
+	assert.EqualValues(t, 1, blockStore.Base())
+	assert.EqualValues(t, commit.Height, blockStore.Height())
+}
+
+func TestBackfillTamperedBlock(t *testing.T) {
+	provider, commit := makeBackfillChain(t, 5)
+	// A header which does not match the hash of its successor is rejected,
+	// even if its commit is properly signed.
+	tampered, _ := makeBackfillChain(t, 5)
+	provider.lightBlocks[3] = tampered.lightBlocks[3]
+
+	blockStore := store.NewBlockStore(dbm.NewMemDB())
+	stateStore := sm.NewStore(dbm.NewMemDB(), sm.StoreOptions{})
+	err := Backfill(context.Background(), provider, blockStore, stateStore, backfillChainID, commit, 1, false,
+		log.NewNopLogger())
+	require.ErrorContains(t, err, "invalid light block at height 3")
+	assert.NotNil(t, blockStore.LoadBlockMeta(4))
+	assert.Nil(t, blockStore.LoadBlockMeta(3))
+
+	// A block whose transactions do not match its header is rejected.
+	provider, commit = makeBackfillChain(t, 5)
+	provider.blocks[4].Data.Txs = test.MakeNTxs(4, 3)
+	blockStore = store.NewBlockStore(dbm.NewMemDB())
+	err = Backfill(context.Background(), provider, blockStore, stateStore, backfillChainID, commit, 1, true,
+		log.NewNopLogger())
+	require.ErrorContains(t, err, "invalid block at height 4")
+	assert.EqualValues(t, 5, blockStore.Base())
+}
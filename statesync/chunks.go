@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/cometbft/cometbft/v2/internal/tempfile"
 	cmtsync "github.com/cometbft/cometbft/v2/libs/sync"
 	"github.com/cometbft/cometbft/v2/p2p"
 )
@@ -36,6 +37,9 @@ type chunkQueue struct {
 	chunkAllocated map[uint32]bool            // chunks that have been allocated via Allocate()
 	chunkReturned  map[uint32]bool            // chunks returned via Next()
 	waiters        map[uint32][]chan<- uint32 // signals WaitFor() waiters about chunk arrival
+	chunkMissing   map[uint32]map[p2p.ID]bool // peers which answered they do not have the chunk
+	missingWaiters map[uint32][]chan struct{} // signals WaitForMissing() waiters about such answers
+	persistent     bool                       // keep the chunk files on Close(), to resume later
 }
 
 // newChunkQueue creates a new chunk queue for a snapshot, using a temp dir for storage.
@@ -56,9 +60,43 @@ func newChunkQueue(snapshot *snapshot, tempDir string) (*chunkQueue, error) {
 		chunkAllocated: make(map[uint32]bool, snapshot.Chunks),
 		chunkReturned:  make(map[uint32]bool, snapshot.Chunks),
 		waiters:        make(map[uint32][]chan<- uint32),
+		chunkMissing:   make(map[uint32]map[p2p.ID]bool),
+		missingWaiters: make(map[uint32][]chan struct{}),
 	}, nil
 }
 
+// newPersistentChunkQueue creates a chunk queue for a snapshot storing its chunks in dir, which
+// are kept when the queue is closed. Chunks already in dir, from a previous attempt at restoring
+// the same snapshot, are loaded into the queue. Callers must call Close() when done.
+func newPersistentChunkQueue(snapshot *snapshot, dir string) (*chunkQueue, error) {
+	if snapshot.Chunks == 0 {
+		return nil, errors.New("snapshot has no chunks")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create dir for state sync chunks: %w", err)
+	}
+	q := &chunkQueue{
+		snapshot:       snapshot,
+		dir:            dir,
+		chunkFiles:     make(map[uint32]string, snapshot.Chunks),
+		chunkSenders:   make(map[uint32]p2p.ID, snapshot.Chunks),
+		chunkAllocated: make(map[uint32]bool, snapshot.Chunks),
+		chunkReturned:  make(map[uint32]bool, snapshot.Chunks),
+		waiters:        make(map[uint32][]chan<- uint32),
+		chunkMissing:   make(map[uint32]map[p2p.ID]bool),
+		missingWaiters: make(map[uint32][]chan struct{}),
+		persistent:     true,
+	}
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		path := filepath.Join(dir, strconv.FormatUint(uint64(i), 10))
+		if _, err := os.Stat(path); err == nil {
+			q.chunkFiles[i] = path
+			q.chunkAllocated[i] = true
+		}
+	}
+	return q, nil
+}
+
 // Add adds a chunk to the queue. It ignores chunks that already exist, returning false.
 func (q *chunkQueue) Add(chunk *chunk) (bool, error) {
 	if chunk == nil || chunk.Chunk == nil {
@@ -83,7 +121,13 @@ func (q *chunkQueue) Add(chunk *chunk) (bool, error) {
 	}
 
 	path := filepath.Join(q.dir, strconv.FormatUint(uint64(chunk.Index), 10))
-	err := os.WriteFile(path, chunk.Chunk, 0o600)
+	var err error
+	if q.persistent {
+		// A partially written chunk must not be picked up when resuming.
+		err = tempfile.WriteFileAtomic(path, chunk.Chunk, 0o600)
+	} else {
+		err = os.WriteFile(path, chunk.Chunk, 0o600)
+	}
 	if err != nil {
 		return false, fmt.Errorf("failed to save chunk %v to file %v: %w", chunk.Index, path, err)
 	}
@@ -120,7 +164,7 @@ func (q *chunkQueue) Allocate() (uint32, error) {
 	return 0, errDone
 }
 
-// Close closes the chunk queue, cleaning up all temporary files.
+// Close closes the chunk queue, cleaning up all temporary files unless the queue is persistent.
 func (q *chunkQueue) Close() error {
 	q.Lock()
 	defer q.Unlock()
@@ -133,7 +177,16 @@ func (q *chunkQueue) Close() error {
 		}
 	}
 	q.waiters = nil
+	for _, waiters := range q.missingWaiters {
+		for _, waiter := range waiters {
+			close(waiter)
+		}
+	}
+	q.missingWaiters = nil
 	q.snapshot = nil
+	if q.persistent {
+		return nil
+	}
 	err := os.RemoveAll(q.dir)
 	if err != nil {
 		return fmt.Errorf("failed to clean up state sync tempdir %v: %w", q.dir, err)
@@ -187,6 +240,48 @@ func (q *chunkQueue) DiscardSender(peerID p2p.ID) error {
 	return nil
 }
 
+// MarkMissing records that the peer which sent chunk, without its contents, does not have it, so
+// that it is requested from another peer. It signals any WaitForMissing() waiters.
+func (q *chunkQueue) MarkMissing(chunk *chunk) error {
+	q.Lock()
+	defer q.Unlock()
+	if q.snapshot == nil {
+		return nil // queue is closed
+	}
+	if chunk.Height != q.snapshot.Height || chunk.Format != q.snapshot.Format {
+		return fmt.Errorf("missing chunk of snapshot %v/%v, expected %v/%v",
+			chunk.Height, chunk.Format, q.snapshot.Height, q.snapshot.Format)
+	}
+	if chunk.Index >= q.snapshot.Chunks {
+		return fmt.Errorf("unexpected missing chunk %v", chunk.Index)
+	}
+	if q.chunkMissing[chunk.Index] == nil {
+		q.chunkMissing[chunk.Index] = make(map[p2p.ID]bool)
+	}
+	q.chunkMissing[chunk.Index][chunk.Sender] = true
+
+	for _, waiter := range q.missingWaiters[chunk.Index] {
+		close(waiter)
+	}
+	delete(q.missingWaiters, chunk.Index)
+	return nil
+}
+
+// MissingFrom returns whether the peer answered it does not have the chunk with the given index.
+func (q *chunkQueue) MissingFrom(index uint32, peerID p2p.ID) bool {
+	q.Lock()
+	defer q.Unlock()
+	return q.chunkMissing[index][peerID]
+}
+
+// ClearMissing forgets the peers which answered they do not have the chunk with the given index,
+// so that they are asked for it again.
+func (q *chunkQueue) ClearMissing(index uint32) {
+	q.Lock()
+	defer q.Unlock()
+	delete(q.chunkMissing, index)
+}
+
 // GetSender returns the sender of the chunk with the given index, or empty if not found.
 func (q *chunkQueue) GetSender(index uint32) p2p.ID {
 	q.Lock()
@@ -278,6 +373,13 @@ func (q *chunkQueue) Retry(index uint32) {
 	delete(q.chunkReturned, index)
 }
 
+// Skip marks a chunk as returned, so that Next() does not return it unless it is retried.
+func (q *chunkQueue) Skip(index uint32) {
+	q.Lock()
+	defer q.Unlock()
+	q.chunkReturned[index] = true
+}
+
 // RetryAll schedules all chunks to be retried, without refetching them.
 func (q *chunkQueue) RetryAll() {
 	q.Lock()
@@ -318,3 +420,17 @@ func (q *chunkQueue) WaitFor(index uint32) <-chan uint32 {
 	}
 	return ch
 }
+
+// WaitForMissing returns a channel that is closed when a peer answers it does not have the chunk
+// with the given index, or when the queue is closed.
+func (q *chunkQueue) WaitForMissing(index uint32) <-chan struct{} {
+	q.Lock()
+	defer q.Unlock()
+	ch := make(chan struct{})
+	if q.snapshot == nil {
+		close(ch)
+		return ch
+	}
+	q.missingWaiters[index] = append(q.missingWaiters[index], ch)
+	return ch
+}
@@ -2,6 +2,7 @@ package statesync
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -54,6 +55,39 @@ func TestNewChunkQueue_TempDir(t *testing.T) {
 	assert.Empty(t, files)
 }
 
+func TestNewPersistentChunkQueue(t *testing.T) {
+	snapshot := &snapshot{Height: 3, Format: 1, Chunks: 3, Hash: []byte{7}}
+	dir := filepath.Join(t.TempDir(), "chunks")
+
+	queue, err := newPersistentChunkQueue(snapshot, dir)
+	require.NoError(t, err)
+	_, err = queue.Add(&chunk{Height: 3, Format: 1, Index: 1, Chunk: []byte{3, 1, 1}})
+	require.NoError(t, err)
+	require.NoError(t, queue.Close())
+
+	// The chunks survive the queue, and are loaded by the next one.
+	queue, err = newPersistentChunkQueue(snapshot, dir)
+	require.NoError(t, err)
+	defer queue.Close()
+	assert.False(t, queue.Has(0))
+	assert.True(t, queue.Has(1))
+
+	index, err := queue.Allocate()
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, index)
+	index, err = queue.Allocate()
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, index)
+
+	// Skipped chunks are not returned until retried.
+	queue.Skip(0)
+	c, err := queue.Next()
+	require.NoError(t, err)
+	assert.Equal(t, &chunk{Height: 3, Format: 1, Index: 1, Chunk: []byte{3, 1, 1}}, c)
+	queue.RetryAll()
+	assert.Equal(t, <-queue.WaitFor(1), uint32(1))
+}
+
 func TestChunkQueue(t *testing.T) {
 	queue, teardown := setupChunkQueue(t)
 	defer teardown()
@@ -310,6 +344,34 @@ func TestChunkQueue_DiscardSender(t *testing.T) {
 	assert.Equal(t, errDone, err)
 }
 
+func TestChunkQueue_MarkMissing(t *testing.T) {
+	queue, teardown := setupChunkQueue(t)
+	defer teardown()
+
+	require.Error(t, queue.MarkMissing(&chunk{Height: 2, Format: 1, Index: 0, Sender: "a"}))
+	require.Error(t, queue.MarkMissing(&chunk{Height: 3, Format: 1, Index: 5, Sender: "a"}))
+
+	missing := queue.WaitForMissing(1)
+	require.NoError(t, queue.MarkMissing(&chunk{Height: 3, Format: 1, Index: 1, Sender: "a"}))
+	select {
+	case <-missing:
+	default:
+		t.Fatal("waiter not signaled")
+	}
+	assert.True(t, queue.MissingFrom(1, "a"))
+	assert.False(t, queue.MissingFrom(1, "b"))
+	assert.False(t, queue.MissingFrom(0, "a"))
+
+	queue.ClearMissing(1)
+	assert.False(t, queue.MissingFrom(1, "a"))
+
+	// Closing the queue releases the waiters.
+	missing = queue.WaitForMissing(2)
+	require.NoError(t, queue.Close())
+	_, ok := <-missing
+	assert.False(t, ok)
+}
+
 func TestChunkQueue_GetSender(t *testing.T) {
 	queue, teardown := setupChunkQueue(t)
 	defer teardown()
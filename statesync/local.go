@@ -0,0 +1,118 @@
+package statesync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/cometbft/cometbft/v2/config"
+	cmtbytes "github.com/cometbft/cometbft/v2/libs/bytes"
+	"github.com/cometbft/cometbft/v2/libs/log"
+	"github.com/cometbft/cometbft/v2/proxy"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// LocalSnapshot is the metadata of a snapshot stored on disk, as found in the
+// metadata file read by RestoreLocalSnapshot. It mirrors the snapshot
+// advertised by the app in ListSnapshots.
+type LocalSnapshot struct {
+	Height   uint64            `json:"height"`
+	Format   uint32            `json:"format"`
+	Chunks   uint32            `json:"chunks"`
+	Hash     cmtbytes.HexBytes `json:"hash"`
+	Metadata []byte            `json:"metadata"`
+}
+
+// LoadLocalSnapshot reads the JSON snapshot metadata file at path.
+func LoadLocalSnapshot(path string) (*LocalSnapshot, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ls LocalSnapshot
+	if err := json.Unmarshal(bz, &ls); err != nil {
+		return nil, fmt.Errorf("parsing snapshot metadata %s: %w", path, err)
+	}
+	if ls.Height == 0 {
+		return nil, fmt.Errorf("snapshot metadata %s: height cannot be 0", path)
+	}
+	if ls.Chunks == 0 {
+		return nil, fmt.Errorf("snapshot metadata %s: no chunks", path)
+	}
+	return &ls, nil
+}
+
+// RestoreLocalSnapshot restores the app's state from a snapshot on disk,
+// rather than one fetched from peers. The snapshot is described by the
+// metadata file at metadataPath, and its chunks are the files in chunksDir
+// named after their index ("0", "1", ...). The snapshot is offered and its
+// chunks applied to the app just like during state sync, and the resulting
+// app hash is verified against the one obtained from stateProvider.
+//
+// It returns the state and commit at the snapshot height, which the caller
+// must use to bootstrap the node.
+func RestoreLocalSnapshot(
+	cfg config.StateSyncConfig,
+	logger log.Logger,
+	conn proxy.AppConnSnapshot,
+	connQuery proxy.AppConnQuery,
+	stateProvider StateProvider,
+	metadataPath string,
+	chunksDir string,
+) (sm.State, *types.Commit, error) {
+	ls, err := LoadLocalSnapshot(metadataPath)
+	if err != nil {
+		return sm.State{}, nil, err
+	}
+	snapshot := &snapshot{
+		Height:   ls.Height,
+		Format:   ls.Format,
+		Chunks:   ls.Chunks,
+		Hash:     ls.Hash,
+		Metadata: ls.Metadata,
+	}
+
+	chunks, err := newChunkQueue(snapshot, cfg.TempDir)
+	if err != nil {
+		return sm.State{}, nil, err
+	}
+	defer chunks.Close()
+
+	loadChunk := func(index uint32) (*chunk, error) {
+		bz, err := os.ReadFile(filepath.Join(chunksDir, strconv.FormatUint(uint64(index), 10)))
+		if err != nil {
+			return nil, fmt.Errorf("reading chunk %d: %w", index, err)
+		}
+		if bz == nil {
+			bz = []byte{}
+		}
+		return &chunk{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Index:  index,
+			Chunk:  bz,
+		}, nil
+	}
+	for index := uint32(0); index < snapshot.Chunks; index++ {
+		c, err := loadChunk(index)
+		if err != nil {
+			return sm.State{}, nil, err
+		}
+		if _, err := chunks.Add(c); err != nil {
+			return sm.State{}, nil, fmt.Errorf("adding chunk %d: %w", index, err)
+		}
+	}
+
+	s := newSyncer(cfg, logger, conn, connQuery, stateProvider, cfg.TempDir)
+	// All the chunks are already in the queue: no need to fetch any. The ones the app asks to
+	// refetch are reloaded from chunksDir.
+	s.loadChunk = loadChunk
+	state, commit, err := s.restore(snapshot, chunks, 0)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("restoring snapshot at height %d: %w", snapshot.Height, err)
+	}
+	return state, commit, nil
+}
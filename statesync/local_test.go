@@ -0,0 +1,170 @@
+package statesync
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	cmtstate "github.com/cometbft/cometbft/api/cometbft/state/v2"
+	cmtversion "github.com/cometbft/cometbft/api/cometbft/version/v1"
+	abci "github.com/cometbft/cometbft/v2/abci/types"
+	"github.com/cometbft/cometbft/v2/config"
+	"github.com/cometbft/cometbft/v2/libs/log"
+	"github.com/cometbft/cometbft/v2/proxy"
+	proxymocks "github.com/cometbft/cometbft/v2/proxy/mocks"
+	sm "github.com/cometbft/cometbft/v2/state"
+	"github.com/cometbft/cometbft/v2/statesync/mocks"
+	"github.com/cometbft/cometbft/v2/types"
+)
+
+// writeLocalSnapshot writes the metadata and chunks of a snapshot to dir.
+func writeLocalSnapshot(t *testing.T, dir string, metadata string, chunks [][]byte) (string, string) {
+	t.Helper()
+
+	metadataPath := filepath.Join(dir, "snapshot.json")
+	require.NoError(t, os.WriteFile(metadataPath, []byte(metadata), 0o600))
+	chunksDir := filepath.Join(dir, "chunks")
+	require.NoError(t, os.Mkdir(chunksDir, 0o700))
+	for i, chunk := range chunks {
+		require.NoError(t, os.WriteFile(filepath.Join(chunksDir, strconv.Itoa(i)), chunk, 0o600))
+	}
+	return metadataPath, chunksDir
+}
+
+func TestRestoreLocalSnapshot(t *testing.T) {
+	metadataPath, chunksDir := writeLocalSnapshot(t, t.TempDir(),
+		`{"height": 3, "format": 1, "chunks": 2, "hash": "010203", "metadata": "AQ=="}`,
+		[][]byte{{1, 0}, {1, 1}})
+
+	state := sm.State{
+		Version:         cmtstate.Version{Consensus: cmtversion.Consensus{App: testAppVersion}},
+		LastBlockHeight: 3,
+		AppHash:         []byte("app_hash"),
+	}
+	commit := &types.Commit{Height: 3}
+	stateProvider := &mocks.StateProvider{}
+	stateProvider.On("AppHash", mock.Anything, uint64(3)).Return(state.AppHash, nil)
+	stateProvider.On("State", mock.Anything, uint64(3)).Return(state, nil)
+	stateProvider.On("Commit", mock.Anything, uint64(3)).Return(commit, nil)
+
+	connSnapshot := &proxymocks.AppConnSnapshot{}
+	connSnapshot.On("OfferSnapshot", mock.Anything, &abci.OfferSnapshotRequest{
+		Snapshot: &abci.Snapshot{Height: 3, Format: 1, Chunks: 2, Hash: []byte{1, 2, 3}, Metadata: []byte{1}},
+		AppHash:  []byte("app_hash"),
+	}).Return(&abci.OfferSnapshotResponse{Result: abci.OFFER_SNAPSHOT_RESULT_ACCEPT}, nil)
+	for i := uint32(0); i < 2; i++ {
+		connSnapshot.On("ApplySnapshotChunk", mock.Anything, &abci.ApplySnapshotChunkRequest{
+			Index: i, Chunk: []byte{1, byte(i)},
+		}).Once().Return(&abci.ApplySnapshotChunkResponse{Result: abci.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT}, nil)
+	}
+	connQuery := &proxymocks.AppConnQuery{}
+	connQuery.On("Info", mock.Anything, proxy.InfoRequest).Return(&abci.InfoResponse{
+		AppVersion:       testAppVersion,
+		LastBlockHeight:  3,
+		LastBlockAppHash: []byte("app_hash"),
+	}, nil)
+
+	cfg := config.DefaultStateSyncConfig()
+	newState, newCommit, err := RestoreLocalSnapshot(*cfg, log.NewNopLogger(), connSnapshot, connQuery,
+		stateProvider, metadataPath, chunksDir)
+	require.NoError(t, err)
+	assert.Equal(t, state, newState)
+	assert.Equal(t, commit, newCommit)
+
+	connSnapshot.AssertExpectations(t)
+	connQuery.AssertExpectations(t)
+}
+
+func TestRestoreLocalSnapshotRefetchChunks(t *testing.T) {
+	metadataPath, chunksDir := writeLocalSnapshot(t, t.TempDir(),
+		`{"height": 3, "format": 1, "chunks": 3, "hash": "010203"}`,
+		[][]byte{{1, 0}, {1, 1}, {1, 2}})
+
+	state := sm.State{
+		Version:         cmtstate.Version{Consensus: cmtversion.Consensus{App: testAppVersion}},
+		LastBlockHeight: 3,
+		AppHash:         []byte("app_hash"),
+	}
+	stateProvider := &mocks.StateProvider{}
+	stateProvider.On("AppHash", mock.Anything, uint64(3)).Return(state.AppHash, nil)
+	stateProvider.On("State", mock.Anything, uint64(3)).Return(state, nil)
+	stateProvider.On("Commit", mock.Anything, uint64(3)).Return(&types.Commit{Height: 3}, nil)
+
+	connSnapshot := &proxymocks.AppConnSnapshot{}
+	connSnapshot.On("OfferSnapshot", mock.Anything, mock.Anything).
+		Return(&abci.OfferSnapshotResponse{Result: abci.OFFER_SNAPSHOT_RESULT_ACCEPT}, nil)
+	apply := func(index uint32) *mock.Call {
+		return connSnapshot.On("ApplySnapshotChunk", mock.Anything, &abci.ApplySnapshotChunkRequest{
+			Index: index, Chunk: []byte{1, byte(index)},
+		}).Once()
+	}
+	// The app asks for chunk 0 to be refetched while applying chunk 1, and for
+	// chunk 2 to be refetched instead of applying it.
+	apply(0).Return(&abci.ApplySnapshotChunkResponse{Result: abci.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT}, nil)
+	apply(1).Return(&abci.ApplySnapshotChunkResponse{
+		Result:        abci.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT,
+		RefetchChunks: []uint32{0},
+	}, nil)
+	apply(0).Return(&abci.ApplySnapshotChunkResponse{Result: abci.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT}, nil)
+	apply(2).Return(&abci.ApplySnapshotChunkResponse{
+		Result:        abci.APPLY_SNAPSHOT_CHUNK_RESULT_RETRY,
+		RefetchChunks: []uint32{2},
+	}, nil)
+	apply(2).Return(&abci.ApplySnapshotChunkResponse{Result: abci.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT}, nil)
+	connQuery := &proxymocks.AppConnQuery{}
+	connQuery.On("Info", mock.Anything, proxy.InfoRequest).Return(&abci.InfoResponse{
+		AppVersion:       testAppVersion,
+		LastBlockHeight:  3,
+		LastBlockAppHash: []byte("app_hash"),
+	}, nil)
+
+	done := make(chan error, 1)
+	go func() {
+		cfg := config.DefaultStateSyncConfig()
+		_, _, err := RestoreLocalSnapshot(*cfg, log.NewNopLogger(), connSnapshot, connQuery,
+			stateProvider, metadataPath, chunksDir)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("restoring the snapshot did not complete")
+	}
+	connSnapshot.AssertExpectations(t)
+}
+
+func TestRestoreLocalSnapshotMissingChunk(t *testing.T) {
+	metadataPath, chunksDir := writeLocalSnapshot(t, t.TempDir(),
+		`{"height": 3, "format": 1, "chunks": 2, "hash": "010203"}`,
+		[][]byte{{1, 0}})
+
+	cfg := config.DefaultStateSyncConfig()
+	_, _, err := RestoreLocalSnapshot(*cfg, log.NewNopLogger(), &proxymocks.AppConnSnapshot{},
+		&proxymocks.AppConnQuery{}, &mocks.StateProvider{}, metadataPath, chunksDir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoadLocalSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "snapshot.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"height": 0, "chunks": 1}`), 0o600))
+	_, err := LoadLocalSnapshot(path)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"height": 1, "chunks": 0}`), 0o600))
+	_, err = LoadLocalSnapshot(path)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"height": 10, "format": 2, "chunks": 4, "hash": "ABCD"}`), 0o600))
+	ls, err := LoadLocalSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, &LocalSnapshot{Height: 10, Format: 2, Chunks: 4, Hash: []byte{0xab, 0xcd}}, ls)
+}
@@ -20,11 +20,25 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "syncing",
 			Help:      "Whether or not a node is state syncing. 1 if yes, 0 if no.",
 		}, labels).With(labelsAndValues...),
+		ServedChunkRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "served_chunk_requests",
+			Help:      "Number of snapshot chunk requests from peers that were served.",
+		}, labels).With(labelsAndValues...),
+		ThrottledChunkRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "throttled_chunk_requests",
+			Help:      "Number of snapshot chunk requests from peers that were turned down because of the serving limits.",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
 func NopMetrics() *Metrics {
 	return &Metrics{
-		Syncing: discard.NewGauge(),
+		Syncing:                discard.NewGauge(),
+		ServedChunkRequests:    discard.NewCounter(),
+		ThrottledChunkRequests: discard.NewCounter(),
 	}
 }
@@ -16,4 +16,9 @@ const (
 type Metrics struct {
 	// Whether or not a node is state syncing. 1 if yes, 0 if no.
 	Syncing metrics.Gauge
+	// Number of snapshot chunk requests from peers that were served.
+	ServedChunkRequests metrics.Counter
+	// Number of snapshot chunk requests from peers that were turned down
+	// because of the serving limits.
+	ThrottledChunkRequests metrics.Counter
 }
@@ -11,6 +11,7 @@ import (
 	ssproto "github.com/cometbft/cometbft/api/cometbft/statesync/v1"
 	abci "github.com/cometbft/cometbft/v2/abci/types"
 	"github.com/cometbft/cometbft/v2/config"
+	"github.com/cometbft/cometbft/v2/internal/servelimit"
 	cmtsync "github.com/cometbft/cometbft/v2/libs/sync"
 	"github.com/cometbft/cometbft/v2/p2p"
 	tcpconn "github.com/cometbft/cometbft/v2/p2p/transport/tcp/conn"
@@ -39,6 +40,9 @@ type Reactor struct {
 	tempDir   string
 	metrics   *Metrics
 
+	// limits the chunk requests from peers served concurrently.
+	serveLimiter *servelimit.Limiter
+
 	// This will only be set when a state sync is in progress. It is used to feed received
 	// snapshots and chunks into the sync.
 	mtx    cmtsync.RWMutex
@@ -53,10 +57,11 @@ func NewReactor(
 	metrics *Metrics,
 ) *Reactor {
 	r := &Reactor{
-		cfg:       cfg,
-		conn:      conn,
-		connQuery: connQuery,
-		metrics:   metrics,
+		cfg:          cfg,
+		conn:         conn,
+		connQuery:    connQuery,
+		metrics:      metrics,
+		serveLimiter: servelimit.New(cfg.ServeMaxConcurrent, cfg.ServeMaxConcurrentPerPeer),
 	}
 	r.BaseReactor = *p2p.NewBaseReactor("StateSync", r)
 
@@ -174,28 +179,7 @@ func (r *Reactor) Receive(e p2p.Envelope) {
 		case *ssproto.ChunkRequest:
 			r.Logger.Debug("Received chunk request", "height", msg.Height, "format", msg.Format,
 				"chunk", msg.Index, "peer", e.Src.ID())
-			resp, err := r.conn.LoadSnapshotChunk(context.TODO(), &abci.LoadSnapshotChunkRequest{
-				Height: msg.Height,
-				Format: msg.Format,
-				Chunk:  msg.Index,
-			})
-			if err != nil {
-				r.Logger.Error("Failed to load chunk", "height", msg.Height, "format", msg.Format,
-					"chunk", msg.Index, "err", err)
-				return
-			}
-			r.Logger.Debug("Sending chunk", "height", msg.Height, "format", msg.Format,
-				"chunk", msg.Index, "peer", e.Src.ID())
-			_ = e.Src.Send(p2p.Envelope{
-				ChannelID: ChunkChannel,
-				Message: &ssproto.ChunkResponse{
-					Height:  msg.Height,
-					Format:  msg.Format,
-					Index:   msg.Index,
-					Chunk:   resp.Chunk,
-					Missing: resp.Chunk == nil,
-				},
-			})
+			r.serveChunkRequest(msg, e.Src)
 
 		case *ssproto.ChunkResponse:
 			r.mtx.RLock()
@@ -204,6 +188,20 @@ func (r *Reactor) Receive(e p2p.Envelope) {
 				r.Logger.Debug("Received unexpected chunk, no state sync in progress", "peer", e.Src.ID())
 				return
 			}
+			if msg.Missing {
+				r.Logger.Debug("Received missing chunk", "height", msg.Height, "format", msg.Format,
+					"chunk", msg.Index, "peer", e.Src.ID())
+				if err := r.syncer.MissingChunk(&chunk{
+					Height: msg.Height,
+					Format: msg.Format,
+					Index:  msg.Index,
+					Sender: e.Src.ID(),
+				}); err != nil {
+					r.Logger.Error("Failed to record missing chunk", "height", msg.Height, "format", msg.Format,
+						"chunk", msg.Index, "err", err)
+				}
+				return
+			}
 			r.Logger.Debug("Received chunk, adding to sync", "height", msg.Height, "format", msg.Format,
 				"chunk", msg.Index, "peer", e.Src.ID())
 			_, err := r.syncer.AddChunk(&chunk{
@@ -295,3 +293,62 @@ func (r *Reactor) Sync(stateProvider StateProvider, maxDiscoveryTime time.Durati
 	r.mtx.Unlock()
 	return state, commit, err
 }
+
+// serveChunkRequest responds to a chunk request within the serving limits.
+// Requests over the limits are answered as missing chunks, so that the peer
+// asks someone else.
+func (r *Reactor) serveChunkRequest(msg *ssproto.ChunkRequest, src p2p.Peer) {
+	if r.serveLimiter.Unlimited() {
+		r.respondToChunkRequest(msg, src)
+		return
+	}
+	if !r.serveLimiter.Acquire(src.ID()) {
+		r.metrics.ThrottledChunkRequests.Add(1)
+		r.Logger.Debug("Too many chunk requests being served, turning down request", "height", msg.Height,
+			"format", msg.Format, "chunk", msg.Index, "peer", src.ID())
+		_ = src.Send(p2p.Envelope{
+			ChannelID: ChunkChannel,
+			Message: &ssproto.ChunkResponse{
+				Height:  msg.Height,
+				Format:  msg.Format,
+				Index:   msg.Index,
+				Missing: true,
+			},
+		})
+		return
+	}
+	go func() {
+		defer r.serveLimiter.Release(src.ID())
+		r.respondToChunkRequest(msg, src)
+	}()
+}
+
+// respondToChunkRequest loads a chunk from the application and sends it to
+// the requesting peer.
+func (r *Reactor) respondToChunkRequest(msg *ssproto.ChunkRequest, src p2p.Peer) {
+	resp, err := r.conn.LoadSnapshotChunk(context.TODO(), &abci.LoadSnapshotChunkRequest{
+		Height: msg.Height,
+		Format: msg.Format,
+		Chunk:  msg.Index,
+	})
+	if err != nil {
+		r.Logger.Error("Failed to load chunk", "height", msg.Height, "format", msg.Format,
+			"chunk", msg.Index, "err", err)
+		return
+	}
+	r.Logger.Debug("Sending chunk", "height", msg.Height, "format", msg.Format,
+		"chunk", msg.Index, "peer", src.ID())
+	err = src.Send(p2p.Envelope{
+		ChannelID: ChunkChannel,
+		Message: &ssproto.ChunkResponse{
+			Height:  msg.Height,
+			Format:  msg.Format,
+			Index:   msg.Index,
+			Chunk:   resp.Chunk,
+			Missing: resp.Chunk == nil,
+		},
+	})
+	if err == nil {
+		r.metrics.ServedChunkRequests.Add(1)
+	}
+}
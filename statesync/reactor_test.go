@@ -1,6 +1,7 @@
 package statesync
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -12,9 +13,11 @@ import (
 	ssproto "github.com/cometbft/cometbft/api/cometbft/statesync/v1"
 	abci "github.com/cometbft/cometbft/v2/abci/types"
 	"github.com/cometbft/cometbft/v2/config"
+	"github.com/cometbft/cometbft/v2/libs/log"
 	"github.com/cometbft/cometbft/v2/p2p"
 	p2pmocks "github.com/cometbft/cometbft/v2/p2p/mocks"
 	proxymocks "github.com/cometbft/cometbft/v2/proxy/mocks"
+	"github.com/cometbft/cometbft/v2/statesync/mocks"
 )
 
 func TestReactor_Receive_ChunkRequest(t *testing.T) {
@@ -95,6 +98,61 @@ func TestReactor_Receive_ChunkRequest(t *testing.T) {
 	}
 }
 
+func TestReactor_Receive_ChunkRequestThrottled(t *testing.T) {
+	// The first request blocks in the app until released.
+	release := make(chan time.Time)
+	conn := &proxymocks.AppConnSnapshot{}
+	conn.On("LoadSnapshotChunk", mock.Anything, &abci.LoadSnapshotChunkRequest{Height: 1, Format: 1, Chunk: 0}).
+		WaitUntil(release).
+		Return(&abci.LoadSnapshotChunkResponse{Chunk: []byte{1}}, nil)
+
+	responses := make(chan *ssproto.ChunkResponse, 2)
+	peer := &p2pmocks.Peer{}
+	peer.On("ID").Return(p2p.ID("id"))
+	peer.On("Send", mock.MatchedBy(func(i any) bool {
+		e, ok := i.(p2p.Envelope)
+		return ok && e.ChannelID == ChunkChannel
+	})).Run(func(args mock.Arguments) {
+		responses <- args[0].(p2p.Envelope).Message.(*ssproto.ChunkResponse)
+	}).Return(nil)
+
+	cfg := config.DefaultStateSyncConfig()
+	cfg.ServeMaxConcurrentPerPeer = 1
+	r := NewReactor(*cfg, conn, nil, NopMetrics())
+	require.NoError(t, r.Start())
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	r.Receive(p2p.Envelope{ChannelID: ChunkChannel, Src: peer,
+		Message: &ssproto.ChunkRequest{Height: 1, Format: 1, Index: 0}})
+	r.Receive(p2p.Envelope{ChannelID: ChunkChannel, Src: peer,
+		Message: &ssproto.ChunkRequest{Height: 1, Format: 1, Index: 1}})
+
+	// The second request is turned down while the first one is in flight.
+	select {
+	case resp := <-responses:
+		assert.Equal(t, &ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Missing: true}, resp)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the throttled response")
+	}
+
+	close(release)
+	select {
+	case resp := <-responses:
+		assert.Equal(t, &ssproto.ChunkResponse{Height: 1, Format: 1, Index: 0, Chunk: []byte{1}}, resp)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the chunk")
+	}
+	require.Eventually(t, func() bool {
+		total, _ := r.serveLimiter.InFlight("id")
+		return total == 0
+	}, time.Second, 10*time.Millisecond)
+	conn.AssertExpectations(t)
+}
+
 func TestReactor_Receive_SnapshotsRequest(t *testing.T) {
 	testcases := map[string]struct {
 		snapshots       []*abci.Snapshot
@@ -183,3 +241,66 @@ func TestReactor_Receive_SnapshotsRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestReactor_Receive_MissingChunk(t *testing.T) {
+	cfg := config.DefaultStateSyncConfig()
+	// Long enough for the chunk to be requested again only because it is missing.
+	cfg.ChunkRequestTimeout = time.Minute
+	r := NewReactor(*cfg, &proxymocks.AppConnSnapshot{}, nil, NopMetrics())
+	require.NoError(t, r.Start())
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	s := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	chunks, err := newChunkQueue(s, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = chunks.Close() })
+	r.syncer = newSyncer(*cfg, log.NewNopLogger(), &proxymocks.AppConnSnapshot{}, &proxymocks.AppConnQuery{},
+		&mocks.StateProvider{}, "")
+	r.syncer.chunks = chunks
+
+	requests := make(chan p2p.ID, 4)
+	peers := make(map[p2p.ID]*p2pmocks.Peer)
+	for _, id := range []p2p.ID{"a", "b"} {
+		peer := &p2pmocks.Peer{}
+		peer.On("ID").Return(id)
+		peer.On("Send", mock.MatchedBy(func(i any) bool {
+			e, ok := i.(p2p.Envelope)
+			return ok && e.ChannelID == ChunkChannel
+		})).Run(func(mock.Arguments) { requests <- id }).Return(nil)
+		peers[id] = peer
+		_, err := r.syncer.AddSnapshot(peer, s)
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.syncer.fetchChunks(ctx, s, chunks)
+
+	nextRequest := func() p2p.ID {
+		select {
+		case id := <-requests:
+			return id
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a chunk request")
+			return ""
+		}
+	}
+
+	// The first peer asked does not have the chunk: it is requested from the
+	// other one.
+	first := nextRequest()
+	r.Receive(p2p.Envelope{ChannelID: ChunkChannel, Src: peers[first],
+		Message: &ssproto.ChunkResponse{Height: 1, Format: 1, Index: 0, Missing: true}})
+	second := nextRequest()
+	assert.NotEqual(t, first, second)
+	assert.True(t, chunks.MissingFrom(0, first))
+
+	r.Receive(p2p.Envelope{ChannelID: ChunkChannel, Src: peers[second],
+		Message: &ssproto.ChunkResponse{Height: 1, Format: 1, Index: 0, Chunk: []byte{1}}})
+	require.Eventually(t, func() bool { return chunks.Has(0) }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, second, chunks.GetSender(0))
+}
@@ -0,0 +1,123 @@
+package statesync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cometbft/cometbft/v2/internal/tempfile"
+)
+
+const (
+	resumeSnapshotFile = "snapshot.json"
+	resumeAppliedFile  = "applied"
+	resumeChunksDir    = "chunks"
+)
+
+// resumeState persists the progress of a snapshot restoration in a directory, so that it can be
+// resumed after a restart: the snapshot being restored, its chunks, and which chunks the app has
+// acknowledged.
+type resumeState struct {
+	dir      string
+	snapshot *snapshot
+	applied  []byte // bitmap of the chunks accepted by the app
+}
+
+// newResumeState starts persisting the restoration of snapshot in dir, discarding any previous
+// progress.
+func newResumeState(dir string, snapshot *snapshot) (*resumeState, error) {
+	if err := clearResumeState(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	bz, err := json.Marshal(LocalSnapshot{
+		Height:   snapshot.Height,
+		Format:   snapshot.Format,
+		Chunks:   snapshot.Chunks,
+		Hash:     snapshot.Hash,
+		Metadata: snapshot.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tempfile.WriteFileAtomic(filepath.Join(dir, resumeSnapshotFile), bz, 0o600); err != nil {
+		return nil, fmt.Errorf("persisting snapshot: %w", err)
+	}
+	rs := &resumeState{dir: dir, snapshot: snapshot, applied: make([]byte, (snapshot.Chunks+7)/8)}
+	return rs, rs.save()
+}
+
+// loadResumeState loads the restoration progress persisted in dir. It returns nil if there is
+// none.
+func loadResumeState(dir string) (*resumeState, error) {
+	ls, err := LoadLocalSnapshot(filepath.Join(dir, resumeSnapshotFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	applied, err := os.ReadFile(filepath.Join(dir, resumeAppliedFile))
+	if err != nil {
+		return nil, err
+	}
+	if len(applied) != int(ls.Chunks+7)/8 {
+		return nil, fmt.Errorf("applied chunks bitmap has %d bytes, expected %d", len(applied), (ls.Chunks+7)/8)
+	}
+	return &resumeState{
+		dir: dir,
+		snapshot: &snapshot{
+			Height:   ls.Height,
+			Format:   ls.Format,
+			Chunks:   ls.Chunks,
+			Hash:     ls.Hash,
+			Metadata: ls.Metadata,
+		},
+		applied: applied,
+	}, nil
+}
+
+// clearResumeState removes the restoration progress persisted in dir, along with the chunks.
+func clearResumeState(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clean up state sync resume dir %v: %w", dir, err)
+	}
+	return nil
+}
+
+// chunksDir returns the directory where the chunks of the snapshot are stored.
+func (rs *resumeState) chunksDir() string {
+	return filepath.Join(rs.dir, resumeChunksDir)
+}
+
+// isApplied returns whether the app has accepted the chunk with the given index.
+func (rs *resumeState) isApplied(index uint32) bool {
+	return rs.applied[index/8]&(1<<(index%8)) != 0
+}
+
+// setApplied records whether the app has accepted the chunk with the given index.
+func (rs *resumeState) setApplied(index uint32, applied bool) error {
+	if applied {
+		rs.applied[index/8] |= 1 << (index % 8)
+	} else {
+		rs.applied[index/8] &^= 1 << (index % 8)
+	}
+	return rs.save()
+}
+
+// resetApplied records that the app has not accepted any chunk.
+func (rs *resumeState) resetApplied() error {
+	clear(rs.applied)
+	return rs.save()
+}
+
+func (rs *resumeState) save() error {
+	if err := tempfile.WriteFileAtomic(filepath.Join(rs.dir, resumeAppliedFile), rs.applied, 0o600); err != nil {
+		return fmt.Errorf("persisting applied chunks: %w", err)
+	}
+	return nil
+}
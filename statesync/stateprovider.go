@@ -1,6 +1,7 @@
 package statesync
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -8,10 +9,12 @@ import (
 
 	dbm "github.com/cometbft/cometbft-db"
 	cmtstate "github.com/cometbft/cometbft/api/cometbft/state/v2"
+	"github.com/cometbft/cometbft/v2/config"
 	"github.com/cometbft/cometbft/v2/libs/log"
 	cmtsync "github.com/cometbft/cometbft/v2/libs/sync"
 	"github.com/cometbft/cometbft/v2/light"
 	lightprovider "github.com/cometbft/cometbft/v2/light/provider"
+	lightgrpc "github.com/cometbft/cometbft/v2/light/provider/grpc"
 	lighthttp "github.com/cometbft/cometbft/v2/light/provider/http"
 	lightrpc "github.com/cometbft/cometbft/v2/light/rpc"
 	lightdb "github.com/cometbft/cometbft/v2/light/store/db"
@@ -84,6 +87,65 @@ func NewLightClientStateProviderWithDBKeyVersion(ctx context.Context,
 	}, nil
 }
 
+// NewStateProviderFromConfig creates the light client StateProvider selected by the state_provider
+// option of cfg, using either its RPC or gRPC servers and its trust options.
+func NewStateProviderFromConfig(ctx context.Context,
+	cfg *config.StateSyncConfig,
+	chainID string,
+	version cmtstate.Version,
+	initialHeight int64,
+	logger log.Logger,
+	dbKeyLayoutVersion string,
+) (StateProvider, error) {
+	trustOptions := light.TrustOptions{
+		Period: cfg.TrustPeriod,
+		Height: cfg.TrustHeight,
+		Hash:   cfg.TrustHashBytes(),
+	}
+	if cfg.StateProvider == config.StateProviderGRPC {
+		return NewGRPCLightClientStateProvider(ctx, chainID, version, initialHeight,
+			cfg.GRPCServers, trustOptions, logger, dbKeyLayoutVersion)
+	}
+	return NewLightClientStateProviderWithDBKeyVersion(ctx, chainID, version, initialHeight,
+		cfg.RPCServers, trustOptions, logger, dbKeyLayoutVersion)
+}
+
+// NewGRPCLightClientStateProvider creates a new StateProvider using a light client and the light
+// block service of the gRPC servers at the given addresses (host:port).
+func NewGRPCLightClientStateProvider(ctx context.Context,
+	chainID string,
+	version cmtstate.Version,
+	initialHeight int64,
+	servers []string,
+	trustOptions light.TrustOptions,
+	logger log.Logger,
+	dbKeyLayoutVersion string,
+) (StateProvider, error) {
+	if len(servers) < 2 {
+		return nil, fmt.Errorf("at least 2 gRPC servers are required, got %v", len(servers))
+	}
+
+	providers := make([]lightprovider.Provider, 0, len(servers))
+	for _, server := range servers {
+		provider, err := lightgrpc.New(ctx, chainID, server)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up gRPC client: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	lc, err := light.NewClient(ctx, chainID, trustOptions, providers[0], providers[1:],
+		lightdb.NewWithDBVersion(dbm.NewMemDB(), "", dbKeyLayoutVersion), light.Logger(logger), light.MaxRetryAttempts(5))
+	if err != nil {
+		return nil, err
+	}
+	return &lightClientStateProvider{
+		lc:            lc,
+		version:       version,
+		initialHeight: initialHeight,
+	}, nil
+}
+
 // NewLightClientStateProvider creates a new StateProvider using a light client and RPC clients.
 // DB Key layout will default to v1.
 func NewLightClientStateProvider(
@@ -184,25 +246,92 @@ func (s *lightClientStateProvider) State(ctx context.Context, height uint64) (sm
 	state.NextValidators = nextLightBlock.ValidatorSet
 	state.LastHeightValidatorsChanged = nextLightBlock.Height
 
-	// We'll also need to fetch consensus params via RPC, using light client verification.
+	params, err := s.consensusParams(ctx, currentLightBlock)
+	if err != nil {
+		return sm.State{}, fmt.Errorf("unable to fetch consensus parameters for height %v: %w",
+			currentLightBlock.Height, err)
+	}
+	state.ConsensusParams = params
+	state.LastHeightConsensusParamsChanged = currentLightBlock.Height
+
+	return state, nil
+}
+
+// LightBlock implements BackfillProvider, fetching the light block at the given height from the
+// primary without verification.
+func (s *lightClientStateProvider) LightBlock(ctx context.Context, height int64) (*types.LightBlock, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.lc.Primary().LightBlock(ctx, height)
+}
+
+// Block implements BackfillProvider, fetching the block at the given height from the primary
+// without verification.
+func (s *lightClientStateProvider) Block(ctx context.Context, height int64) (*types.Block, error) {
+	s.Lock()
+	defer s.Unlock()
+	if p, ok := s.lc.Primary().(blockProvider); ok {
+		return p.Block(ctx, height)
+	}
+
 	primaryURL, ok := s.providers[s.lc.Primary()]
 	if !ok || primaryURL == "" {
-		return sm.State{}, errors.New("could not find address for primary light client provider")
+		return nil, errors.New("could not find address for primary light client provider")
 	}
 	primaryRPC, err := rpcClient(primaryURL)
 	if err != nil {
-		return sm.State{}, fmt.Errorf("unable to create RPC client: %w", err)
+		return nil, fmt.Errorf("unable to create RPC client: %w", err)
 	}
-	rpcclient := lightrpc.NewClient(primaryRPC, s.lc)
-	result, err := rpcclient.ConsensusParams(ctx, &currentLightBlock.Height)
+	result, err := primaryRPC.Block(ctx, &height)
 	if err != nil {
-		return sm.State{}, fmt.Errorf("unable to fetch consensus parameters for height %v: %w",
-			nextLightBlock.Height, err)
+		return nil, err
 	}
-	state.ConsensusParams = result.ConsensusParams
-	state.LastHeightConsensusParamsChanged = currentLightBlock.Height
+	return result.Block, nil
+}
 
-	return state, nil
+// blockProvider is implemented by light block providers which also serve full blocks, such as
+// the gRPC provider.
+type blockProvider interface {
+	Block(ctx context.Context, height int64) (*types.Block, error)
+}
+
+// consensusParamsProvider is implemented by light block providers which also serve consensus
+// parameters, such as the gRPC provider.
+type consensusParamsProvider interface {
+	ConsensusParams(ctx context.Context, height int64) (*types.ConsensusParams, error)
+}
+
+// consensusParams fetches the consensus parameters at the height of the verified light block lb
+// from the primary provider, checking them against its consensus hash. The caller must hold the
+// mutex lock.
+func (s *lightClientStateProvider) consensusParams(ctx context.Context, lb *types.LightBlock) (types.ConsensusParams, error) {
+	if p, ok := s.lc.Primary().(consensusParamsProvider); ok {
+		params, err := p.ConsensusParams(ctx, lb.Height)
+		if err != nil {
+			return types.ConsensusParams{}, err
+		}
+		if !bytes.Equal(params.Hash(), lb.ConsensusHash) {
+			return types.ConsensusParams{}, fmt.Errorf("consensus params hash %X does not match trusted hash %X",
+				params.Hash(), lb.ConsensusHash)
+		}
+		return *params, nil
+	}
+
+	// Otherwise, fetch them via RPC, using light client verification.
+	primaryURL, ok := s.providers[s.lc.Primary()]
+	if !ok || primaryURL == "" {
+		return types.ConsensusParams{}, errors.New("could not find address for primary light client provider")
+	}
+	primaryRPC, err := rpcClient(primaryURL)
+	if err != nil {
+		return types.ConsensusParams{}, fmt.Errorf("unable to create RPC client: %w", err)
+	}
+	rpcclient := lightrpc.NewClient(primaryRPC, s.lc)
+	result, err := rpcclient.ConsensusParams(ctx, &lb.Height)
+	if err != nil {
+		return types.ConsensusParams{}, err
+	}
+	return result.ConsensusParams, nil
 }
 
 // rpcClient sets up a new RPC client.
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"time"
 
 	ssproto "github.com/cometbft/cometbft/api/cometbft/statesync/v1"
@@ -55,6 +56,13 @@ type syncer struct {
 	tempDir       string
 	chunkFetchers int32
 	retryTimeout  time.Duration
+	// if set, the snapshot being restored and its chunks are persisted in this directory, so
+	// that an interrupted restoration can be resumed.
+	resumeDir string
+	resume    *resumeState
+	// if set, the chunks are loaded with it rather than fetched from peers, including the ones
+	// the app asks to refetch.
+	loadChunk func(index uint32) (*chunk, error)
 
 	mtx    cmtsync.RWMutex
 	chunks *chunkQueue
@@ -78,6 +86,7 @@ func newSyncer(
 		tempDir:       tempDir,
 		chunkFetchers: cfg.ChunkFetchers,
 		retryTimeout:  cfg.ChunkRequestTimeout,
+		resumeDir:     cfg.ResumeDir(),
 	}
 }
 
@@ -103,6 +112,22 @@ func (s *syncer) AddChunk(chunk *chunk) (bool, error) {
 	return added, nil
 }
 
+// MissingChunk records that the sender of chunk, received without its contents, does not have
+// it, so that it is requested from another peer.
+func (s *syncer) MissingChunk(chunk *chunk) error {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if s.chunks == nil {
+		return errors.New("no state sync in progress")
+	}
+	if err := s.chunks.MarkMissing(chunk); err != nil {
+		return err
+	}
+	s.logger.Debug("Peer does not have chunk", "height", chunk.Height, "format", chunk.Format,
+		"chunk", chunk.Index, "peer", chunk.Sender)
+	return nil
+}
+
 // AddSnapshot adds a snapshot to the snapshot pool. It returns true if a new, previously unseen
 // snapshot was accepted and added.
 func (s *syncer) AddSnapshot(peer p2p.Peer, snapshot *snapshot) (bool, error) {
@@ -156,6 +181,10 @@ func (s *syncer) SyncAny(discoveryTime, maxDiscoveryTime time.Duration, retryHoo
 	)
 	for {
 		// If not nil, we're going to retry restoration of the same snapshot.
+		if snapshot == nil {
+			// Resume an interrupted restoration first, if any.
+			snapshot, chunks = s.resumeSnapshot()
+		}
 		if snapshot == nil {
 			snapshot = s.snapshots.Best()
 			chunks = nil
@@ -170,7 +199,7 @@ func (s *syncer) SyncAny(discoveryTime, maxDiscoveryTime time.Duration, retryHoo
 			continue
 		}
 		if chunks == nil {
-			chunks, err = newChunkQueue(snapshot, s.tempDir)
+			chunks, err = s.chunkQueueFor(snapshot)
 			if err != nil {
 				return sm.State{}, nil, fmt.Errorf("failed to create chunk queue: %w", err)
 			}
@@ -180,13 +209,20 @@ func (s *syncer) SyncAny(discoveryTime, maxDiscoveryTime time.Duration, retryHoo
 		newState, commit, err := s.Sync(snapshot, chunks)
 		switch {
 		case err == nil:
+			s.discardResumeState()
 			return newState, commit, nil
 
 		case errors.Is(err, errAbort):
+			s.discardResumeState()
 			return sm.State{}, nil, err
 
 		case errors.Is(err, errRetrySnapshot):
 			chunks.RetryAll()
+			if s.resume != nil {
+				if err := s.resume.resetApplied(); err != nil {
+					return sm.State{}, nil, err
+				}
+			}
 			s.logger.Info("Retrying snapshot", "height", snapshot.Height, "format", snapshot.Format,
 				"hash", log.NewLazySprintf("%X", snapshot.Hash))
 			continue
@@ -218,6 +254,9 @@ func (s *syncer) SyncAny(discoveryTime, maxDiscoveryTime time.Duration, retryHoo
 			s.snapshots.Reject(snapshot)
 
 		default:
+			if errors.Is(err, errVerifyFailed) {
+				s.discardResumeState()
+			}
 			return sm.State{}, nil, fmt.Errorf("snapshot restoration failed: %w", err)
 		}
 
@@ -226,11 +265,71 @@ func (s *syncer) SyncAny(discoveryTime, maxDiscoveryTime time.Duration, retryHoo
 		if err != nil {
 			s.logger.Error("Failed to clean up chunk queue", "err", err)
 		}
+		s.discardResumeState()
 		snapshot = nil
 		chunks = nil
 	}
 }
 
+// resumeSnapshot returns the snapshot whose restoration was interrupted, if any, along with a
+// chunk queue holding the chunks already fetched. The chunks accepted by the app are skipped.
+func (s *syncer) resumeSnapshot() (*snapshot, *chunkQueue) {
+	if s.resumeDir == "" {
+		return nil, nil
+	}
+	rs, err := loadResumeState(s.resumeDir)
+	if err != nil {
+		s.logger.Error("Failed to load interrupted snapshot restoration, discarding it", "err", err)
+		s.discardResumeState()
+		return nil, nil
+	}
+	if rs == nil {
+		return nil, nil
+	}
+	chunks, err := newPersistentChunkQueue(rs.snapshot, rs.chunksDir())
+	if err != nil {
+		s.logger.Error("Failed to load chunks of interrupted snapshot restoration, discarding it", "err", err)
+		s.discardResumeState()
+		return nil, nil
+	}
+	applied := 0
+	for i := uint32(0); i < rs.snapshot.Chunks; i++ {
+		if rs.isApplied(i) {
+			chunks.Skip(i)
+			applied++
+		}
+	}
+	s.resume = rs
+	s.logger.Info("Resuming snapshot restoration", "height", rs.snapshot.Height, "format", rs.snapshot.Format,
+		"hash", log.NewLazySprintf("%X", rs.snapshot.Hash), "applied", applied, "total", rs.snapshot.Chunks)
+	return rs.snapshot, chunks
+}
+
+// chunkQueueFor creates the chunk queue to restore snapshot with, persisting the restoration
+// progress if resuming is enabled.
+func (s *syncer) chunkQueueFor(snapshot *snapshot) (*chunkQueue, error) {
+	if s.resumeDir == "" {
+		return newChunkQueue(snapshot, s.tempDir)
+	}
+	rs, err := newResumeState(s.resumeDir, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	s.resume = rs
+	return newPersistentChunkQueue(snapshot, rs.chunksDir())
+}
+
+// discardResumeState removes the persisted restoration progress, if any.
+func (s *syncer) discardResumeState() {
+	if s.resumeDir == "" {
+		return
+	}
+	s.resume = nil
+	if err := clearResumeState(s.resumeDir); err != nil {
+		s.logger.Error("Failed to clean up interrupted snapshot restoration", "err", err)
+	}
+}
+
 // Sync executes a sync for a specific snapshot, returning the latest state and block commit which
 // the caller must use to bootstrap the node.
 func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.Commit, error) {
@@ -247,6 +346,13 @@ func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.
 		s.mtx.Unlock()
 	}()
 
+	return s.restore(snapshot, chunks, s.chunkFetchers)
+}
+
+// restore restores the snapshot into the app from chunks, running the given
+// number of chunk fetchers to fetch the chunks from peers. It returns the
+// latest state and block commit, like Sync.
+func (s *syncer) restore(snapshot *snapshot, chunks *chunkQueue, fetchers int32) (sm.State, *types.Commit, error) {
 	hctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
 	defer cancel()
 
@@ -269,7 +375,7 @@ func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.
 	// Spawn chunk fetchers. They will terminate when the chunk queue is closed or context canceled.
 	fetchCtx, cancel := context.WithCancel(context.TODO())
 	defer cancel()
-	for i := int32(0); i < s.chunkFetchers; i++ {
+	for i := int32(0); i < fetchers; i++ {
 		go s.fetchChunks(fetchCtx, snapshot, chunks)
 	}
 
@@ -376,6 +482,16 @@ func (s *syncer) applyChunks(chunks *chunkQueue) error {
 			if err != nil {
 				return fmt.Errorf("failed to discard chunk %v: %w", index, err)
 			}
+			if s.resume != nil {
+				if err := s.resume.setApplied(index, false); err != nil {
+					return err
+				}
+			}
+			if s.loadChunk != nil {
+				if err := s.reloadChunk(chunks, index); err != nil {
+					return err
+				}
+			}
 		}
 
 		// Reject any senders as requested by the app
@@ -391,6 +507,11 @@ func (s *syncer) applyChunks(chunks *chunkQueue) error {
 
 		switch resp.Result {
 		case abci.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT:
+			if s.resume != nil {
+				if err := s.resume.setApplied(chunk.Index, true); err != nil {
+					return err
+				}
+			}
 		case abci.APPLY_SNAPSHOT_CHUNK_RESULT_ABORT:
 			return errAbort
 		case abci.APPLY_SNAPSHOT_CHUNK_RESULT_RETRY:
@@ -405,6 +526,20 @@ func (s *syncer) applyChunks(chunks *chunkQueue) error {
 	}
 }
 
+// reloadChunk loads the discarded chunk at index back into the queue, with the chunk loader.
+func (s *syncer) reloadChunk(chunks *chunkQueue, index uint32) error {
+	chunk, err := s.loadChunk(index)
+	if err != nil {
+		return fmt.Errorf("failed to reload chunk %v: %w", index, err)
+	}
+	if _, err := chunks.Add(chunk); err != nil {
+		return fmt.Errorf("failed to add chunk %v: %w", index, err)
+	}
+	s.logger.Info("Reloaded snapshot chunk", "height", chunk.Height, "format", chunk.Format,
+		"chunk", index, "total", chunks.Size())
+	return nil
+}
+
 // fetchChunks requests chunks from peers, receiving allocations from the chunk queue. Chunks
 // will be received from the reactor via syncer.AddChunks() to chunkQueue.Add().
 func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *chunkQueue) {
@@ -436,13 +571,19 @@ func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *ch
 		s.logger.Info("Fetching snapshot chunk", "height", snapshot.Height,
 			"format", snapshot.Format, "chunk", index, "total", chunks.Size())
 
-		s.requestChunk(snapshot, index)
+		missing := chunks.WaitForMissing(index)
+		s.requestChunk(snapshot, chunks, index)
 
 		select {
 		case <-chunks.WaitFor(index):
 			next = true
 
+		case <-missing:
+			// The peer does not have the chunk: request it from another one.
+			next = false
+
 		case <-time.After(s.retryTimeout):
+			chunks.ClearMissing(index)
 			next = false
 
 		case <-ctx.Done():
@@ -451,14 +592,27 @@ func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *ch
 	}
 }
 
-// requestChunk requests a chunk from a peer.
-func (s *syncer) requestChunk(snapshot *snapshot, chunk uint32) {
-	peer := s.snapshots.GetPeer(snapshot)
-	if peer == nil {
+// requestChunk requests a chunk from a peer, other than the ones which answered they do not have
+// it. If all of them did, the chunk is requested again once the retry timeout expires.
+func (s *syncer) requestChunk(snapshot *snapshot, chunks *chunkQueue, chunk uint32) {
+	peers := s.snapshots.GetPeers(snapshot)
+	if len(peers) == 0 {
 		s.logger.Error("No valid peers found for snapshot", "height", snapshot.Height,
 			"format", snapshot.Format, "hash", log.NewLazySprintf("%X", snapshot.Hash))
 		return
 	}
+	candidates := peers[:0]
+	for _, peer := range peers {
+		if !chunks.MissingFrom(chunk, peer.ID()) {
+			candidates = append(candidates, peer)
+		}
+	}
+	if len(candidates) == 0 {
+		s.logger.Info("No peer has snapshot chunk available, retrying later", "height", snapshot.Height,
+			"format", snapshot.Format, "chunk", chunk)
+		return
+	}
+	peer := candidates[rand.Intn(len(candidates))] //nolint:gosec // G404: Use of weak random number generator
 	s.logger.Debug("Requesting snapshot chunk", "height", snapshot.Height,
 		"format", snapshot.Format, "chunk", chunk, "peer", peer.ID())
 	_ = peer.Send(p2p.Envelope{
@@ -2,6 +2,8 @@ package statesync
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -694,3 +696,98 @@ func toABCI(s *snapshot) *abci.Snapshot {
 		Metadata: s.Metadata,
 	}
 }
+
+func TestSyncer_SyncAny_Resume(t *testing.T) {
+	state := sm.State{
+		ChainID: "chain",
+		Version: cmtstate.Version{
+			Consensus: cmtversion.Consensus{App: testAppVersion},
+		},
+		LastBlockHeight: 1,
+		AppHash:         []byte("app_hash"),
+	}
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+	s := &snapshot{Height: 1, Format: 1, Chunks: 3, Hash: []byte{1, 2, 3}}
+
+	// A previous restoration of the snapshot fetched all the chunks, and the
+	// app accepted the first one before the node stopped.
+	dir := filepath.Join(t.TempDir(), "statesync")
+	rs, err := newResumeState(dir, s)
+	require.NoError(t, err)
+	require.NoError(t, rs.setApplied(0, true))
+	queue, err := newPersistentChunkQueue(s, rs.chunksDir())
+	require.NoError(t, err)
+	for i := uint32(0); i < s.Chunks; i++ {
+		_, err := queue.Add(&chunk{Height: 1, Format: 1, Index: i, Chunk: []byte{1, 1, byte(i)}})
+		require.NoError(t, err)
+	}
+	require.NoError(t, queue.Close())
+
+	stateProvider := &mocks.StateProvider{}
+	stateProvider.On("AppHash", mock.Anything, uint64(1)).Return(state.AppHash, nil)
+	stateProvider.On("Commit", mock.Anything, uint64(1)).Return(commit, nil)
+	stateProvider.On("State", mock.Anything, uint64(1)).Return(state, nil)
+	connSnapshot := &proxymocks.AppConnSnapshot{}
+	connSnapshot.On("OfferSnapshot", mock.Anything, &abci.OfferSnapshotRequest{
+		Snapshot: &abci.Snapshot{Height: 1, Format: 1, Chunks: 3, Hash: []byte{1, 2, 3}},
+		AppHash:  []byte("app_hash"),
+	}).Once().Return(&abci.OfferSnapshotResponse{Result: abci.OFFER_SNAPSHOT_RESULT_ACCEPT}, nil)
+	// Only the chunks not yet accepted are applied.
+	for i := uint32(1); i < s.Chunks; i++ {
+		connSnapshot.On("ApplySnapshotChunk", mock.Anything, &abci.ApplySnapshotChunkRequest{
+			Index: i, Chunk: []byte{1, 1, byte(i)},
+		}).Once().Return(&abci.ApplySnapshotChunkResponse{Result: abci.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT}, nil)
+	}
+	connQuery := &proxymocks.AppConnQuery{}
+	connQuery.On("Info", mock.Anything, proxy.InfoRequest).Return(&abci.InfoResponse{
+		AppVersion:       testAppVersion,
+		LastBlockHeight:  1,
+		LastBlockAppHash: []byte("app_hash"),
+	}, nil)
+
+	cfg := config.DefaultStateSyncConfig()
+	cfg.ResumePath = dir
+	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+
+	newState, lastCommit, err := syncer.SyncAny(0, maxDiscoveryTime, func() {})
+	require.NoError(t, err)
+	assert.Equal(t, state, newState)
+	assert.Equal(t, commit, lastCommit)
+	connSnapshot.AssertExpectations(t)
+
+	// The progress is discarded once the snapshot is restored.
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestResumeState(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "statesync")
+	s := &snapshot{Height: 5, Format: 2, Chunks: 10, Hash: []byte{1}, Metadata: []byte{2}}
+
+	rs, err := loadResumeState(dir)
+	require.NoError(t, err)
+	require.Nil(t, rs)
+
+	rs, err = newResumeState(dir, s)
+	require.NoError(t, err)
+	require.NoError(t, rs.setApplied(1, true))
+	require.NoError(t, rs.setApplied(9, true))
+	require.NoError(t, rs.setApplied(1, false))
+
+	loaded, err := loadResumeState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, s, loaded.snapshot)
+	for i := uint32(0); i < s.Chunks; i++ {
+		assert.Equal(t, i == 9, loaded.isApplied(i), "chunk %d", i)
+	}
+
+	require.NoError(t, loaded.resetApplied())
+	loaded, err = loadResumeState(dir)
+	require.NoError(t, err)
+	assert.False(t, loaded.isApplied(9))
+
+	require.NoError(t, clearResumeState(dir))
+	rs, err = loadResumeState(dir)
+	require.NoError(t, err)
+	assert.Nil(t, rs)
+}
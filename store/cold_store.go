@@ -0,0 +1,141 @@
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+// coldStoreBatchHeights is the number of heights moved to the cold database in
+// each batch.
+const coldStoreBatchHeights = 1000
+
+var (
+	coldHeightKey = []byte("coldStoreHeight")
+
+	ErrNoColdStore = errors.New("the block store has no cold database")
+)
+
+// WithColdStore sets the secondary, cold, database to which the parts and
+// commits of old blocks are moved by MoveBlocksToColdStore. Both databases are
+// read transparently. A nil db disables the cold storage of blocks.
+func WithColdStore(db dbm.DB) BlockStoreOption {
+	return func(bs *BlockStore) {
+		if db == nil {
+			return
+		}
+		bz, err := bs.db.Get(coldHeightKey)
+		if err != nil {
+			panic(err)
+		}
+		bs.coldDB = db
+		if len(bz) == 8 {
+			bs.coldHeight = int64(binary.BigEndian.Uint64(bz))
+		}
+	}
+}
+
+// ColdHeight returns the height below which the blocks were moved to the cold
+// database, or 0 if none were.
+func (bs *BlockStore) ColdHeight() int64 {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.coldHeight
+}
+
+// MoveBlocksToColdStore moves the parts and commits of the blocks below height
+// to the cold database, in batches, and returns the number of moved blocks.
+// The block metas stay in the main database. The latest block is never moved.
+//
+// Each batch is written to the cold database before being deleted from the
+// main one, so the blocks can be loaded at any time. MoveBlocksToColdStore
+// must not run concurrently with PruneBlocks: the Pruner calls both in turn.
+func (bs *BlockStore) MoveBlocksToColdStore(height int64) (int64, error) {
+	if bs.coldDB == nil {
+		return 0, ErrNoColdStore
+	}
+	defer addTimeSample(bs.metrics.BlockStoreAccessDurationSeconds.With("method", "move_blocks_to_cold_store"), time.Now())()
+
+	bs.mtx.RLock()
+	from := max(bs.coldHeight, bs.base)
+	height = min(height, bs.height)
+	bs.mtx.RUnlock()
+
+	var moved int64
+	for start := from; start < height; start += coldStoreBatchHeights {
+		end := min(start+coldStoreBatchHeights, height)
+		n, err := bs.moveBlocksToColdStore(start, end)
+		if err != nil {
+			return moved, err
+		}
+		moved += n
+	}
+	return moved, nil
+}
+
+// moveBlocksToColdStore moves the blocks from start to end (exclusive) to the
+// cold database in a single batch.
+func (bs *BlockStore) moveBlocksToColdStore(start, end int64) (int64, error) {
+	coldBatch := bs.coldDB.NewBatch()
+	defer coldBatch.Close()
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+
+	move := func(key []byte) error {
+		bz, err := bs.db.Get(key)
+		if err != nil || len(bz) == 0 {
+			return err
+		}
+		if err := coldBatch.Set(key, bz); err != nil {
+			return err
+		}
+		return batch.Delete(key)
+	}
+
+	var moved int64
+	for h := start; h < end; h++ {
+		meta := bs.LoadBlockMeta(h)
+		if meta == nil {
+			continue
+		}
+		for p := 0; p < int(meta.BlockID.PartSetHeader.Total); p++ {
+			if err := move(bs.dbKeyLayout.CalcBlockPartKey(h, p)); err != nil {
+				return moved, ErrDBOpt{Err: err}
+			}
+		}
+		if err := move(bs.dbKeyLayout.CalcBlockCommitKey(h)); err != nil {
+			return moved, ErrDBOpt{Err: err}
+		}
+		moved++
+	}
+	if err := coldBatch.WriteSync(); err != nil {
+		return 0, ErrDBOpt{Err: err}
+	}
+
+	if err := batch.Set(coldHeightKey, encodeColdHeight(end)); err != nil {
+		return 0, ErrDBOpt{Err: err}
+	}
+	bs.mtx.Lock()
+	defer bs.mtx.Unlock()
+	if err := batch.WriteSync(); err != nil {
+		return 0, ErrDBOpt{Err: err}
+	}
+	bs.coldHeight = end
+	return moved, nil
+}
+
+// get returns the value of key in the main database or, if it is not found
+// there, in the cold database.
+func (bs *BlockStore) get(key []byte) ([]byte, error) {
+	bz, err := bs.db.Get(key)
+	if err != nil || len(bz) != 0 || bs.coldDB == nil {
+		return bz, err
+	}
+	return bs.coldDB.Get(key)
+}
+
+func encodeColdHeight(height int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(height))
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"errors"
+	"time"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/v2/internal/compression"
+)
+
+// WithCompression sets the algorithm, "none" or "zstd", the block parts are
+// compressed with when saved. Block parts saved with another algorithm remain
+// readable, see RecompressBlockParts.
+func WithCompression(algorithm string) BlockStoreOption {
+	return func(bs *BlockStore) {
+		if err := compression.Validate(algorithm); err != nil {
+			panic(err)
+		}
+		bs.compression = algorithm
+	}
+}
+
+// RecompressBlockParts rewrites the stored block parts which are not
+// compressed with the algorithm of the store, in batches of at most batchSize
+// parts, including the parts moved to the cold database. Each batch is written
+// atomically and the parts already rewritten are skipped, so an interrupted
+// run is resumed by calling it again. It returns the number of rewritten
+// parts.
+//
+// RecompressBlockParts must not run concurrently with PruneBlocks or
+// MoveBlocksToColdStore.
+func (bs *BlockStore) RecompressBlockParts(batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		return 0, errors.New("batch size must be positive")
+	}
+	defer addTimeSample(bs.metrics.BlockStoreAccessDurationSeconds.With("method", "recompress_block_parts"), time.Now())()
+
+	bs.mtx.RLock()
+	base, height, coldHeight := bs.base, bs.height, bs.coldHeight
+	bs.mtx.RUnlock()
+
+	var (
+		rewritten int64
+		pending   int
+		batches   = make(map[dbm.DB]dbm.Batch)
+	)
+	flush := func() error {
+		for db, batch := range batches {
+			err := batch.WriteSync()
+			_ = batch.Close()
+			delete(batches, db)
+			if err != nil {
+				return ErrDBOpt{Err: err}
+			}
+		}
+		rewritten += int64(pending)
+		pending = 0
+		return nil
+	}
+	defer func() {
+		for _, batch := range batches {
+			_ = batch.Close()
+		}
+	}()
+
+	for h := base; h > 0 && h <= height; h++ {
+		meta := bs.LoadBlockMeta(h)
+		if meta == nil {
+			continue
+		}
+		db := bs.db
+		if h < coldHeight {
+			db = bs.coldDB
+		}
+		for p := 0; p < int(meta.BlockID.PartSetHeader.Total); p++ {
+			key := bs.dbKeyLayout.CalcBlockPartKey(h, p)
+			bz, err := db.Get(key)
+			if err != nil {
+				return rewritten, ErrDBOpt{Err: err}
+			}
+			if len(bz) == 0 {
+				continue
+			}
+			bz, changed, err := compression.Recode(bs.compression, bz)
+			if err != nil {
+				return rewritten, err
+			}
+			if !changed {
+				continue
+			}
+			batch, ok := batches[db]
+			if !ok {
+				batch = db.NewBatch()
+				batches[db] = batch
+			}
+			if err := batch.Set(key, bz); err != nil {
+				return rewritten, ErrDBOpt{Err: err}
+			}
+			pending++
+			if pending == batchSize {
+				if err := flush(); err != nil {
+					return rewritten, err
+				}
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return rewritten, err
+	}
+	return rewritten, nil
+}
@@ -1,10 +1,16 @@
 package store
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"strconv"
 
 	"github.com/google/orderedcode"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/v2/internal/keymigration"
 )
 
 type BlockKeyLayout interface {
@@ -211,3 +217,88 @@ func (*v2Layout) CalcSeenCommitKey(height int64) []byte {
 }
 
 var _ BlockKeyLayout = (*v2Layout)(nil)
+
+// KeyLayoutMigratingToV2 is the key layout version recorded in the database
+// while its keys are being migrated from the v1 to the v2 layout. A store
+// cannot be opened until the migration is completed.
+const KeyLayoutMigratingToV2 = "v1-to-v2"
+
+// MigrateKeyLayoutToV2 rewrites the keys of the blockstore database db from
+// the v1 to the v2 layout, in batches of batchSize keys, then records the v2
+// layout version so that NewBlockStore uses it. An interrupted migration is
+// resumed by calling it again. It returns the number of migrated keys.
+func MigrateKeyLayoutToV2(db dbm.DB, batchSize int) (int64, error) {
+	version, err := db.Get([]byte("version"))
+	if err != nil {
+		return 0, err
+	}
+	switch string(version) {
+	case "v2":
+		return 0, nil
+	case "", "v1", KeyLayoutMigratingToV2:
+	default:
+		return 0, fmt.Errorf("unknown key layout version %q", version)
+	}
+	if err := db.SetSync([]byte("version"), []byte(KeyLayoutMigratingToV2)); err != nil {
+		return 0, err
+	}
+
+	v2 := &v2Layout{}
+	renames := []struct {
+		prefix string
+		rename keymigration.RenameFunc
+	}{
+		{"H:", heightRename(v2.CalcBlockMetaKey)},
+		{"C:", heightRename(v2.CalcBlockCommitKey)},
+		{"SC:", heightRename(v2.CalcSeenCommitKey)},
+		{"EC:", heightRename(v2.CalcExtCommitKey)},
+		{"P:", func(key []byte) ([]byte, error) {
+			heightStr, indexStr, ok := bytes.Cut(key[len("P:"):], []byte(":"))
+			if !ok {
+				return nil, errors.New("invalid block part key")
+			}
+			height, err := strconv.ParseInt(string(heightStr), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			index, err := strconv.Atoi(string(indexStr))
+			if err != nil {
+				return nil, err
+			}
+			return v2.CalcBlockPartKey(height, index), nil
+		}},
+		{"BH:", func(key []byte) ([]byte, error) {
+			hash, err := hex.DecodeString(string(key[len("BH:"):]))
+			if err != nil {
+				return nil, err
+			}
+			return v2.CalcBlockHashKey(hash), nil
+		}},
+	}
+
+	var migrated int64
+	for _, r := range renames {
+		n, err := keymigration.Migrate(db, []byte(r.prefix), r.rename, batchSize)
+		migrated += n
+		if err != nil {
+			return migrated, err
+		}
+	}
+	return migrated, db.SetSync([]byte("version"), []byte("v2"))
+}
+
+// heightRename returns a keymigration.RenameFunc for the v1 keys of the form
+// "<prefix>:<height>", using calcKey to compute the new key.
+func heightRename(calcKey func(height int64) []byte) keymigration.RenameFunc {
+	return func(key []byte) ([]byte, error) {
+		_, heightStr, ok := bytes.Cut(key, []byte(":"))
+		if !ok {
+			return nil, errors.New("invalid key")
+		}
+		height, err := strconv.ParseInt(string(heightStr), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return calcKey(height), nil
+	}
+}
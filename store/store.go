@@ -12,6 +12,7 @@ import (
 	dbm "github.com/cometbft/cometbft-db"
 	cmtstore "github.com/cometbft/cometbft/api/cometbft/store/v1"
 	cmtproto "github.com/cometbft/cometbft/api/cometbft/types/v2"
+	"github.com/cometbft/cometbft/v2/internal/compression"
 	"github.com/cometbft/cometbft/v2/internal/evidence"
 	"github.com/cometbft/cometbft/v2/libs/metrics"
 	cmtsync "github.com/cometbft/cometbft/v2/libs/sync"
@@ -62,6 +63,14 @@ type BlockStore struct {
 
 	dbKeyLayout BlockKeyLayout
 
+	// coldDB, if not nil, holds the parts and commits of the blocks below
+	// coldHeight. coldHeight is guarded by mtx.
+	coldDB     dbm.DB
+	coldHeight int64
+
+	// compression is the algorithm the block parts are compressed with.
+	compression string
+
 	blocksDeleted      int64
 	compact            bool
 	compactionInterval int64
@@ -111,6 +120,8 @@ func setDBLayout(bStore *BlockStore, dbKeyLayoutVersion string) {
 		dbKeyLayoutVersion = "v1"
 	case "v2":
 		bStore.dbKeyLayout = &v2Layout{}
+	case KeyLayoutMigratingToV2:
+		panic("the migration of the key layout to v2 is not complete, run it again")
 	default:
 		panic("unknown key layout version")
 	}
@@ -291,7 +302,7 @@ func (bs *BlockStore) LoadBlockPart(height int64, index int) *types.Part {
 	}
 	pbpart := new(cmtproto.Part)
 	start := time.Now()
-	bz, err := bs.db.Get(bs.dbKeyLayout.CalcBlockPartKey(height, index))
+	bz, err := bs.get(bs.dbKeyLayout.CalcBlockPartKey(height, index))
 	if err != nil {
 		panic(err)
 	}
@@ -301,6 +312,10 @@ func (bs *BlockStore) LoadBlockPart(height int64, index int) *types.Part {
 	if len(bz) == 0 {
 		return nil
 	}
+	bz, err = compression.Decode(bz)
+	if err != nil {
+		panic(fmt.Errorf("reading block part: %w", err))
+	}
 	err = proto.Unmarshal(bz, pbpart)
 	if err != nil {
 		panic(fmt.Errorf("unmarshal to cmtproto.Part failed: %w", err))
@@ -377,7 +392,7 @@ func (bs *BlockStore) LoadBlockCommit(height int64) *types.Commit {
 	pbc := new(cmtproto.Commit)
 
 	start := time.Now()
-	bz, err := bs.db.Get(bs.dbKeyLayout.CalcBlockCommitKey(height))
+	bz, err := bs.get(bs.dbKeyLayout.CalcBlockCommitKey(height))
 	if err != nil {
 		panic(err)
 	}
@@ -480,7 +495,7 @@ func (bs *BlockStore) PruneBlocks(height int64, state sm.State) (uint64, int64,
 		bs.mtx.RUnlock()
 		return 0, -1, ErrExceedLatestHeight{Height: bs.height}
 	}
-	base := bs.base
+	base, coldHeight := bs.base, bs.coldHeight
 	bs.mtx.RUnlock()
 	if height < base {
 		return 0, -1, ErrExceedBaseHeight{Height: height, Base: base}
@@ -489,6 +504,20 @@ func (bs *BlockStore) PruneBlocks(height int64, state sm.State) (uint64, int64,
 	pruned := uint64(0)
 	batch := bs.db.NewBatch()
 	defer batch.Close()
+	// The blocks below coldHeight are deleted from the cold database as well.
+	var coldBatch dbm.Batch
+	if bs.coldDB != nil {
+		coldBatch = bs.coldDB.NewBatch()
+		defer func() {
+			_ = coldBatch.Close()
+		}()
+	}
+	deleteCold := func(key []byte) error {
+		if coldBatch == nil {
+			return nil
+		}
+		return coldBatch.Delete(key)
+	}
 	flush := func(batch dbm.Batch, base int64) error {
 		// We can't trust batches to be atomic, so update base first to make sure no one
 		// tries to access missing blocks.
@@ -496,7 +525,18 @@ func (bs *BlockStore) PruneBlocks(height int64, state sm.State) (uint64, int64,
 		defer batch.Close()
 		defer bs.mtx.Unlock()
 		bs.base = base
-		return bs.saveStateAndWriteDB(batch, "failed to prune")
+		if err := bs.saveStateAndWriteDB(batch, "failed to prune"); err != nil {
+			return err
+		}
+		if coldBatch == nil {
+			return nil
+		}
+		if err := coldBatch.WriteSync(); err != nil {
+			return err
+		}
+		_ = coldBatch.Close()
+		coldBatch = bs.coldDB.NewBatch()
+		return nil
 	}
 
 	defer addTimeSample(bs.metrics.BlockStoreAccessDurationSeconds.With("method", "prune_blocks"), time.Now())()
@@ -529,6 +569,11 @@ func (bs *BlockStore) PruneBlocks(height int64, state sm.State) (uint64, int64,
 			if err := batch.Delete(bs.dbKeyLayout.CalcBlockCommitKey(h)); err != nil {
 				return 0, -1, ErrDBOpt{Err: err}
 			}
+			if h < coldHeight {
+				if err := deleteCold(bs.dbKeyLayout.CalcBlockCommitKey(h)); err != nil {
+					return 0, -1, ErrDBOpt{Err: err}
+				}
+			}
 			bs.blockCommitCache.Remove(h)
 		}
 		if err := batch.Delete(bs.dbKeyLayout.CalcSeenCommitKey(h)); err != nil {
@@ -539,6 +584,11 @@ func (bs *BlockStore) PruneBlocks(height int64, state sm.State) (uint64, int64,
 			if err := batch.Delete(bs.dbKeyLayout.CalcBlockPartKey(h, p)); err != nil {
 				return 0, -1, ErrDBOpt{Err: err}
 			}
+			if h < coldHeight {
+				if err := deleteCold(bs.dbKeyLayout.CalcBlockPartKey(h, p)); err != nil {
+					return 0, -1, ErrDBOpt{Err: err}
+				}
+			}
 			bs.blockPartCache.Remove(blockPartIndex{h, p})
 		}
 		pruned++
@@ -753,7 +803,7 @@ func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part, b
 		panic(cmterrors.ErrMsgToProto{MessageName: "Part", Err: err})
 	}
 
-	partBytes := mustEncode(pbp)
+	partBytes := compression.Encode(bs.compression, mustEncode(pbp))
 
 	if saveBlockPartsToBatch {
 		err = batch.Set(bs.dbKeyLayout.CalcBlockPartKey(height, index), partBytes)
@@ -801,7 +851,99 @@ func (bs *BlockStore) SaveSeenCommit(height int64, seenCommit *types.Commit) err
 	return nil
 }
 
+// SaveBackfilledBlock persists a block below the base of the store, along with
+// its canonical commit, lowering the base to its height. It is used to
+// backfill the blocks before a state sync snapshot, from the highest to the
+// lowest: the block must be at height base-1, or at any height if the store is
+// empty. The caller must have verified the block and commit.
+func (bs *BlockStore) SaveBackfilledBlock(block *types.Block, blockParts *types.PartSet, commit *types.Commit) error {
+	defer addTimeSample(bs.metrics.BlockStoreAccessDurationSeconds.With("method", "save_backfilled_block"), time.Now())()
+	if block == nil {
+		return errors.New("BlockStore can only save a non-nil block")
+	}
+
+	height := block.Height
+	if base := bs.Base(); base > 0 && height != base-1 {
+		return fmt.Errorf("BlockStore can only backfill contiguous blocks. Wanted %v, got %v", base-1, height)
+	}
+	if !blockParts.IsComplete() {
+		return errors.New("BlockStore can only save complete block part sets")
+	}
+	if height != commit.Height {
+		return fmt.Errorf("BlockStore cannot save commit of a different height (block: %d, commit: %d)", height, commit.Height)
+	}
+
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+
+	saveBlockPartsToBatch := blockParts.Count() <= maxBlockPartsToBatch
+	for i := 0; i < int(blockParts.Total()); i++ {
+		bs.saveBlockPart(height, i, blockParts.GetPart(i), batch, saveBlockPartsToBatch)
+	}
+	if err := bs.saveBackfilledMetaToBatch(types.NewBlockMeta(block, blockParts), commit, batch); err != nil {
+		return err
+	}
+	if block.LastCommit != nil && height > 1 {
+		if err := batch.Set(bs.dbKeyLayout.CalcBlockCommitKey(height-1), mustEncode(block.LastCommit.ToProto())); err != nil {
+			return err
+		}
+	}
+
+	bs.mtx.Lock()
+	defer bs.mtx.Unlock()
+	bs.base = height
+	if bs.height == 0 {
+		bs.height = height
+	}
+	return bs.saveStateAndWriteDB(batch, "failed to save backfilled block")
+}
+
+// SaveBackfilledHeader persists the meta of a block below the base of the
+// store, along with its canonical commit, without the block itself. The base
+// of the store is unchanged, so that the header is not served as part of the
+// stored blocks, but it can be loaded with LoadBlockMeta and LoadBlockCommit,
+// e.g. to verify evidence. Such headers are not pruned. The caller must have
+// verified the header and commit.
+func (bs *BlockStore) SaveBackfilledHeader(blockMeta *types.BlockMeta, commit *types.Commit) error {
+	defer addTimeSample(bs.metrics.BlockStoreAccessDurationSeconds.With("method", "save_backfilled_header"), time.Now())()
+	height := blockMeta.Header.Height
+	if base := bs.Base(); base > 0 && height >= base {
+		return fmt.Errorf("BlockStore can only backfill headers below the base %v, got %v", base, height)
+	}
+	if height != commit.Height {
+		return fmt.Errorf("BlockStore cannot save commit of a different height (header: %d, commit: %d)", height, commit.Height)
+	}
+
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+
+	if err := bs.saveBackfilledMetaToBatch(blockMeta, commit, batch); err != nil {
+		return err
+	}
+	return batch.WriteSync()
+}
+
+func (bs *BlockStore) saveBackfilledMetaToBatch(blockMeta *types.BlockMeta, commit *types.Commit, batch dbm.Batch) error {
+	height := blockMeta.Header.Height
+	pbm := blockMeta.ToProto()
+	if pbm == nil {
+		return errors.New("nil blockmeta")
+	}
+	if err := batch.Set(bs.dbKeyLayout.CalcBlockMetaKey(height), mustEncode(pbm)); err != nil {
+		return err
+	}
+	if err := batch.Set(bs.dbKeyLayout.CalcBlockHashKey(blockMeta.BlockID.Hash), []byte(strconv.FormatInt(height, 10))); err != nil {
+		return err
+	}
+	return batch.Set(bs.dbKeyLayout.CalcBlockCommitKey(height), mustEncode(commit.ToProto()))
+}
+
 func (bs *BlockStore) Close() error {
+	if bs.coldDB != nil {
+		if err := bs.coldDB.Close(); err != nil {
+			return err
+		}
+	}
 	return bs.db.Close()
 }
 
@@ -902,6 +1044,93 @@ func (bs *BlockStore) DeleteLatestBlock() error {
 	return nil
 }
 
+// DeleteBlocksAbove removes all the blocks above height in a single batch,
+// lowering the store height to height. It returns the number of removed blocks.
+// The height must not be below the base, since the store cannot be emptied.
+func (bs *BlockStore) DeleteBlocksAbove(height int64) (int64, error) {
+	defer addTimeSample(bs.metrics.BlockStoreAccessDurationSeconds.With("method", "delete_blocks_above"), time.Now())()
+
+	bs.mtx.RLock()
+	base, latest, coldHeight := bs.base, bs.height, bs.coldHeight
+	bs.mtx.RUnlock()
+
+	if height < base {
+		return 0, ErrExceedBaseHeight{Height: height, Base: base}
+	}
+	if height >= latest {
+		return 0, nil
+	}
+
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+	// The blocks below coldHeight are deleted from the cold database as well.
+	var coldBatch dbm.Batch
+	if height+1 < coldHeight {
+		coldBatch = bs.coldDB.NewBatch()
+		defer coldBatch.Close()
+	}
+
+	for h := height + 1; h <= latest; h++ {
+		// delete what we can, skipping what's already missing, as in
+		// DeleteLatestBlock.
+		if meta := bs.LoadBlockMeta(h); meta != nil {
+			if err := batch.Delete(bs.dbKeyLayout.CalcBlockHashKey(meta.BlockID.Hash)); err != nil {
+				return 0, ErrDBOpt{Err: err}
+			}
+			for p := 0; p < int(meta.BlockID.PartSetHeader.Total); p++ {
+				if err := batch.Delete(bs.dbKeyLayout.CalcBlockPartKey(h, p)); err != nil {
+					return 0, ErrDBOpt{Err: err}
+				}
+				if h < coldHeight {
+					if err := coldBatch.Delete(bs.dbKeyLayout.CalcBlockPartKey(h, p)); err != nil {
+						return 0, ErrDBOpt{Err: err}
+					}
+				}
+			}
+		}
+		if h < coldHeight {
+			if err := coldBatch.Delete(bs.dbKeyLayout.CalcBlockCommitKey(h)); err != nil {
+				return 0, ErrDBOpt{Err: err}
+			}
+		}
+		if err := batch.Delete(bs.dbKeyLayout.CalcBlockCommitKey(h)); err != nil {
+			return 0, ErrDBOpt{Err: err}
+		}
+		if err := batch.Delete(bs.dbKeyLayout.CalcSeenCommitKey(h)); err != nil {
+			return 0, ErrDBOpt{Err: err}
+		}
+		if err := batch.Delete(bs.dbKeyLayout.CalcExtCommitKey(h)); err != nil {
+			return 0, ErrDBOpt{Err: err}
+		}
+		if err := batch.Delete(bs.dbKeyLayout.CalcBlockMetaKey(h)); err != nil {
+			return 0, ErrDBOpt{Err: err}
+		}
+	}
+
+	if coldBatch != nil {
+		if err := coldBatch.WriteSync(); err != nil {
+			return 0, ErrDBOpt{Err: err}
+		}
+		if err := batch.Set(coldHeightKey, encodeColdHeight(height+1)); err != nil {
+			return 0, ErrDBOpt{Err: err}
+		}
+	}
+
+	bs.mtx.Lock()
+	defer bs.mtx.Unlock()
+	bs.height = height
+	bs.coldHeight = min(bs.coldHeight, height+1)
+	if err := bs.saveStateAndWriteDB(batch, "failed to delete blocks"); err != nil {
+		return 0, ErrDBOpt{Err: err}
+	}
+	// The caches may hold the removed commits and parts.
+	bs.seenCommitCache.Purge()
+	bs.blockCommitCache.Purge()
+	bs.blockExtendedCommitCache.Purge()
+	bs.blockPartCache.Purge()
+	return latest - height, nil
+}
+
 // addTimeSample returns a function that, when called, adds an observation to m.
 // The observation added to m is the number of seconds elapsed since addTimeSample
 // was initially called. addTimeSample is meant to be called in a defer to calculate
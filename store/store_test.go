@@ -898,6 +898,53 @@ func TestLoadBlockMeta(t *testing.T) {
 	}
 }
 
+func TestSaveBackfilledBlock(t *testing.T) {
+	state, bs, _, _, cleanup, _ := makeStateAndBlockStoreAndIndexers()
+	defer cleanup()
+
+	makeBlock := func(h int64) (*types.Block, *types.PartSet) {
+		block := state.MakeBlock(h, test.MakeNTxs(h, 10), makeTestExtCommit(h-1, cmttime.Now()).ToCommit(), nil,
+			state.Validators.GetProposer().Address)
+		partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		return block, partSet
+	}
+
+	// The first backfilled block sets the base and height of an empty store.
+	block, partSet := makeBlock(10)
+	require.NoError(t, bs.SaveBackfilledBlock(block, partSet, makeTestExtCommit(10, cmttime.Now()).ToCommit()))
+	assert.EqualValues(t, 10, bs.Base())
+	assert.EqualValues(t, 10, bs.Height())
+
+	// Blocks must be backfilled right below the base.
+	block, partSet = makeBlock(8)
+	require.Error(t, bs.SaveBackfilledBlock(block, partSet, makeTestExtCommit(8, cmttime.Now()).ToCommit()))
+	block, partSet = makeBlock(9)
+	require.Error(t, bs.SaveBackfilledBlock(block, partSet, makeTestExtCommit(8, cmttime.Now()).ToCommit()))
+	require.NoError(t, bs.SaveBackfilledBlock(block, partSet, makeTestExtCommit(9, cmttime.Now()).ToCommit()))
+	assert.EqualValues(t, 9, bs.Base())
+	assert.EqualValues(t, 10, bs.Height())
+	loaded, _ := bs.LoadBlock(9)
+	require.NotNil(t, loaded)
+	assert.Equal(t, block.Hash(), loaded.Hash())
+	assert.EqualValues(t, 9, bs.LoadBlockCommit(9).Height)
+
+	// Headers are stored below the base without lowering it.
+	block, partSet = makeBlock(8)
+	meta := types.NewBlockMeta(block, partSet)
+	require.Error(t, bs.SaveBackfilledHeader(meta, makeTestExtCommit(7, cmttime.Now()).ToCommit()))
+	require.NoError(t, bs.SaveBackfilledHeader(meta, makeTestExtCommit(8, cmttime.Now()).ToCommit()))
+	assert.EqualValues(t, 9, bs.Base())
+	assert.Equal(t, meta.BlockID, bs.LoadBlockMeta(8).BlockID)
+	assert.Equal(t, meta.BlockID, bs.LoadBlockMetaByHash(block.Hash()).BlockID)
+	assert.EqualValues(t, 8, bs.LoadBlockCommit(8).Height)
+	loaded, _ = bs.LoadBlock(8)
+	assert.Nil(t, loaded)
+
+	block, partSet = makeBlock(9)
+	require.Error(t, bs.SaveBackfilledHeader(types.NewBlockMeta(block, partSet), makeTestExtCommit(9, cmttime.Now()).ToCommit()))
+}
+
 func TestLoadBlockMetaByHash(t *testing.T) {
 	config := test.ResetTestRoot("blockchain_reactor_test")
 	defer os.RemoveAll(config.RootDir)
@@ -990,3 +1037,245 @@ func initStateStoreRetainHeights(stateStore sm.Store, appBlockRH, dcBlockRH, dcB
 	}
 	return nil
 }
+
+func TestMigrateKeyLayoutToV2(t *testing.T) {
+	state, _, _, _, cleanup, _ := makeStateAndBlockStoreAndIndexers()
+	defer cleanup()
+
+	db := dbm.NewMemDB()
+	bs := NewBlockStore(db, WithDBKeyLayout("v1"))
+	blocks := make([]*types.Block, 0, 5)
+	for h := int64(1); h <= 5; h++ {
+		block := state.MakeBlock(h, test.MakeNTxs(h, 10), makeTestExtCommit(h-1, cmttime.Now()).ToCommit(), nil,
+			state.Validators.GetProposer().Address)
+		partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		bs.SaveBlock(block, partSet, makeTestExtCommit(h, cmttime.Now()).ToCommit())
+		blocks = append(blocks, block)
+	}
+
+	n, err := MigrateKeyLayoutToV2(db, 3)
+	require.NoError(t, err)
+	assert.Positive(t, n)
+	version, err := db.Get([]byte("version"))
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(version))
+
+	// A migrated database is left untouched.
+	n, err = MigrateKeyLayoutToV2(db, 3)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	bs = NewBlockStore(db)
+	assert.Equal(t, "v2", bs.GetVersion())
+	assert.EqualValues(t, 1, bs.Base())
+	assert.EqualValues(t, 5, bs.Height())
+	for _, block := range blocks {
+		loaded, _ := bs.LoadBlock(block.Height)
+		require.NotNil(t, loaded, "height %d", block.Height)
+		assert.Equal(t, block.Hash(), loaded.Hash())
+		assert.Equal(t, block.Hash(), bs.LoadBlockMetaByHash(block.Hash()).BlockID.Hash)
+		assert.EqualValues(t, block.Height, bs.LoadSeenCommit(block.Height).Height)
+	}
+	assert.EqualValues(t, 4, bs.LoadBlockCommit(4).Height)
+
+	// A store cannot be opened while its migration is incomplete.
+	require.NoError(t, db.Set([]byte("version"), []byte(KeyLayoutMigratingToV2)))
+	assert.Panics(t, func() { NewBlockStore(db) })
+}
+
+func TestDeleteBlocksAbove(t *testing.T) {
+	state, bs, _, _, cleanup, _ := makeStateAndBlockStoreAndIndexers()
+	defer cleanup()
+
+	blocks := make([]*types.Block, 0, 5)
+	for h := int64(1); h <= 5; h++ {
+		block := state.MakeBlock(h, test.MakeNTxs(h, 10), makeTestExtCommit(h-1, cmttime.Now()).ToCommit(), nil,
+			state.Validators.GetProposer().Address)
+		partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		bs.SaveBlock(block, partSet, makeTestExtCommit(h, cmttime.Now()).ToCommit())
+		blocks = append(blocks, block)
+	}
+	// Fill the caches.
+	require.NotNil(t, bs.LoadSeenCommit(4))
+	require.NotNil(t, bs.LoadBlockPart(4, 0))
+
+	n, err := bs.DeleteBlocksAbove(5)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	n, err = bs.DeleteBlocksAbove(2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, n)
+	assert.EqualValues(t, 1, bs.Base())
+	assert.EqualValues(t, 2, bs.Height())
+	for _, block := range blocks[2:] {
+		assert.Nil(t, bs.LoadBlockMeta(block.Height))
+		assert.Nil(t, bs.LoadBlockMetaByHash(block.Hash()))
+		assert.Nil(t, bs.LoadBlockPart(block.Height, 0))
+		assert.Nil(t, bs.LoadSeenCommit(block.Height))
+	}
+	loaded, _ := bs.LoadBlock(2)
+	require.NotNil(t, loaded)
+	assert.Equal(t, blocks[1].Hash(), loaded.Hash())
+
+	_, err = bs.DeleteBlocksAbove(0)
+	require.ErrorAs(t, err, &ErrExceedBaseHeight{})
+}
+
+func TestMoveBlocksToColdStore(t *testing.T) {
+	state, _, _, _, cleanup, _ := makeStateAndBlockStoreAndIndexers()
+	defer cleanup()
+	db, coldDB := dbm.NewMemDB(), dbm.NewMemDB()
+	bs := NewBlockStore(db, WithColdStore(coldDB))
+
+	_, err := NewBlockStore(dbm.NewMemDB()).MoveBlocksToColdStore(1)
+	require.ErrorIs(t, err, ErrNoColdStore)
+
+	blocks := make([]*types.Block, 0, 10)
+	for h := int64(1); h <= 10; h++ {
+		block := state.MakeBlock(h, test.MakeNTxs(h, 10), makeTestExtCommit(h-1, cmttime.Now()).ToCommit(), nil,
+			state.Validators.GetProposer().Address)
+		partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		bs.SaveBlock(block, partSet, makeTestExtCommit(h, cmttime.Now()).ToCommit())
+		blocks = append(blocks, block)
+	}
+
+	n, err := bs.MoveBlocksToColdStore(6)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, n)
+	assert.EqualValues(t, 6, bs.ColdHeight())
+	hot, err := db.Get(bs.dbKeyLayout.CalcBlockPartKey(1, 0))
+	require.NoError(t, err)
+	assert.Nil(t, hot)
+
+	// Moving again does not move the same blocks twice.
+	n, err = bs.MoveBlocksToColdStore(6)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	// The cold height is persisted and the moved blocks are loaded from the
+	// cold database.
+	bs = NewBlockStore(db, WithColdStore(coldDB))
+	assert.EqualValues(t, 6, bs.ColdHeight())
+	for _, block := range blocks {
+		loaded, _ := bs.LoadBlock(block.Height)
+		require.NotNil(t, loaded, "height %d", block.Height)
+		assert.Equal(t, block.Hash(), loaded.Hash())
+	}
+	for h := int64(1); h < 6; h++ {
+		require.NotNil(t, bs.LoadBlockCommit(h), "height %d", h)
+	}
+
+	// Pruning removes the blocks from the cold database as well.
+	pruned, _, err := bs.PruneBlocks(4, state)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, pruned)
+	cold, err := coldDB.Get(bs.dbKeyLayout.CalcBlockPartKey(3, 0))
+	require.NoError(t, err)
+	assert.Nil(t, cold)
+	loaded, _ := bs.LoadBlock(4)
+	require.NotNil(t, loaded)
+
+	// Deleting the latest blocks lowers the cold height.
+	_, err = bs.DeleteBlocksAbove(4)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, bs.ColdHeight())
+	cold, err = coldDB.Get(bs.dbKeyLayout.CalcBlockPartKey(5, 0))
+	require.NoError(t, err)
+	assert.Nil(t, cold)
+}
+
+func TestPruningServiceMovesBlocksToColdStore(t *testing.T) {
+	state, _, txIndexer, blockIndexer, cleanup, stateStore := makeStateAndBlockStoreAndIndexers()
+	defer cleanup()
+	bs := NewBlockStore(dbm.NewMemDB(), WithColdStore(dbm.NewMemDB()))
+	require.NoError(t, initStateStoreRetainHeights(stateStore, 0, 0, 0))
+
+	now := cmttime.Now()
+	for h := int64(1); h <= 10; h++ {
+		// Blocks 1 to 5 are an hour old.
+		blockTime := now
+		if h <= 5 {
+			blockTime = now.Add(-time.Hour)
+		}
+		block := state.MakeBlock(h, test.MakeNTxs(h, 10), new(types.Commit), nil, state.Validators.GetProposer().Address)
+		block.Time = blockTime
+		partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		bs.SaveBlock(block, partSet, makeTestExtCommit(h, blockTime).ToCommit())
+	}
+
+	pruner := sm.NewPruner(
+		stateStore,
+		bs,
+		blockIndexer,
+		txIndexer,
+		log.TestingLogger(),
+		sm.WithPrunerInterval(10*time.Millisecond),
+		sm.WithPrunerColdStorage(bs, 2, time.Minute),
+	)
+	require.NoError(t, pruner.Start())
+	defer func() {
+		require.NoError(t, pruner.Stop())
+	}()
+
+	// Blocks 6 to 8 are more than 2 blocks behind but not old enough.
+	require.Eventually(t, func() bool {
+		return bs.ColdHeight() == 6
+	}, time.Second, 10*time.Millisecond)
+	for h := int64(1); h <= 10; h++ {
+		block, _ := bs.LoadBlock(h)
+		require.NotNil(t, block, "height %d", h)
+	}
+}
+
+func TestBlockStoreCompression(t *testing.T) {
+	state, _, _, _, cleanup, _ := makeStateAndBlockStoreAndIndexers()
+	defer cleanup()
+	db := dbm.NewMemDB()
+	bs := NewBlockStore(db, WithCompression("zstd"))
+
+	blocks := make([]*types.Block, 0, 4)
+	for h := int64(1); h <= 4; h++ {
+		// Blocks 1 and 2 are compressed, 3 and 4 are not.
+		if h == 3 {
+			bs = NewBlockStore(db, WithCompression("none"))
+		}
+		block := state.MakeBlock(h, test.MakeNTxs(h, 10), makeTestExtCommit(h-1, cmttime.Now()).ToCommit(), nil,
+			state.Validators.GetProposer().Address)
+		partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		bs.SaveBlock(block, partSet, makeTestExtCommit(h, cmttime.Now()).ToCommit())
+		blocks = append(blocks, block)
+	}
+
+	compressed, err := db.Get(bs.dbKeyLayout.CalcBlockPartKey(1, 0))
+	require.NoError(t, err)
+	uncompressed, err := db.Get(bs.dbKeyLayout.CalcBlockPartKey(3, 0))
+	require.NoError(t, err)
+	assert.Less(t, len(compressed), len(uncompressed))
+
+	checkBlocks := func(bs *BlockStore) {
+		t.Helper()
+		for _, block := range blocks {
+			loaded, _ := bs.LoadBlock(block.Height)
+			require.NotNil(t, loaded, "height %d", block.Height)
+			assert.Equal(t, block.Hash(), loaded.Hash())
+		}
+	}
+	checkBlocks(NewBlockStore(db))
+
+	bs = NewBlockStore(db, WithCompression("zstd"))
+	n, err := bs.RecompressBlockParts(1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+	n, err = bs.RecompressBlockParts(1)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	checkBlocks(NewBlockStore(db))
+
+	assert.Panics(t, func() { NewBlockStore(db, WithCompression("gzip")) })
+}